@@ -0,0 +1,13 @@
+// Command gnome-shortcuts prints the GNOME keyboard shortcuts that
+// actually fire; see package shortcuts.
+package main
+
+import (
+	"os"
+
+	"github.com/temirov/gnome_shortcuts/pkg/shortcuts"
+)
+
+func main() {
+	os.Exit(shortcuts.Main(os.Args[1:]))
+}
@@ -2,9 +2,9 @@ module github.com/temirov/gnome_shortcuts
 
 go 1.24.2
 
-require github.com/manifoldco/promptui v0.9.0
+require github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e
 
 require (
-	github.com/chzyer/readline v0.0.0-20180603132655-2972be24d48e // indirect
+	github.com/chzyer/test v1.0.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
 )
@@ -0,0 +1,53 @@
+package shortcuts
+
+import "strings"
+
+/*──────────────── accessibility ─────────────────
+
+Screen reader, magnifier, contrast and text size
+chords live in the media-keys schema; they are
+collected with or without -include-media-keys
+and grouped as "Accessibility".
+
+Sticky and slow keys are toggled by Shift
+gestures, not chords.  They are listed (while
+org.gnome.desktop.a11y.keyboard enable is on) but
+claim nothing.
+*/
+
+const a11yApp = "Accessibility"
+
+var a11yMediaKeys = map[string]string{
+	"screenreader":       "Screen Reader",
+	"magnifier":          "Magnifier",
+	"magnifier-zoom-in":  "Magnifier Zoom In",
+	"magnifier-zoom-out": "Magnifier Zoom Out",
+	"on-screen-keyboard": "On-screen Keyboard",
+	"increase-text-size": "Increase Text Size",
+	"decrease-text-size": "Decrease Text Size",
+	"toggle-contrast":    "High Contrast",
+}
+
+// a11yAction names an accessibility key of the media-keys schema.
+func a11yAction(schema, key string) (string, bool) {
+	if !isMediaSchema(schema, key) {
+		return "", false
+	}
+	a, ok := a11yMediaKeys[strings.TrimSuffix(key, "-static")]
+	return a, ok
+}
+
+const a11yKeyboard = "org.gnome.desktop.a11y.keyboard"
+
+// a11yGestures are the keyboard toggles; vals holds "schema key" values.
+func a11yGestures(vals map[string]string) []row {
+	if vals[a11yKeyboard+" enable"] != "true" {
+		return nil
+	}
+	return []row{
+		{accel: "Shift ×5", app: a11yApp, action: "Toggle Sticky Keys",
+			rank: 2, order: 1 << 20, schema: a11yKeyboard, key: "stickykeys-enable"},
+		{accel: "Shift held 8 s", app: a11yApp, action: "Toggle Slow Keys",
+			rank: 2, order: 1 << 20, schema: a11yKeyboard, key: "slowkeys-enable"},
+	}
+}
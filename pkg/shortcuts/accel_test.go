@@ -0,0 +1,57 @@
+package shortcuts
+
+import "testing"
+
+func TestParseAccel(t *testing.T) {
+	for _, c := range []struct {
+		in, want string
+		ok       bool
+	}{
+		{"<Primary>q", "<Control>q", true},
+		{"<Control>q", "<Control>q", true},
+		{"<Ctrl>Q", "<Control>q", true},
+		{"<ctl><shft>q", "<Control><Shift>q", true},
+		{"<Shift><Primary>q", "<Control><Shift>q", true},
+		{"<Super><Alt><Control>Left", "<Control><Alt><Super>Left", true},
+		{"<Mod4>Return", "<Super>Return", true},
+		{"<Mod1>F4", "<Alt>F4", true},
+		{"<Super>+Left", "<Super>Left", true},
+		{"<Primary><Primary>a", "<Control>a", true},
+		{"<Super>", "<Super>", true},
+		{"Page_Up", "Page_Up", true},
+		{"", "", false},
+		{"disabled", "", false},
+		{"<Bogus>q", "", false},
+		{"<Super", "", false},
+		{"<Super>two words", "", false},
+	} {
+		a, ok := parseAccel(c.in)
+		if ok != c.ok || ok && a.spec() != c.want {
+			t.Errorf("parseAccel(%q) = %q, %v; want %q, %v", c.in, a.spec(), ok, c.want, c.ok)
+		}
+	}
+}
+
+// TestClaimChordNearDup files <Primary>q and <Control>q under one
+// chord and reports them as written differently; one spelling twice
+// is no near-duplicate.
+func TestClaimChordNearDup(t *testing.T) {
+	chosen := map[string]row{}
+	res := gschemaResolver{}
+	a := row{spec: "<Primary>q", key: "a", rank: 0}
+	b := row{spec: "<Control>q", key: "b", rank: 1}
+	c := row{spec: "<Primary>q", key: "c", rank: 2}
+	if _, ok := claimChord(chosen, res, a); ok {
+		t.Error("first claimant reported as a near-duplicate")
+	}
+	d, ok := claimChord(chosen, res, b)
+	if !ok || d.a.key != "a" || d.b.key != "b" {
+		t.Errorf("<Control>q after <Primary>q: near-dup %v (%s, %s), want a and b", ok, d.a.key, d.b.key)
+	}
+	if len(chosen) != 1 || chosen["<Control>q"].key != "a" || len(chosen["<Control>q"].lost) != 1 {
+		t.Errorf("chosen = %+v, want one chord held by a, b lost", chosen)
+	}
+	if d, ok := claimChord(chosen, res, c); !ok || d.a.key != "b" {
+		t.Errorf("<Primary>q again: near-dup %v with %q, want it matched to b's <Control>q", ok, d.a.key)
+	}
+}
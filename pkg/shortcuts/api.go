@@ -0,0 +1,284 @@
+package shortcuts
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+/*────────────────── library API ──────────────────
+
+What cmd/gnome-shortcuts does, for other programs
+such as status-bar applets.  Options are applied
+to the package state the command line uses, with
+the display flags (-keys, -sep, -mod-order) at
+their defaults, for the length of a call; calls
+hold a lock while they do, so goroutines may share
+the package but take turns.  Nothing here exits or
+prints: errors are returned, and warnings go to
+Options.Warnings.
+*/
+
+// apiMu serialises the calls below, which swap package state.
+var apiMu sync.Mutex
+
+// Layout picks the modifier names used in Binding.Accel.
+type Layout int
+
+const (
+	LayoutPC     Layout = iota // Win, Alt
+	LayoutApple                // Command, Option
+	LayoutChrome               // Search
+)
+
+func (l Layout) kb() kb {
+	switch l {
+	case LayoutApple:
+		return kbApple
+	case LayoutChrome:
+		return kbChrome
+	}
+	return kbPC
+}
+
+// Options mirror the command line's collection flags; the zero value
+// reads the running GNOME session.
+type Options struct {
+	Layout       Layout
+	Desktop      string // "gnome" (default), "sway", "i3", "hyprland" or "kde"
+	ConfigFile   string // config of a Desktop other than gnome; "" for its usual place
+	Resolver     string // conflict strategy; "" for gschema
+	Defaults     bool   // schema defaults only, no gsettings calls
+	IncludeMedia bool   // XF86 media and Fn keys
+	IncludeApps  bool   // app-local shortcuts (Files, GTK accels)
+	NoTerminals  bool   // leave out GNOME Terminal and Console
+	Host         string // user@machine, collected over ssh
+	Glyphs       bool   // ⏎ ← ␣ instead of Enter, Left, Space
+
+	// Positions names layouts ("dvorak", "fr") whose moved keys fill
+	// Binding.Position, as list -positions does.
+	Positions []string
+
+	// Diagnose, if set, hears what collection worked around: a
+	// *SchemaNotFoundError, *GSettingsUnavailableError or *ParseError.
+	Diagnose func(error)
+
+	// Warnings, if set, gets what the command line prints to stderr
+	// without -verbose: a config file that will not open, a failing
+	// collector.  Nil discards them.
+	Warnings io.Writer
+}
+
+// Binding is one shortcut: the claimant that fires and those it shadows.
+type Binding struct {
+	Accel    string    `json:"shortcut"` // formatted for Options.Layout
+	Spec     string    `json:"spec"`     // GTK accelerator, "<Super>h"
+	App      string    `json:"app"`
+	Action   string    `json:"action"`
+	Schema   string    `json:"schema,omitempty"`
+	Key      string    `json:"key,omitempty"`
+	File     string    `json:"file,omitempty"` // file:line for desktops configured by file
+	Rank     int       `json:"rank"`           // lower fires first: XKB -2, core -1, WM 0 … apps 4
+	Locked   bool      `json:"locked,omitempty"`
+	Source   string    `json:"source,omitempty"`   // list -source: "user", "system:<db>" or "default"
+	Favorite bool      `json:"favorite,omitempty"` // listed in config.toml
+	Position string    `json:"position,omitempty"` // list -positions: "Dvorak: K position"
+	Shadowed []Binding `json:"shadowed,omitempty"`
+}
+
+// collectState is the package state Options stand for.
+type collectState struct {
+	desktop, wmConfig, resolver, host string
+	defaults, media, apps, terminals  bool
+	diagnose                          func(error)
+	warnings                          io.Writer
+	diagnosed                         map[string]bool // what was said once
+	remoteWarned                      bool
+}
+
+func currentState() collectState {
+	return collectState{desktopOpt, wmConfig, resolverName, hostOpt,
+		defaultsOnly, includeMedia, includeApps, includeTerminals, diagnoseFn, warnOut,
+		diagnosed, remoteWarned}
+}
+
+func (s collectState) set() {
+	desktopOpt, wmConfig, resolverName, hostOpt = s.desktop, s.wmConfig, s.resolver, s.host
+	defaultsOnly, includeMedia, includeApps, includeTerminals = s.defaults, s.media, s.apps, s.terminals
+	diagnoseFn, warnOut, diagnosed, remoteWarned = s.diagnose, s.warnings, s.diagnosed, s.remoteWarned
+}
+
+// displayState is the package state the display flags set.
+type displayState struct {
+	keys, sep, modOrder, lang string
+	glyphs                    bool
+}
+
+func currentDisplay() displayState {
+	return displayState{keyStyle, accelSep, modOrder, langOpt, glyphsOnMod}
+}
+
+func (s displayState) set() {
+	keyStyle, accelSep, modOrder, langOpt, glyphsOnMod = s.keys, s.sep, s.modOrder, s.lang, s.glyphs
+}
+
+// enter takes apiMu and puts the display flags at their defaults
+// for a call; leave undoes both.
+func enter() (leave func()) {
+	apiMu.Lock()
+	saved := currentDisplay()
+	displayState{"auto", " + ", "canonical", "", false}.set()
+	return func() {
+		saved.set()
+		apiMu.Unlock()
+	}
+}
+
+// apply sets the package state for o and returns a func restoring it.
+func (o Options) apply() (restore func(), err error) {
+	s := collectState{o.Desktop, o.ConfigFile, o.Resolver, o.Host,
+		o.Defaults, o.IncludeMedia, o.IncludeApps, !o.NoTerminals, o.Diagnose, o.Warnings,
+		map[string]bool{}, false} // each call hears every warning
+	if s.warnings == nil {
+		s.warnings = io.Discard
+	}
+	if s.desktop == "" {
+		s.desktop = "gnome"
+	}
+	if s.resolver == "" {
+		s.resolver = "gschema"
+	}
+	if _, ok := desktops[s.desktop]; !ok && s.desktop != "gnome" {
+		return nil, fmt.Errorf("desktop %q: want one of %s", s.desktop, strings.Join(desktopNames(), ", "))
+	}
+	if _, ok := resolvers[s.resolver]; !ok {
+		return nil, fmt.Errorf("resolver %q: want one of %s", s.resolver, resolverNames())
+	}
+	if err := checkHost(s.host); err != nil {
+		return nil, err
+	}
+	saved := currentState()
+	s.set()
+	return saved.set, nil
+}
+
+func (o Options) labels() map[string]string {
+	if o.Glyphs {
+		return labelsFor(o.Layout.kb(), "md")
+	}
+	return labelsFor(o.Layout.kb(), "text")
+}
+
+// Collect reads and resolves the shortcuts, sorted the way list
+// prints them.
+func Collect(ctx context.Context, opts Options) ([]Binding, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	defer enter()()
+	restore, err := opts.apply()
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	rows, _, err := collectContext(ctx, opts.labels())
+	if err != nil {
+		return nil, err
+	}
+	sortRows(rows)
+	return toBindings(rows, opts.Positions), nil
+}
+
+// Resolve picks, for every chord bound more than once, the binding that
+// fires under strategy ("" for gschema); the others move into its
+// Shadowed list.  Bindings keep their Rank, and their order breaks ties.
+// An empty Accel is filled in with PC modifier names.
+func Resolve(bs []Binding, strategy string) ([]Binding, error) {
+	if strategy == "" {
+		strategy = "gschema"
+	}
+	mk, ok := resolvers[strategy]
+	if !ok {
+		return nil, fmt.Errorf("resolver %q: want one of %s", strategy, resolverNames())
+	}
+	defer enter()()
+	restore, err := Options{Resolver: strategy}.apply() // warnings discarded
+	if err != nil {
+		return nil, err
+	}
+	defer restore()
+	res := mk()
+	lbl := labelsFor(kbPC, "text")
+	chosen := map[string]row{}
+	pos := map[string]string{} // spec (or accel) → Position, which rows do not keep
+	n := 0
+	var add func(b Binding)
+	add = func(b Binding) {
+		r := b.row()
+		if b.Position != "" {
+			pos[cmp.Or(b.Spec, b.Accel)] = b.Position
+		}
+		if r.accel == "" {
+			r.accel, _ = fmtKey(r.spec, lbl)
+		}
+		r.order = n
+		n++
+		claimChord(chosen, res, r)
+		for _, s := range b.Shadowed {
+			add(s)
+		}
+	}
+	for _, b := range bs {
+		add(b)
+	}
+	rows := make([]row, 0, len(chosen))
+	for _, r := range chosen {
+		rows = append(rows, r)
+	}
+	sortRows(rows)
+	out := toBindings(rows, nil)
+	var fill func(bs []Binding)
+	fill = func(bs []Binding) {
+		for i := range bs {
+			bs[i].Position = pos[cmp.Or(bs[i].Spec, bs[i].Accel)]
+			fill(bs[i].Shadowed)
+		}
+	}
+	fill(out)
+	return out, nil
+}
+
+// toBindings turns rows into Bindings, Position naming the moved keys
+// of layouts.
+func toBindings(rows []row, layouts []string) []Binding {
+	out := make([]Binding, len(rows))
+	for i, r := range rows {
+		out[i] = Binding{Accel: r.accel, Spec: r.spec, App: r.app, Action: r.action,
+			Schema: r.schema, Key: r.key, File: r.src, Rank: r.rank, Locked: r.locked,
+			Source: r.source, Favorite: favorite(r.spec), Position: positions(r.pressed(), layouts),
+			Shadowed: toBindings(r.lost, layouts)}
+		if len(r.lost) == 0 {
+			out[i].Shadowed = nil
+		}
+	}
+	return out
+}
+
+func (b Binding) row() row {
+	return row{accel: b.Accel, app: b.App, action: b.Action, rank: b.Rank,
+		spec: b.Spec, schema: b.Schema, key: b.Key, src: b.File, locked: b.Locked, source: b.Source}
+}
+
+// Render writes bs as "text" (what list prints), "json", "md" or a
+// format added with RegisterRenderer.
+func Render(ctx context.Context, w io.Writer, format string, bs []Binding) error {
+	defer enter()()
+	r, err := rendererFor(format)
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+	return r.Render(ctx, w, []Section{{Bindings: bs}})
+}
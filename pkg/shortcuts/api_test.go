@@ -0,0 +1,61 @@
+package shortcuts
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestCollectWarnings runs the fixture through a runtime resolver whose
+// observed.json is not JSON: the warning goes to Options.Warnings,
+// every call, and none to stderr.
+func TestCollectWarnings(t *testing.T) {
+	dir := filepath.Join("testdata", "golden", "gnome46-wayland")
+	state := t.TempDir()
+	t.Setenv("XDG_STATE_HOME", state)
+	if err := os.MkdirAll(filepath.Join(state, "gnome-shortcuts"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(observedPath(), []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	savedDump, savedDir, savedCache := dumpOpt, schemaDirOpt, schemaCache
+	t.Cleanup(func() { dumpOpt, schemaDirOpt, schemaCache = savedDump, savedDir, savedCache })
+	d, err := readDump(filepath.Join(dir, "dump.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpOpt, schemaDirOpt, schemaCache = d, filepath.Join(dir, "schemas"), map[string]*schemaInfo{}
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	savedStderr := os.Stderr
+	os.Stderr = w
+	var bufs [2]bytes.Buffer
+	for i := range bufs {
+		if _, err := Collect(context.Background(), Options{Resolver: "runtime", Warnings: &bufs[i]}); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := Resolve([]Binding{{Spec: "<Super>a"}, {Spec: "<Super>a", Action: "b"}}, "runtime"); err != nil {
+		t.Error(err)
+	}
+	os.Stderr = savedStderr
+	w.Close()
+	stderr, _ := io.ReadAll(r)
+
+	for i, b := range bufs {
+		if !strings.Contains(b.String(), "runtime resolver: "+observedPath()) {
+			t.Errorf("call %d: Warnings = %q, want the runtime resolver's", i+1, b.String())
+		}
+	}
+	if len(stderr) > 0 {
+		t.Errorf("stderr = %q, want nothing", stderr)
+	}
+}
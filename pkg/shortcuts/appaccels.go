@@ -0,0 +1,139 @@
+package shortcuts
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*─────────── per-application accelerators ───────
+
+Sources:
+  ~/.config/<app>/accels   GTK accel map dumps; lines
+                           starting with ";" are defaults
+                           the user has not changed, but
+                           they are live all the same
+  gnome-terminal           relocatable keybinding schema
+*/
+
+type appAccel struct{ app, action, spec string }
+
+var accelLineRE = regexp.MustCompile(`^;?\s*\(gtk_accel_path\s+"([^"]*)"\s+"([^"]*)"\)`)
+
+const terminalKeys = "org.gnome.Terminal.Legacy.Keybindings:/org/gnome/terminal/legacy/keybindings/"
+
+func appAccels() []appAccel {
+	out := gtkAccels()
+	for _, e := range parseDump(gsettingsDump(terminalKeys)) {
+		spec := gvTextString(e.val)
+		if spec == "" || spec == "disabled" {
+			continue
+		}
+		out = append(out, appAccel{"Terminal", humanise(e.key), spec})
+	}
+	return out
+}
+
+// gtkAccels reads the accel maps GTK 2/3 apps dump into ~/.config.
+func gtkAccels() []appAccel {
+	if remote() || fromDump() {
+		return nil
+	}
+	var out []appAccel
+	cfg, _ := os.UserConfigDir()
+	files, _ := filepath.Glob(filepath.Join(cfg, "*", "accels"))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		app := humanise(filepath.Base(filepath.Dir(f)))
+		sc := bufio.NewScanner(bytes.NewReader(data))
+		for sc.Scan() {
+			m := accelLineRE.FindStringSubmatch(sc.Text())
+			if m == nil || m[2] == "" {
+				continue
+			}
+			// "<Actions>/GeditWindowActions/FileSave" → "File Save"
+			act := m[1][strings.LastIndexByte(m[1], '/')+1:]
+			out = append(out, appAccel{app, humanise(splitCamel(act)), m[2]})
+		}
+	}
+	return out
+}
+
+// splitCamel turns "FileSaveAs" into "File_Save_As".
+func splitCamel(s string) string {
+	var b strings.Builder
+	for i, r := range s {
+		if i > 0 && r >= 'A' && r <= 'Z' {
+			b.WriteByte('_')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+/*──────────────── steals command ────────────────*/
+
+func init() {
+	commands["steals"] = command{
+		help:  "system shortcuts that take chords away from applications",
+		flags: collectFlags,
+		run:   runSteals,
+	}
+}
+
+func runSteals([]string) error {
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sys := map[string]row{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok && r.rank < appRank {
+			sys[a.spec()] = r
+		}
+	}
+
+	type steal struct {
+		sys row
+		app appAccel
+	}
+	var hits []steal
+	for _, aa := range appAccels() {
+		a, ok := parseAccel(aa.spec)
+		if !ok {
+			continue
+		}
+		if r, ok := sys[a.spec()]; ok {
+			hits = append(hits, steal{r, aa})
+		}
+	}
+	if len(hits) == 0 {
+		fmt.Println("No application accelerators are shadowed by system shortcuts.")
+		return nil
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].app.app != hits[j].app.app {
+			return hits[i].app.app < hits[j].app.app
+		}
+		return hits[i].sys.accel < hits[j].sys.accel
+	})
+
+	line := strings.Repeat("─", 100)
+	fmt.Println(line)
+	fmt.Printf("%-22s %-36s %s\n", "Shortcut", "Taken by", "Never reaches")
+	fmt.Println(line)
+	for _, h := range hits {
+		fmt.Printf("%-22s %-36s %s\n", h.sys.accel,
+			h.sys.app+": "+h.sys.action, h.app.app+": "+h.app.action)
+	}
+	return nil
+}
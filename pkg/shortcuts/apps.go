@@ -0,0 +1,87 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*────────────── core app inventory ──────────────
+
+Hard-coded shortcuts of Files, Settings and Text
+Editor (app_shortcuts.tsv) and the GTK accel maps
+in ~/.config/<app>/accels, listed with -include-apps.
+Like the terminals they are app local: system
+bindings shadow them, other apps do not.
+*/
+
+var includeApps bool
+
+//go:embed app_shortcuts.tsv
+var appShortcutsTSV string
+
+type appBind struct{ app, schema, spec, action string }
+
+func parseAppBinds(file, data string) []appBind {
+	var out []appBind
+	var app, schema string
+	for n, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		if strings.HasPrefix(l, "[") {
+			f := strings.Split(strings.Trim(l, "[]"), "\t")
+			app, schema = f[0], ""
+			if len(f) > 1 {
+				schema = f[1]
+			}
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 2 || app == "" {
+			badLine(file, n, "want spec<TAB>action under an [app] line, got %q", l)
+			continue
+		}
+		out = append(out, appBind{app, schema, f[0], f[1]})
+	}
+	return out
+}
+
+func loadAppShortcuts() []appBind {
+	cfg, _ := os.UserConfigDir()
+	file := filepath.Join(cfg, "gnome-shortcuts", "app_shortcuts.tsv")
+	if data, err := os.ReadFile(file); err == nil {
+		return parseAppBinds(file, string(data))
+	}
+	return parseAppBinds("app_shortcuts.tsv", appShortcutsTSV)
+}
+
+func appRows(lbl map[string]string) []row {
+	if !includeApps {
+		return nil
+	}
+	var out []row
+	installed := map[string]bool{}
+	for i, b := range loadAppShortcuts() {
+		ok, seen := installed[b.schema]
+		if !seen {
+			ok = b.schema == "" || lookupSchema(b.schema).file != ""
+			installed[b.schema] = ok
+		}
+		if !ok {
+			continue
+		}
+		if acc, ok := fmtAccel(b.spec, lbl); ok {
+			out = append(out, row{accel: acc, app: b.app, action: b.action,
+				rank: appRank, order: i, spec: b.spec})
+		}
+	}
+	for i, a := range gtkAccels() {
+		if acc, ok := fmtAccel(a.spec, lbl); ok {
+			out = append(out, row{accel: acc, app: a.app, action: a.action,
+				rank: appRank, order: i, spec: a.spec})
+		}
+	}
+	return out
+}
@@ -0,0 +1,175 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+/*──────────── keyboard-only audit ───────────────
+
+Lists actions that have no binding at all, i.e.
+are reachable only by pointer or gesture.  The
+essential list below covers what a user who cannot
+use a pointer needs to operate the desktop.  An
+essential action whose every chord another binding
+takes is no more reachable, so it is listed too.
+*/
+
+type essential struct{ schema, key, why string }
+
+var essentials = []essential{
+	{"org.gnome.desktop.wm.keybindings", "close", "close the focused window"},
+	{"org.gnome.desktop.wm.keybindings", "switch-applications", "move between applications"},
+	{"org.gnome.desktop.wm.keybindings", "switch-windows", "move between windows"},
+	{"org.gnome.desktop.wm.keybindings", "activate-window-menu", "window menu (move, resize, always on top)"},
+	{"org.gnome.desktop.wm.keybindings", "toggle-maximized", "maximise / restore"},
+	{"org.gnome.desktop.wm.keybindings", "minimize", "hide a window"},
+	{"org.gnome.desktop.wm.keybindings", "begin-move", "move a window"},
+	{"org.gnome.desktop.wm.keybindings", "begin-resize", "resize a window"},
+	{"org.gnome.desktop.wm.keybindings", "switch-to-workspace-left", "previous workspace"},
+	{"org.gnome.desktop.wm.keybindings", "switch-to-workspace-right", "next workspace"},
+	{"org.gnome.desktop.wm.keybindings", "switch-panels", "reach the top bar and dialogs"},
+	{"org.gnome.shell.keybindings", "toggle-overview", "open the overview"},
+	{"org.gnome.shell.keybindings", "toggle-message-tray", "read notifications"},
+	{"org.gnome.shell.keybindings", "focus-active-notification", "act on a notification"},
+	{"org.gnome.shell.keybindings", "toggle-quick-settings", "network, sound, power settings"},
+	{"org.gnome.settings-daemon.plugins.media-keys", "screenreader", "toggle the screen reader"},
+	{"org.gnome.settings-daemon.plugins.media-keys", "magnifier", "toggle the magnifier"},
+	{"org.gnome.settings-daemon.plugins.media-keys", "on-screen-keyboard", "toggle the on-screen keyboard"},
+	{"org.gnome.settings-daemon.plugins.media-keys", "screensaver", "lock the screen"},
+	{"org.gnome.settings-daemon.plugins.media-keys", "logout", "log out"},
+}
+
+var auditOpt struct{ all bool }
+
+func init() {
+	commands["audit"] = command{
+		help: "actions with no keyboard binding (pointer/gesture only)",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&auditOpt.all, "all", false, "also list every other unbound action")
+			collectFlags(fs)
+		},
+		run: runAudit,
+	}
+}
+
+// hasBinding reports whether a gsettings value holds at least one
+// usable accelerator.
+func hasBinding(val string) bool {
+	for _, v := range gvTextStrings(val) {
+		if v != "" && v != "disabled" {
+			return true
+		}
+	}
+	return false
+}
+
+func runAudit([]string) error {
+	if desktopOpt != "gnome" {
+		return fmt.Errorf("audit: reads GNOME settings; -desktop %s has no schemas to audit", desktopOpt)
+	}
+	bound := map[string]bool{}
+	var unbound []entry
+	for _, e := range dumpAll() {
+		if !strings.Contains(e.schema, "keybinding") && !strings.HasSuffix(e.schema, ".media-keys") {
+			continue
+		}
+		if hasBinding(e.val) {
+			bound[e.schema+" "+e.key] = true
+		} else {
+			unbound = append(unbound, e)
+		}
+	}
+	known := map[string]bool{}
+	for _, e := range unbound {
+		known[e.schema+" "+e.key] = true
+	}
+
+	// which bound keys hold a chord, and who took the others'
+	saved := includeMedia
+	includeMedia = true
+	rows, _, err := collect(labels("text"))
+	includeMedia = saved
+	if err != nil {
+		return err
+	}
+	fires := map[string]bool{}
+	takenBy := map[string]row{}
+	for _, r := range rows {
+		fires[r.schema+" "+r.key] = true
+		for _, l := range r.lost {
+			takenBy[l.schema+" "+l.key] = r
+		}
+	}
+
+	var missing, shadowed []essential
+	for _, e := range essentials {
+		k := e.schema + " " + e.key
+		switch {
+		case known[k]:
+			missing = append(missing, e)
+		case bound[k] && !fires[k]:
+			shadowed = append(shadowed, e)
+		}
+	}
+	if len(missing)+len(shadowed) == 0 {
+		fmt.Println("Every essential action has a keyboard binding.")
+	}
+	if len(missing) > 0 {
+		fmt.Println("Essential actions reachable only by pointer or gesture:")
+		fmt.Println()
+		for _, e := range missing {
+			app, _ := classify(e.schema, e.key)
+			fmt.Printf("  %-16s %-28s %s\n", app, humanise(e.key), e.why)
+		}
+	}
+	if len(shadowed) > 0 {
+		if len(missing) > 0 {
+			fmt.Println()
+		}
+		fmt.Println("Essential actions whose keys another binding takes:")
+		fmt.Println()
+		for _, e := range shadowed {
+			app, _ := classify(e.schema, e.key)
+			by := "never fires"
+			if r, ok := takenBy[e.schema+" "+e.key]; ok {
+				by = fmt.Sprintf("%s goes to %s: %s", r.accel, r.app, r.action)
+			}
+			fmt.Printf("  %-16s %-28s %s\n", app, humanise(e.key), by)
+		}
+	}
+
+	if !auditOpt.all {
+		if n := len(unbound) - len(missing); n > 0 {
+			fmt.Printf("\n%d other actions are unbound (-all lists them).\n", n)
+		}
+		return nil
+	}
+	ess := map[string]bool{}
+	for _, e := range missing {
+		ess[e.schema+" "+e.key] = true
+	}
+	sort.Slice(unbound, func(i, j int) bool {
+		ai, ri := classify(unbound[i].schema, unbound[i].key)
+		aj, rj := classify(unbound[j].schema, unbound[j].key)
+		if ri != rj {
+			return ri < rj
+		}
+		if ai != aj {
+			return ai < aj
+		}
+		return unbound[i].key < unbound[j].key
+	})
+	fmt.Println("\nOther unbound actions:")
+	fmt.Println()
+	for _, e := range unbound {
+		if ess[e.schema+" "+e.key] {
+			continue
+		}
+		app, _ := classify(e.schema, e.key)
+		fmt.Printf("  %-16s %s\n", app, humanise(e.key))
+	}
+	return nil
+}
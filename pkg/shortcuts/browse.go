@@ -0,0 +1,643 @@
+package shortcuts
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+/*────────────────────── browse ──────────────────────
+
+The table full-screen, for someone at a terminal;
+a bare `gnome-shortcuts` opens it there instead of
+asking for the layout first.  Keys are read raw:
+
+	↑ ↓ PgUp PgDn Home End   move; vi's j k g G
+	         and Ctrl-d Ctrl-u (half a screen) too
+	/        filter as you type, the match marked in
+	         each row; Enter keeps it, Esc drops it
+	1 2 3    sort by shortcut, application, action,
+	         again to reverse; 0 for priority order
+	Enter    the selected row in detail: spec, schema
+	         key and what its schema says of it, where
+	         the value comes from, what it shadows;
+	         Esc or Enter again goes back
+	y        copy the selected row: a custom shortcut's
+	         command, else its accelerator
+	l        next keyboard layout
+	?        these keys, until ? or Esc
+	q        quit (Ctrl-C too)
+
+The mouse works too: the wheel moves, a click
+selects a row and one on a column head sorts by
+it.  Shift and a drag still select text in most
+terminals.
+*/
+
+func init() {
+	commands["browse"] = command{
+		help: "full-screen table: / filters, 1-3 sort, l switches layout, ? lists keys",
+		flags: func(fs *flag.FlagSet) {
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runBrowse,
+	}
+}
+
+var kbNames = [...]string{kbApple: "Apple", kbPC: "PC", kbChrome: "Chromebook"}
+
+// browseRow is a table row and the row it came from, for the detail pane.
+type browseRow struct {
+	Binding
+	r row
+}
+
+type browser struct {
+	kb        kb
+	all, view []browseRow // every row, and those shown
+	filter    string
+	typing    bool   // the filter has the keyboard
+	detail    bool   // the selected row's detail pane is up
+	help      bool   // the key list is up
+	msg       string // for the status line, until the next key
+	sortCol   int    // 0 priority order, else the column
+	desc      bool
+	top, cur  int // first row on screen, selected row
+	w, h      int
+	color     bool  // rows in the theme's colours
+	theme     theme // selected and header are used even without colour
+}
+
+func runBrowse([]string) error {
+	in, out := int(os.Stdin.Fd()), int(os.Stdout.Fd())
+	if !readline.IsTerminal(in) || !readline.IsTerminal(out) {
+		return fmt.Errorf("browse: needs a terminal; use list")
+	}
+	b := &browser{kb: kbPC, color: colorOn(), theme: palette()}
+	if !b.color {
+		b.theme.selected, b.theme.header = "7", "7"
+	}
+	if k, ok := presetLayout(); ok {
+		b.kb = k
+	} else if k, ok := detectedLayout(); ok {
+		b.kb = k
+	}
+	if err := b.load(); err != nil {
+		return err
+	}
+	st, err := readline.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer readline.Restore(in, st)
+	fmt.Print(escAltScreen + escMouseOn)
+	defer fmt.Print(escMouseOff + escMainScreen)
+
+	keys, stop, err := keyReader(in)
+	if err != nil {
+		return err
+	}
+	defer stop()
+	winch := make(chan os.Signal, 1)
+	signal.Notify(winch, syscall.SIGWINCH)
+	defer signal.Stop(winch)
+
+	for {
+		b.w, b.h = termSize()
+		b.scroll()
+		b.draw(os.Stdout)
+		select {
+		case <-runCtx.Done():
+			return runCtx.Err()
+		case <-winch:
+		case k, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			quit, err := b.key(k)
+			if quit || err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// keyReader sends the keys typed on fd until stop.  It reads a
+// non-blocking dup of fd, which stop can close under a pending Read,
+// so nothing is left reading the terminal once browse returns.
+func keyReader(fd int) (keys <-chan string, stop func(), err error) {
+	dup, err := syscall.Dup(fd)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := syscall.SetNonblock(dup, true); err != nil {
+		syscall.Close(dup)
+		return nil, nil, err
+	}
+	f := os.NewFile(uintptr(dup), "stdin")
+	ch, done := make(chan string), make(chan struct{})
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		defer close(ch)
+		buf := make([]byte, 64)
+		for {
+			n, err := f.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, k := range keysIn(buf[:n]) {
+				select {
+				case ch <- k:
+				case <-done:
+					return
+				}
+			}
+		}
+	}()
+	return ch, func() {
+		close(done)
+		f.Close()
+		wg.Wait()
+		syscall.SetNonblock(fd, false) // the dup shares fd's flags
+	}, nil
+}
+
+// load collects the table for b.kb.
+func (b *browser) load() error {
+	rows, _, err := collect(labelsOn(b.kb, "text"))
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+	var shown []row
+	for _, r := range rows {
+		if !excluded(r) {
+			shown = append(shown, r)
+		}
+	}
+	b.all = b.all[:0]
+	for i, x := range listBindings(shown) {
+		b.all = append(b.all, browseRow{x, shown[i]})
+	}
+	b.refresh()
+	return nil
+}
+
+// refresh rebuilds the view from the filter and sort order.
+func (b *browser) refresh() {
+	b.view = b.view[:0]
+	for _, x := range b.all {
+		if matches(x.Binding, b.filter) {
+			b.view = append(b.view, x)
+		}
+	}
+	if b.sortCol > 0 {
+		by := sortBy[[...]string{"accel", "app", "action"}[b.sortCol-1]]
+		slices.SortStableFunc(b.view, func(x, y browseRow) int {
+			if b.desc {
+				return by(y.Binding, x.Binding)
+			}
+			return by(x.Binding, y.Binding)
+		})
+	}
+	b.cur = min(b.cur, max(len(b.view)-1, 0))
+}
+
+// key handles one key press; quit ends browse.
+func (b *browser) key(k string) (quit bool, err error) {
+	if k == "ctrl-c" {
+		return true, nil
+	}
+	b.msg = ""
+	if strings.HasPrefix(k, "click ") {
+		b.click(k)
+		return false, nil
+	}
+	if k == "y" && !b.typing && len(b.view) > 0 {
+		v := clipValue(b.view[b.cur].r)
+		b.msg = "copied " + v
+		if err := copyText(v); err != nil {
+			b.msg = err.Error()
+		}
+		return false, nil
+	}
+	if b.help {
+		switch k {
+		case "q":
+			return true, nil
+		case "?", "esc", "enter":
+			b.help = false
+		}
+		return false, nil
+	}
+	if b.detail {
+		switch k {
+		case "q":
+			return true, nil
+		case "enter", "esc":
+			b.detail = false
+		}
+		return false, nil
+	}
+	if b.move(k) {
+		return false, nil
+	}
+	if b.typing {
+		switch k {
+		case "enter":
+			b.typing = false
+		case "esc":
+			b.typing, b.filter = false, ""
+		case "backspace":
+			if _, n := utf8.DecodeLastRuneInString(b.filter); n > 0 {
+				b.filter = b.filter[:len(b.filter)-n]
+			}
+		case "ctrl-u":
+			b.filter = ""
+		default:
+			if utf8.RuneCountInString(k) == 1 {
+				b.filter += k
+			}
+		}
+		b.refresh()
+		return false, nil
+	}
+	switch k {
+	case "q":
+		return true, nil
+	case "/":
+		b.typing = true
+	case "?":
+		b.help = true
+	case "enter":
+		b.detail = len(b.view) > 0
+	case "esc":
+		b.filter = ""
+	case "0":
+		b.sortCol, b.desc = 0, false
+	case "1", "2", "3":
+		c := int(k[0] - '0')
+		b.desc = b.sortCol == c && !b.desc
+		b.sortCol = c
+	case "l":
+		b.kb = (b.kb + 1) % kb(len(kbNames))
+		return false, b.load()
+	}
+	b.refresh()
+	return false, nil
+}
+
+// move handles the cursor keys, and vi's while the filter does not
+// have the keyboard.
+func (b *browser) move(k string) bool {
+	if vi, ok := viKeys[k]; ok && !b.typing {
+		k = vi
+	}
+	page := max(b.h-3, 1)
+	switch k {
+	case "up":
+		b.cur--
+	case "down":
+		b.cur++
+	case "pgup":
+		b.cur -= page
+	case "pgdn":
+		b.cur += page
+	case "wheelup":
+		b.cur -= 3
+	case "wheeldn":
+		b.cur += 3
+	case "halfup":
+		b.cur -= max(page/2, 1)
+	case "halfdn":
+		b.cur += max(page/2, 1)
+	case "home":
+		b.cur = 0
+	case "end":
+		b.cur = len(b.view) - 1
+	default:
+		return false
+	}
+	b.cur = max(min(b.cur, len(b.view)-1), 0)
+	return true
+}
+
+// click selects the row clicked, or sorts by the column whose head
+// was; k is "click x y", counted from 1.
+func (b *browser) click(k string) {
+	var x, y int
+	fmt.Sscanf(k, "click %d %d", &x, &y)
+	switch {
+	case b.help || b.detail:
+	case y == 1:
+		c := 3
+		if x <= 29 {
+			c = 1
+		} else if x <= 58 {
+			c = 2
+		}
+		b.desc = b.sortCol == c && !b.desc
+		b.sortCol = c
+		b.refresh()
+	case y < b.h && b.top+y-2 < len(b.view):
+		b.cur = b.top + y - 2
+	}
+}
+
+var viKeys = map[string]string{
+	"k": "up", "j": "down", "g": "home", "G": "end", "ctrl-u": "halfup", "ctrl-d": "halfdn",
+}
+
+// scroll keeps the selected row on screen.
+func (b *browser) scroll() {
+	room := max(b.h-2, 1)
+	if b.cur < b.top {
+		b.top = b.cur
+	}
+	if b.cur >= b.top+room {
+		b.top = b.cur - room + 1
+	}
+	b.top = max(min(b.top, len(b.view)-room), 0)
+}
+
+// draw paints a header, the rows that fit and a status line.
+func (b *browser) draw(w io.Writer) {
+	var s strings.Builder
+	s.WriteString("\x1b[H")
+	heads := []string{"Shortcut", "Application", "Action"}
+	if b.sortCol > 0 {
+		heads[b.sortCol-1] += map[bool]string{false: " ↓", true: " ↑"}[b.desc]
+	}
+	s.WriteString(sgr(b.theme.header, b.line(heads[0], heads[1], heads[2])) + "\r\n")
+	if b.help {
+		b.drawHelp(&s)
+		io.WriteString(w, s.String())
+		return
+	}
+	if b.detail {
+		b.drawDetail(&s)
+		io.WriteString(w, s.String())
+		return
+	}
+	for i := b.top; i < b.top+b.h-2; i++ {
+		switch {
+		case i >= len(b.view):
+			s.WriteString("\x1b[K\r\n")
+		case i == b.cur:
+			s.WriteString("\x1b[" + b.theme.selected + "m" + b.row(b.view[i], b.theme.selected) + "\x1b[m\r\n")
+		default:
+			s.WriteString(b.row(b.view[i], "") + "\x1b[K\r\n")
+		}
+	}
+	if b.typing {
+		s.WriteString(fitTo("/"+b.filter+"▏", b.w))
+	} else {
+		status := fmt.Sprintf("%d/%d · %s", len(b.view), len(b.all), kbNames[b.kb])
+		if b.filter != "" {
+			status += " · /" + b.filter
+		}
+		if b.msg != "" {
+			status += " · " + b.msg
+		} else {
+			status += " · / filter  1-3 sort  ⏎ detail  y copy  l layout  ? keys  q quit"
+		}
+		s.WriteString(fitTo(status, b.w))
+	}
+	s.WriteString("\x1b[K")
+	io.WriteString(w, s.String())
+}
+
+// browseKeys is the ? overlay.
+var browseKeys = [][2]string{
+	{"↑ ↓  k j", "previous, next row"},
+	{"PgUp PgDn", "a screen up, down"},
+	{"Ctrl-u Ctrl-d", "half a screen up, down"},
+	{"Home End  g G", "first, last row"},
+	{"/", "filter as you type; Enter keeps it, Esc drops it"},
+	{"Esc", "drop the filter"},
+	{"1 2 3", "sort by shortcut, application, action; again reverses"},
+	{"0", "priority order"},
+	{"Enter", "the selected row in detail"},
+	{"y", "copy the selected command or accelerator"},
+	{"l", "next keyboard layout"},
+	{"?", "this list"},
+	{"q  Ctrl-C", "quit"},
+}
+
+// drawHelp fills the table's place with browseKeys.
+func (b *browser) drawHelp(s *strings.Builder) {
+	for i := range b.h - 2 {
+		if i > 0 && i <= len(browseKeys) {
+			k := browseKeys[i-1]
+			s.WriteString(fitTo("  "+padTo(k[0], 16)+k[1], b.w))
+		}
+		s.WriteString("\x1b[K\r\n")
+	}
+	s.WriteString(fitTo("?/Esc back  q quit", b.w) + "\x1b[K")
+}
+
+// drawDetail fills the table's place with what is known of the
+// selected row.
+func (b *browser) drawDetail(s *strings.Builder) {
+	x := b.view[b.cur]
+	r := x.r
+	var lines []string
+	field := func(name, val string) {
+		if val == "" {
+			return
+		}
+		for i, l := range wrapTo(val, max(b.w-16, 20)) {
+			if i == 0 {
+				lines = append(lines, fmt.Sprintf("  %-13s %s", name, l))
+			} else {
+				lines = append(lines, "                "+l)
+			}
+		}
+	}
+	lines = append(lines, "  "+x.Accel, "")
+	field("Action", tagged(x.Binding))
+	field("Application", x.App)
+	field("Spec", r.spec)
+	field("Schema", r.schema)
+	field("Key", r.key)
+	if r.schema != "" {
+		si := lookupSchema(r.schema)
+		field("Summary", si.meta[r.key].summary)
+		field("Description", si.meta[r.key].description)
+		field("Default", si.defaults[r.key])
+		field("Value from", sourceLabel(r.source))
+	}
+	field("Defined in", r.src)
+	if r.locked {
+		field("Locked", "by the administrator")
+	}
+	if len(r.lost) == 0 {
+		field("Shadows", "nothing")
+	}
+	for i, l := range r.lost {
+		name := ""
+		if i == 0 {
+			name = "Shadows"
+		}
+		at := l.spec
+		if l.schema != "" {
+			at += ", " + l.schema + " " + l.key
+		}
+		if l.source != "" {
+			at += ", from " + sourceLabel(l.source)
+		}
+		field(name, l.app+": "+l.action+"  ("+at+")")
+	}
+	for i := range b.h - 2 {
+		if i < len(lines) {
+			s.WriteString(fitTo(lines[i], b.w))
+		}
+		s.WriteString("\x1b[K\r\n")
+	}
+	s.WriteString(fitTo(cmp.Or(b.msg, "⏎/Esc back  y copy  q quit"), b.w) + "\x1b[K")
+}
+
+// wrapTo breaks s at spaces into lines of at most n columns.
+func wrapTo(s string, n int) []string {
+	var out []string
+	line := ""
+	for _, w := range strings.Fields(s) {
+		if line != "" && dispWidth(line)+1+dispWidth(w) > n {
+			out = append(out, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += w
+	}
+	return append(out, line)
+}
+
+// line lays out one row in the terminal's width, like list's columns.
+// row is x's line, the filter's matches marked and style (SGR, ""
+// for none) resumed after each.  Other rows are coloured cell by cell
+// when b.color is set, as the text table is.
+func (b *browser) row(x browseRow, style string) string {
+	action := tagged(x.Binding)
+	if style != "" || !b.color || b.w < 60 {
+		l, _ := markMatches(b.line(x.Accel, x.App, action), b.filter, style)
+		return l
+	}
+	widths := [3]int{28, 28, b.w - 58}
+	cells := [3]string{fitTo(x.Accel, 28), fitTo(x.App, 28), fitTo(action, widths[2])}
+	out := make([]string, 3)
+	for i, c := range cells {
+		m, ok := markMatches(c, b.filter, "")
+		switch {
+		case ok:
+		case i == 0 && c == x.Accel:
+			m = paintAccel(x.Binding)
+		case i == 1:
+			m = sgr(familyColor(x.App), c)
+		}
+		out[i] = m + padding(c, widths[i])
+	}
+	return strings.Join(out, " ")
+}
+
+func (b *browser) line(accel, app, action string) string {
+	l := padTo(fitTo(accel, 28), 28) + " " + padTo(fitTo(app, 28), 28) + " " + action
+	return padTo(fitTo(l, b.w), b.w)
+}
+
+// fitTo cuts s to n columns, marking the cut with an ellipsis.
+func fitTo(s string, n int) string {
+	if dispWidth(s) <= n {
+		return s
+	}
+	var b strings.Builder
+	for _, r := range s {
+		if dispWidth(b.String()+string(r)) > n-1 {
+			break
+		}
+		b.WriteRune(r)
+	}
+	return b.String() + "…"
+}
+
+// keysIn names the keys in one read from a raw terminal.
+func keysIn(buf []byte) []string {
+	var out []string
+	for len(buf) > 0 {
+		switch c := buf[0]; {
+		case c == 0x1b && len(buf) > 2 && (buf[1] == '[' || buf[1] == 'O'):
+			i := 2
+			for i < len(buf) && (buf[i] < 0x40 || buf[i] > 0x7e) {
+				i++
+			}
+			if i < len(buf) {
+				seq := string(buf[2 : i+1])
+				if k, ok := csiKeys[seq]; ok {
+					out = append(out, k)
+				} else if k, ok := mouseKey(seq); ok {
+					out = append(out, k)
+				}
+			}
+			buf = buf[min(i+1, len(buf)):]
+			continue
+		case c == 0x1b:
+			out = append(out, "esc")
+		case c == '\r' || c == '\n':
+			out = append(out, "enter")
+		case c == 0x7f || c == 0x08:
+			out = append(out, "backspace")
+		case c == 0x03:
+			out = append(out, "ctrl-c")
+		case c == 0x15:
+			out = append(out, "ctrl-u")
+		case c == 0x04:
+			out = append(out, "ctrl-d")
+		case c < 0x20:
+		default:
+			r, n := utf8.DecodeRune(buf)
+			out = append(out, string(r))
+			buf = buf[n:]
+			continue
+		}
+		buf = buf[1:]
+	}
+	return out
+}
+
+// mouseKey names an SGR mouse report ("<b;x;yM"): "wheelup",
+// "wheeldn", or "click x y" for the left button going down.
+func mouseKey(seq string) (string, bool) {
+	var btn, x, y int
+	var end byte
+	if n, _ := fmt.Sscanf(seq, "<%d;%d;%d%c", &btn, &x, &y, &end); n != 4 {
+		return "", false
+	}
+	switch {
+	case btn == 64:
+		return "wheelup", true
+	case btn == 65:
+		return "wheeldn", true
+	case btn == 0 && end == 'M':
+		return fmt.Sprintf("click %d %d", x, y), true
+	}
+	return "", false
+}
+
+var csiKeys = map[string]string{
+	"A": "up", "B": "down", "H": "home", "F": "end",
+	"5~": "pgup", "6~": "pgdn", "1~": "home", "4~": "end", "7~": "home", "8~": "end",
+}
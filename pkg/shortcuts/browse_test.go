@@ -0,0 +1,39 @@
+package shortcuts
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// TestKeyReaderStops reads a key from a pipe, then stops with a Read
+// pending: stop must return and the reader must end.
+func TestKeyReaderStops(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+	keys, stop, err := keyReader(int(r.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	w.WriteString("q")
+	if k := <-keys; k != "q" {
+		t.Errorf("key = %q, want q", k)
+	}
+	stopped := make(chan struct{})
+	go func() {
+		stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("stop did not return with a Read pending")
+	}
+	if _, ok := <-keys; ok {
+		t.Error("keys still open after stop")
+	}
+}
@@ -0,0 +1,27 @@
+package shortcuts
+
+/*─────────────────── Cinnamon ───────────────────
+
+Cinnamon (Linux Mint) keeps its bindings under
+org.cinnamon.desktop.keybindings:
+
+	.wm                 Muffin window management
+	.media-keys         media, screenshots, power …
+	(itself)            custom-list and shell keys
+	.custom-keybinding  relocatable, one per entry of
+	                    custom-list; binding is 'as'
+
+Everything else goes through the GNOME code.
+*/
+
+const (
+	cinnamonKeys   = "org.cinnamon.desktop.keybindings"
+	cinnamonCustom = cinnamonKeys + ".custom-keybinding"
+)
+
+func init() {
+	relocatables = append(relocatables, relocatable{child: cinnamonCustom,
+		parent: cinnamonKeys, key: "custom-list",
+		path: "/org/cinnamon/desktop/keybindings/custom-keybindings/%s/"})
+	customSchemas = append(customSchemas, cinnamonCustom)
+}
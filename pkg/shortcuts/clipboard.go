@@ -0,0 +1,67 @@
+package shortcuts
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+/*─────────────────── clipboard ───────────────────
+
+browse's y and get -copy put a binding on the
+clipboard: what a custom shortcut runs, else its
+accelerator as GTK spells it, ready for gsettings
+or a config file.  wl-copy serves Wayland, xclip
+or xsel X11.
+*/
+
+// clipValue is what copying r puts on the clipboard.
+func clipValue(r row) string {
+	if isCustom(r.schema) {
+		if cmd := customCommand(r.schema); cmd != "" {
+			return cmd
+		}
+	}
+	if a, ok := parseAccel(r.spec); ok { // core specs read "<Super>+Up"
+		return a.spec()
+	}
+	return r.spec
+}
+
+// clipboardTools are tried in order; the env var says which display
+// server the tool needs.
+var clipboardTools = []struct {
+	env  string
+	argv []string
+}{
+	{"WAYLAND_DISPLAY", []string{"wl-copy"}},
+	{"DISPLAY", []string{"xclip", "-selection", "clipboard"}},
+	{"DISPLAY", []string{"xsel", "--clipboard", "--input"}},
+}
+
+// copyText puts s on the desktop clipboard.
+func copyText(s string) error {
+	for _, t := range clipboardTools {
+		if os.Getenv(t.env) == "" {
+			continue
+		}
+		if _, err := exec.LookPath(t.argv[0]); err != nil {
+			continue
+		}
+		ctx, cancel := toolContext()
+		defer cancel()
+		cmd := exec.CommandContext(ctx, t.argv[0], t.argv[1:]...)
+		cmd.Stdin = strings.NewReader(s)
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		cmd.WaitDelay = 100 * time.Millisecond // wl-copy and xclip stay on to serve the paste
+		if err := cmd.Run(); err != nil && !errors.Is(err, exec.ErrWaitDelay) {
+			return fmt.Errorf("%s: %w: %s", t.argv[0], err, strings.TrimSpace(stderr.String()))
+		}
+		return nil
+	}
+	return fmt.Errorf("no clipboard: install wl-clipboard (Wayland) or xclip (X11)")
+}
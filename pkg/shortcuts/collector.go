@@ -0,0 +1,244 @@
+package shortcuts
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+/*────────────────── collectors ───────────────────
+
+Every source of bindings is a Collector, claimed
+in the order below.  How a source's rows enter the
+table is its claim mode:
+
+	claimed    compete for their chord (the resolver)
+	replacing  replace the chord's row, keeping its
+	           losers (Mutter's immutable core chords)
+	listed     shown, but hold no chord (lid, gestures,
+	           keys inside shell screens)
+	appLocal   lose to any system row, never to each
+	           other (terminals, apps)
+
+Collectors added with Register, and plugins
+(plugin.go), are claimed after the built-in
+claimed sources.
+*/
+
+// Collector is a source of bindings for the GNOME table.  Collect
+// returns each claimant with Shadowed empty; Rank places it against
+// the built-in sources (see Binding).
+type Collector interface {
+	Name() string
+	Collect(ctx context.Context) ([]Binding, error)
+}
+
+type claimMode int
+
+const (
+	claimed claimMode = iota
+	replacing
+	listed
+	appLocal
+)
+
+// pass is one collection run: the labels and the settings dump the
+// built-in collectors share.
+type pass struct {
+	ctx     context.Context
+	lbl     map[string]string
+	entries []entry
+	vals    map[string]string // "schema key" → value
+}
+
+func newPass(ctx context.Context, lbl map[string]string) *pass {
+	p := &pass{ctx: ctx, lbl: lbl, entries: dumpAll(), vals: map[string]string{}}
+	for _, e := range p.entries {
+		p.vals[e.schema+" "+e.key] = e.val
+	}
+	return p
+}
+
+// builtin is a collector that works on rows, keeping what Binding
+// leaves out (schema order, the key's other specs).
+type builtin struct {
+	name string
+	mode claimMode
+	rows func(p *pass) []row
+}
+
+func (b builtin) Name() string { return b.name }
+
+func (b builtin) Collect(ctx context.Context) ([]Binding, error) {
+	defer withRunCtx(ctx)()
+	rows := b.rows(newPass(ctx, labelsFor(kbPC, "text")))
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return toBindings(rows, nil), nil
+}
+
+var collectors = []Collector{
+	builtin{"gsettings", claimed, gsettingsRows},
+	builtin{"hardware", claimed, func(p *pass) []row { return hardwareRows(p.vals, p.lbl) }},
+	builtin{"xkb", claimed, func(p *pass) []row { return xkbKeyRows(p.lbl) }},
+	builtin{"custom", claimed, customRows},
+	builtin{"xbindkeys", claimed, func(p *pass) []row { return xbindkeysRows(p.lbl) }},
+	builtin{"core", replacing, coreRows},
+	builtin{"a11y-gestures", listed, func(p *pass) []row { return a11yGestures(p.vals) }},
+	builtin{"shell-screens", listed, func(p *pass) []row { return shellRows(p.lbl) }},
+	builtin{"terminals", appLocal, func(p *pass) []row { return terminalRows(p.lbl) }},
+	builtin{"apps", appLocal, func(p *pass) []row { return appRows(p.lbl) }},
+}
+
+// Register adds c to the sources of the GNOME table; its bindings
+// compete for their chords like GNOME's own.
+func Register(c Collector) { collectors = append(collectors, c) }
+
+// collected runs c for p; other collectors' bindings become rows
+// with their Accel formatted for p's labels.
+func collected(c Collector, p *pass) (claimMode, []row) {
+	if b, ok := c.(builtin); ok {
+		rows := b.rows(p)
+		logger.Info("collected", "collector", b.name, "rows", len(rows))
+		return b.mode, rows
+	}
+	bs, err := c.Collect(p.ctx)
+	if err != nil {
+		warn(fmt.Errorf("collector %s: %w", c.Name(), err))
+	}
+	var out []row
+	for i, b := range bs {
+		r := b.row()
+		if acc, ok := fmtKey(r.spec, p.lbl); ok {
+			r.accel = acc
+		} else if r.accel == "" {
+			continue
+		}
+		r.order = i
+		out = append(out, r)
+	}
+	logger.Info("collected", "collector", c.Name(), "rows", len(out))
+	return claimed, out
+}
+
+/*──────── gsettings keybinding keys ────────*/
+
+func gsettingsRows(p *pass) []row {
+	var out []row
+	for _, e := range p.entries {
+		schema, key, val := e.schema, e.key, e.val
+		if isCustom(schema) || isHwKey(schema, key) || isInstanceList(schema, key) { // see customRows, hardwareRows, dumpAll
+			continue
+		}
+		media := includeMedia && isMediaSchema(schema, key)
+		a11y, isA11y := a11yAction(schema, key)
+		if !strings.Contains(schema, "keybinding") && !isInputMethod(schema, key) && !media && !isA11y {
+			continue
+		}
+		app, rank := classify(schema, key)
+		ord, ok := lookupSchema(schema).order[key]
+		if !ok {
+			ord = 1 << 20 // very large ⇒ “last”
+		}
+		action := humanise(key)
+		switch {
+		case isInputMethod(schema, key):
+			action = ibusHotkeys[key]
+		case isA11y:
+			app, action = a11yApp, a11y
+		case media:
+			action = mediaAction(key)
+		}
+		if t, ok := localAction(schema, key); ok && !isA11y && !isInputMethod(schema, key) {
+			action = t
+		}
+		keep, tag := sessionFilter(schema, key)
+		if !keep {
+			dropped(val, "other session: "+backendFor(schema, key)+" only", "schema", schema, "key", key)
+			continue
+		}
+		action += tag
+		if id, dir, ok := strings.Cut(schema, ":"); ok && strings.HasPrefix(id, extensionSchemas) {
+			action += " (" + path.Base(dir) + ")" // the monitor, for per-monitor configs
+		}
+
+		specs := gvTextStrings(val)
+		for _, spec := range specs {
+			acc, ok := fmtAccel(spec, p.lbl)
+			if !ok {
+				continue
+			}
+			out = append(out, row{accel: acc, app: app, action: action,
+				rank: rank, order: ord, spec: spec, schema: schema, key: key, keySpecs: specs})
+		}
+	}
+	return out
+}
+
+/*──────── custom shortcuts (rank 3 ⇒ core/schema win) ────────*/
+
+func customRows(p *pass) []row {
+	customMap := map[string]*custom{}
+	var schemas []string
+	for _, e := range p.entries {
+		if !isCustom(e.schema) {
+			continue
+		}
+		c := customMap[e.schema]
+		if c == nil {
+			c = &custom{}
+			customMap[e.schema] = c
+			schemas = append(schemas, e.schema)
+		}
+		switch e.key {
+		case "binding": // 's' for GNOME, 'as' for Cinnamon
+			c.binds = append(c.binds, gvTextStrings(e.val)...)
+		case "name":
+			c.name = gvTextString(e.val)
+		case "command", "action": // MATE calls it action
+			c.cmd = gvTextString(e.val)
+		}
+	}
+	var out []row
+	for _, schema := range schemas {
+		c := customMap[schema]
+		app := humanise(filepath.Base(c.cmd))
+		if app == "" {
+			app = "Custom"
+		}
+		act := humanise(c.name)
+		if act == "" {
+			act = c.cmd
+		}
+		for _, b := range c.binds {
+			if acc, ok := fmtAccel(b, p.lbl); ok {
+				out = append(out, row{accel: acc, app: app, action: act, rank: 3,
+					spec: b, schema: schema, key: "binding", keySpecs: c.binds})
+			}
+		}
+	}
+	return out
+}
+
+/*──────── immutable core shortcuts ────────*/
+
+// coreRows are the core chords whose backing key still holds them.
+func coreRows(p *pass) []row {
+	if !coreOverride {
+		return nil
+	}
+	var out []row
+	for i, s := range loadCoreShortcuts() {
+		if v, ok := p.vals[s.backing]; s.backing != "" && (!ok || !holdsChord(v, s.spec)) {
+			continue
+		}
+		if acc, ok := fmtAccel(s.spec, p.lbl); ok {
+			out = append(out, row{accel: acc, app: "Window Manager", action: s.action,
+				rank: -1, order: i, spec: s.spec})
+		}
+	}
+	return out
+}
@@ -0,0 +1,173 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"os"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/chzyer/readline"
+)
+
+/*───────────────────── colour ─────────────────────
+
+The text table and the conflict report colour
+modifiers, each application family and the ✔/✘
+of a conflict; a shortcut that shadows others has
+its key in red.  Which colours is the theme's
+(theme.go).  -color=auto, the
+default, colours only a terminal, and only while
+NO_COLOR is unset or empty and TERM is not dumb
+(no-color.org).  always and never do what they say.
+*/
+
+var colorMode = "auto"
+
+func colorFlag(fs *flag.FlagSet) {
+	fs.Func("color", "colour output: auto, always or never (default auto)", func(s string) error {
+		switch s {
+		case "auto", "always", "never":
+			colorMode = s
+			return nil
+		}
+		return fmt.Errorf("want auto, always or never")
+	})
+}
+
+// colorOn reports whether stdout gets colour.
+func colorOn() bool {
+	switch colorMode {
+	case "always":
+		return true
+	case "never":
+		return false
+	}
+	return os.Getenv("NO_COLOR") == "" && os.Getenv("TERM") != "dumb" &&
+		readline.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// SGR parameters.
+const (
+	sgrBold    = "1"
+	sgrDim     = "2"
+	sgrRed     = "31"
+	sgrGreen   = "32"
+	sgrYellow  = "33"
+	sgrBlue    = "34"
+	sgrMagenta = "35"
+	sgrCyan    = "36"
+)
+
+// familyColor gives every Application column value its own colour,
+// the same on every run.
+func familyColor(app string) string {
+	fs := palette().families
+	h := fnv.New32a()
+	h.Write([]byte(app))
+	return fs[h.Sum32()%uint32(len(fs))]
+}
+
+// sgr wraps s in one SGR sequence.
+func sgr(code, s string) string {
+	if code == "" || s == "" {
+		return s
+	}
+	return "\x1b[" + code + "m" + s + "\x1b[m"
+}
+
+// paintAccel colours the modifiers of "Ctrl + Shift + Q" and the
+// key, the latter as a conflict when the chord shadows other bindings.
+func paintAccel(b Binding) string {
+	t := palette()
+	mods, glue, key := splitAccel(b)
+	keyColor := t.key
+	if len(b.Shadowed) > 0 {
+		keyColor = t.conflict
+	}
+	var out strings.Builder
+	for i, m := range mods {
+		out.WriteString(sgr(t.modifiers, m) + sgr(sgrDim, glue[i]))
+	}
+	out.WriteString(sgr(keyColor, key))
+	return out.String()
+}
+
+// splitAccel cuts b.Accel into the modifiers b.Spec holds, what
+// follows each (accelSep, or nothing after a -glyphs symbol), and the
+// key, which may itself contain accelSep ("Ctrl--").  A chord it
+// cannot cut is all key.
+func splitAccel(b Binding) (mods, glue []string, key string) {
+	a, ok := parseAccel(b.Spec)
+	if !ok {
+		return nil, nil, b.Accel
+	}
+	if a.key == "ISO_Left_Tab" {
+		a.mods |= modShift // printed as Shift + Tab
+	}
+	n := bits.OnesCount(uint(a.mods))
+	if a.key == "" {
+		n-- // the last modifier stands where the key would
+	}
+	rest := b.Accel
+	for range n {
+		if glyphsOnMod && !sepSet {
+			if g := modGlyphPrefix(rest); g != "" {
+				mods, glue, rest = append(mods, g), append(glue, ""), rest[len(g):]
+				continue
+			}
+		}
+		m, r, found := strings.Cut(rest, accelSep)
+		if !found || accelSep == "" {
+			return nil, nil, b.Accel
+		}
+		mods, glue, rest = append(mods, m), append(glue, accelSep), r
+	}
+	return mods, glue, rest
+}
+
+// markMatches highlights every case-insensitive occurrence of f in s,
+// going back to style (SGR parameters, "" for none) after each; ok
+// is false when there is none.
+func markMatches(s, f, style string) (out string, ok bool) {
+	if f == "" {
+		return s, false
+	}
+	mark := palette().match
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		if n := prefixFold(s[i:], f); n > 0 {
+			b.WriteString("\x1b[" + mark + "m" + s[i:i+n] + "\x1b[m")
+			if style != "" {
+				b.WriteString("\x1b[" + style + "m")
+			}
+			i += n
+			ok = true
+			continue
+		}
+		_, n := utf8.DecodeRuneInString(s[i:])
+		b.WriteString(s[i : i+n])
+		i += n
+	}
+	return b.String(), ok
+}
+
+// prefixFold is the length of the prefix of s that equals f, letter
+// case aside, or 0.
+func prefixFold(s, f string) int {
+	i := 0
+	for _, fr := range f {
+		if i >= len(s) {
+			return 0
+		}
+		r, n := utf8.DecodeRuneInString(s[i:])
+		if unicode.ToLower(r) != unicode.ToLower(fr) {
+			return 0
+		}
+		i += n
+	}
+	return i
+}
@@ -0,0 +1,317 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+/*─────────────── cross-desktop compare ───────────────
+
+compare -desktop gnome -desktop kde lines the
+desktops' shortcuts up action by action.  GNOME's
+"schema key" is the common name: other desktops'
+bindings map back to it through wm_actions.tsv,
+exec bindings match by command, and anything left
+is listed as a gap on the desktops that lack it.
+-with macos adds a column from that system's
+equivalents table.
+*/
+
+var compareOpt struct {
+	format string
+	with   []string // systems from the equivalents tables
+}
+
+// wmColumn is each desktop's column in wm_actions.tsv.
+var wmColumn = map[string]int{"sway": 0, "i3": 0, "hyprland": 1, "kde": 2}
+
+func init() {
+	commands["compare"] = command{
+		help: "side-by-side shortcuts of -desktop values and -with systems (-format text|md)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&compareOpt.format, "format", "text", "output format: text or md")
+			fs.Func("with", "add a column for another system: "+strings.Join(sortedKeys(systems), ", "), func(v string) error {
+				if _, ok := systems[v]; !ok {
+					return fmt.Errorf("want one of %s", strings.Join(sortedKeys(systems), ", "))
+				}
+				compareOpt.with = append(compareOpt.with, v)
+				return nil
+			})
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runCompare,
+	}
+}
+
+// actionIndex maps another desktop's action back to a GNOME "schema key".
+type actionIndex struct {
+	exact map[string]string
+	nums  []numAction
+}
+
+type numAction struct {
+	re  *regexp.Regexp
+	key string // with "%" where the number goes
+}
+
+// normAction folds spacing and hyprland's commas so cells and
+// config lines compare equal.
+func normAction(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, ",", " ")), " ")
+}
+
+func reverseActions(col int) actionIndex {
+	keys, actions := readWMActions()
+	ix := actionIndex{exact: map[string]string{}}
+	for _, k := range keys {
+		cells := actions[k]
+		if col >= len(cells) || cells[col] == "-" || cells[col] == "" {
+			continue
+		}
+		c := cells[col]
+		if col != wmColumn["kde"] {
+			c = normAction(c)
+		}
+		if strings.Contains(c, "%") && strings.HasSuffix(k, "-%") {
+			re := "^" + strings.ReplaceAll(regexp.QuoteMeta(c), "%", `(\d+)`) + "$"
+			ix.nums = append(ix.nums, numAction{regexp.MustCompile(re), k})
+			continue
+		}
+		if _, ok := ix.exact[c]; !ok {
+			ix.exact[c] = k
+		}
+	}
+	return ix
+}
+
+func (ix actionIndex) lookup(a string) (string, bool) {
+	if k, ok := ix.exact[a]; ok {
+		return k, true
+	}
+	for _, n := range ix.nums {
+		if m := n.re.FindStringSubmatch(a); m != nil {
+			return strings.ReplaceAll(n.key, "%", m[1]), true
+		}
+	}
+	return "", false
+}
+
+// actionID is the name r is compared under.
+func actionID(desktop string, r row, ix actionIndex) string {
+	if col, ok := wmColumn[desktop]; ok {
+		a := r.key // kde: component:action
+		if col != wmColumn["kde"] {
+			a = normAction(r.action)
+		}
+		if k, ok := ix.lookup(a); ok {
+			return k
+		}
+		if cmd, ok := strings.CutPrefix(normAction(r.action), "exec "); ok {
+			return "exec " + strings.TrimPrefix(cmd, "--no-startup-id ")
+		}
+		return desktop + "\x00" + r.app + "\x00" + r.action
+	}
+	switch {
+	case isCustom(r.schema):
+		if cmd := customCommand(r.schema); cmd != "" {
+			return "exec " + normAction(cmd)
+		}
+	case schemaKey(r) != "":
+		return schemaKey(r)
+	}
+	return desktop + "\x00" + r.app + "\x00" + r.action
+}
+
+// comparison is one action across the compared desktops.
+type comparison struct {
+	label string
+	cells [][]row // per desktop
+}
+
+func (c comparison) mark() string {
+	var sets []string
+	for _, rs := range c.cells {
+		if len(rs) == 0 {
+			return "gap"
+		}
+		var s []string
+		for _, r := range rs {
+			a, _ := parseAccel(r.spec)
+			s = append(s, a.spec())
+		}
+		sort.Strings(s)
+		sets = append(sets, strings.Join(s, " "))
+	}
+	for _, s := range sets[1:] {
+		if s != sets[0] {
+			return "≠"
+		}
+	}
+	return ""
+}
+
+func compareDesktops(names []string, lbl map[string]string) ([]comparison, error) {
+	var out []comparison
+	at := map[string]int{}
+	saved := desktopOpt
+	defer func() { desktopOpt = saved }()
+	for d, name := range names {
+		desktopOpt = name
+		ix := reverseActions(wmColumn[name])
+		rows, _, err := collect(lbl)
+		if err != nil {
+			return nil, err
+		}
+		sortRows(rows)
+		for _, r := range rows {
+			if r.spec == "" || r.rank >= appRank {
+				continue
+			}
+			id := actionID(name, r, ix)
+			i, ok := at[id]
+			if !ok {
+				i = len(out)
+				at[id] = i
+				out = append(out, comparison{label: r.app + ": " + r.action, cells: make([][]row, len(names)+len(compareOpt.with))})
+			}
+			out[i].cells[d] = append(out[i].cells[d], r)
+		}
+	}
+	ids := make([]string, len(out))
+	for id, i := range at {
+		ids[i] = id
+	}
+	for j, name := range compareOpt.with {
+		sys := systems[name]
+		table := sys.table()
+		sysLbl := systemLabels(lbl, sys.kb)
+		cell := func(e equivalent) []row {
+			acc, ok := fmtKey(e.spec, sysLbl)
+			if !ok {
+				return nil
+			}
+			return []row{{accel: acc, app: sys.title, action: e.action, spec: e.spec}}
+		}
+		shown := map[equivalent]bool{}
+		for i, id := range ids {
+			if e, ok := equivalentOf(table, id); ok {
+				out[i].cells[len(names)+j] = cell(e)
+				shown[e] = true
+			}
+		}
+		for _, k := range sortedKeys(table) { // what none of the desktops bind
+			if _, ok := at[k]; ok || strings.HasSuffix(k, "-%") || shown[table[k]] {
+				continue
+			}
+			shown[table[k]] = true
+			at[k] = len(out)
+			ids = append(ids, k)
+			c := comparison{label: sys.title + ": " + table[k].action, cells: make([][]row, len(names)+len(compareOpt.with))}
+			c.cells[len(names)+j] = cell(table[k])
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+func runCompare([]string) error {
+	names := desktopList
+	if len(names) == 0 && len(compareOpt.with) > 0 {
+		names = []string{desktopOpt}
+	}
+	if len(names)+len(compareOpt.with) < 2 {
+		return fmt.Errorf("compare: give two -desktop values, or -with, e.g. -desktop gnome -desktop kde")
+	}
+	lbl := labels(compareOpt.format)
+	cs, err := compareDesktops(names, lbl)
+	if err != nil {
+		return err
+	}
+	for _, w := range compareOpt.with {
+		names = append(names, systems[w].title)
+	}
+	switch compareOpt.format {
+	case "text":
+		writeCompareText(os.Stdout, names, cs)
+	case "md", "markdown":
+		writeCompareMD(os.Stdout, names, cs)
+	default:
+		return fmt.Errorf("compare: unknown format %q", compareOpt.format)
+	}
+	return nil
+}
+
+func cellText(rs []row) string {
+	if len(rs) == 0 {
+		return "—"
+	}
+	var s []string
+	for _, r := range rs {
+		s = append(s, r.accel)
+	}
+	return strings.Join(s, ", ")
+}
+
+func compareSummary(cs []comparison) string {
+	same, diff, gap := 0, 0, 0
+	for _, c := range cs {
+		switch c.mark() {
+		case "":
+			same++
+		case "≠":
+			diff++
+		default:
+			gap++
+		}
+	}
+	return fmt.Sprintf("%d actions: %d the same, %d different, %d missing somewhere", len(cs), same, diff, gap)
+}
+
+func writeCompareText(w io.Writer, names []string, cs []comparison) {
+	width := make([]int, len(names)+1)
+	width[0] = len("Action")
+	for i, n := range names {
+		width[i+1] = len(n)
+	}
+	for _, c := range cs {
+		width[0] = max(width[0], len([]rune(c.label)))
+		for i, rs := range c.cells {
+			width[i+1] = max(width[i+1], len([]rune(cellText(rs))))
+		}
+	}
+	line := func(cols []string, mark string) {
+		var b strings.Builder
+		for i, s := range cols {
+			b.WriteString(s + strings.Repeat(" ", width[i]-len([]rune(s))+2))
+		}
+		fmt.Fprintln(w, strings.TrimRight(b.String()+mark, " "))
+	}
+	line(append([]string{"Action"}, names...), "")
+	for _, c := range cs {
+		cols := []string{c.label}
+		for _, rs := range c.cells {
+			cols = append(cols, cellText(rs))
+		}
+		line(cols, c.mark())
+	}
+	fmt.Fprintf(w, "\n%s\n", compareSummary(cs))
+}
+
+func writeCompareMD(w io.Writer, names []string, cs []comparison) {
+	fmt.Fprintf(w, "# Shortcuts: %s\n\n", strings.Join(names, " vs "))
+	fmt.Fprintf(w, "| Action | %s | |\n|---|%s---|\n", strings.Join(names, " | "), strings.Repeat("---|", len(names)))
+	for _, c := range cs {
+		var cols []string
+		for _, rs := range c.cells {
+			cols = append(cols, cellText(rs))
+		}
+		fmt.Fprintf(w, "| %s | %s | %s |\n", c.label, strings.Join(cols, " | "), c.mark())
+	}
+	fmt.Fprintf(w, "\n%s\n", compareSummary(cs))
+}
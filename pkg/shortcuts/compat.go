@@ -0,0 +1,77 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"fmt"
+	"strings"
+)
+
+/*─────────── renamed / moved GNOME keys ─────────
+
+renamed_keys.tsv maps "schema key" names from
+older releases to their current home.  "schema
+key" names are translated as they are read back
+from disk (progress.json, observed.json, imports),
+so state saved before an upgrade still applies.
+*/
+
+//go:embed renamed_keys.tsv
+var renamedKeysTSV string
+
+type rename struct{ to, since string }
+
+var renamedKeys = parseRenames(renamedKeysTSV)
+
+func parseRenames(data string) map[string]rename {
+	out := map[string]rename{}
+	for _, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 2 {
+			continue
+		}
+		r := rename{to: f[1]}
+		if len(f) > 2 {
+			r.since = f[2]
+		}
+		out[f[0]] = r
+	}
+	return out
+}
+
+// translateKey follows renames (they may chain across releases);
+// to is "" when the key was removed, ok false when nothing changed.
+func translateKey(k string) (to, since string, ok bool) {
+	to = k
+	for range len(renamedKeys) { // bound: no cycles
+		r, found := renamedKeys[to]
+		if !found {
+			break
+		}
+		ok, since = true, r.since
+		if r.to == "-" {
+			return "", since, true
+		}
+		to = r.to
+	}
+	return to, since, ok
+}
+
+// currentKey is k under its current name; removed keys stay as they
+// were, matching nothing.
+func currentKey(k string) string {
+	if to, _, _ := translateKey(k); to != "" {
+		return to
+	}
+	return k
+}
+
+// describeRename is one line of a translation report.
+func describeRename(from, to, since string) string {
+	if to == "" {
+		return fmt.Sprintf("  %s: removed in GNOME %s", from, since)
+	}
+	return fmt.Sprintf("  %s → %s (GNOME %s)", from, to, since)
+}
@@ -0,0 +1,246 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*─────────────── shell completion ───────────────
+
+The generated scripts call back into the binary
+(`gnome-shortcuts __complete WORDS…`), so candidates
+come from the live model: sub-commands, their flags,
+and — for get, explain, disable and set — existing
+bindings plus every modifier and keysym we know.
+Every TAB runs the binary afresh, so the bindings
+are cached for a completion session: specsCacheTTL,
+or until dconf's user database changes.
+*/
+
+func init() {
+	commands["completion"] = command{
+		help: "print a bash or zsh completion script",
+		run:  runCompletion,
+	}
+	commands["__complete"] = command{run: runComplete}
+}
+
+// bashCompletion reads the words with < > = kept in them (bash breaks
+// words there), then drops from each candidate what bash will not
+// replace: the part of the word up to its last break.
+const bashCompletion = `# gnome-shortcuts bash completion
+_gnome_shortcuts() {
+    local cur words cword
+    if declare -F _get_comp_words_by_ref >/dev/null; then
+        _get_comp_words_by_ref -n '<>=' cur words cword
+    else
+        cur=${COMP_LINE:0:COMP_POINT}
+        cur=${cur##*[[:space:]]}
+        read -ra words <<< "${COMP_LINE:0:COMP_POINT}"
+        [[ -z $cur ]] && words+=("")
+        cword=$((${#words[@]} - 1))
+    fi
+    local pre=${cur%"${cur##*[<>=]}"} c IFS=$'\n'
+    [[ $cur == [\'\"]* ]] && pre=""
+    COMPREPLY=()
+    for c in $(gnome-shortcuts __complete "${words[@]:1:cword}" 2>/dev/null); do
+        c=${c#"$pre"}
+        [[ $cur != [\'\"]* ]] && printf -v c '%q' "$c"
+        COMPREPLY+=("$c")
+    done
+}
+complete -o default -F _gnome_shortcuts gnome-shortcuts
+`
+
+const zshCompletion = `#compdef gnome-shortcuts
+_gnome_shortcuts() {
+    local -a c
+    c=("${(@f)$(gnome-shortcuts __complete "${(@Q)words[2,CURRENT]}" 2>/dev/null)}")
+    compadd -- $c
+}
+compdef _gnome_shortcuts gnome-shortcuts
+`
+
+func runCompletion(args []string) error {
+	shell := "bash"
+	if len(args) > 0 {
+		shell = args[0]
+	}
+	switch shell {
+	case "bash":
+		io.WriteString(os.Stdout, bashCompletion)
+	case "zsh":
+		io.WriteString(os.Stdout, zshCompletion)
+	default:
+		return fmt.Errorf("completion: unsupported shell %q (bash, zsh)", shell)
+	}
+	return nil
+}
+
+// runComplete gets the words after the program name; the last one is
+// the word being completed (possibly empty, or opened with a quote).
+func runComplete(args []string) error {
+	complete(os.Stdout, args)
+	return nil
+}
+
+func complete(w io.Writer, args []string) {
+	if len(args) == 0 {
+		args = []string{""}
+	}
+	cur := strings.TrimLeft(args[len(args)-1], `'"`)
+	var cands []string
+	c, ok := commands[args[0]]
+	switch {
+	case len(args) == 1:
+		for n := range commands {
+			if !strings.HasPrefix(n, "__") {
+				cands = append(cands, n)
+			}
+		}
+		if strings.HasPrefix(cur, "-") {
+			cands = flagNames(commands["list"])
+		}
+	case !ok:
+	case strings.HasPrefix(cur, "-"):
+		cands = flagNames(c)
+	case c.args != nil:
+		var prev []string
+		for _, a := range args[1 : len(args)-1] {
+			if !strings.HasPrefix(a, "-") {
+				prev = append(prev, a)
+			}
+		}
+		cands = c.args(prev, cur)
+	}
+	printMatches(w, cands, cur)
+}
+
+func flagNames(c command) []string {
+	fs := flag.NewFlagSet("", flag.ContinueOnError)
+	c.addFlags(fs)
+	var out []string
+	fs.VisitAll(func(f *flag.Flag) { out = append(out, "--"+f.Name) })
+	return out
+}
+
+func printMatches(w io.Writer, cands []string, cur string) {
+	sort.Strings(cands)
+	last := ""
+	for _, c := range cands {
+		if strings.HasPrefix(c, cur) && c != last {
+			fmt.Fprintln(w, c)
+			last = c
+		}
+	}
+}
+
+// namedKeys complements the character keys in qwertyRows.
+var namedKeys = []string{
+	"Return", "Escape", "space", "Tab", "BackSpace", "Delete", "Insert",
+	"Home", "End", "Page_Up", "Page_Down", "Left", "Right", "Up", "Down",
+	"Print", "Pause", "Scroll_Lock", "Menu", "Above_Tab",
+	"KP_0", "KP_1", "KP_2", "KP_3", "KP_4", "KP_5", "KP_6", "KP_7", "KP_8", "KP_9",
+	"KP_Enter", "KP_Add", "KP_Subtract", "KP_Multiply", "KP_Divide", "KP_Decimal",
+}
+
+func accelCandidates(cur string) []string {
+	// "<Super><Sh" → prefix "<Super>", partial "<Sh"
+	i := strings.LastIndexByte(cur, '>') + 1
+	prefix, partial := cur[:i], cur[i:]
+	var out []string
+	if partial == "" || strings.HasPrefix(partial, "<") {
+		for _, t := range modTokens {
+			if !strings.Contains(prefix, t) {
+				out = append(out, prefix+t)
+			}
+		}
+		out = append(out, prefix+"<Primary>")
+	}
+	if !strings.HasPrefix(partial, "<") {
+		for _, r := range qwertyRows {
+			for _, k := range r {
+				out = append(out, prefix+k)
+			}
+		}
+		for _, k := range namedKeys {
+			out = append(out, prefix+k)
+		}
+	}
+	for _, c := range boundKeys() {
+		if a, ok := parseAccel(c.spec); ok {
+			out = append(out, a.spec())
+		}
+	}
+	return out
+}
+
+// accelArgs completes arguments that are all accelerators.
+func accelArgs(_ []string, cur string) []string { return accelCandidates(cur) }
+
+// setArgs completes set SCHEMA KEY ACCEL from the keys holding bindings.
+func setArgs(prev []string, cur string) []string {
+	var out []string
+	switch len(prev) {
+	case 0, 1:
+		for _, c := range boundKeys() {
+			switch {
+			case c.schema == "":
+			case len(prev) == 0:
+				out = append(out, c.schema)
+			case c.schema == prev[0]:
+				out = append(out, c.key)
+			}
+		}
+	case 2:
+		out = accelCandidates(cur)
+	}
+	return out
+}
+
+// boundKey is one claimant's binding and the key holding it.
+type boundKey struct{ spec, schema, key string }
+
+const specsCacheTTL = 30 * time.Second
+
+// boundKeys is every claimant of the table, winners and shadowed,
+// from the completion cache when it is fresh.
+func boundKeys() []boundKey {
+	file := ""
+	if dir, err := os.UserCacheDir(); err == nil {
+		file = filepath.Join(dir, "gnome-shortcuts", "complete-keys")
+	}
+	if fi, err := os.Stat(file); err == nil && time.Since(fi.ModTime()) < specsCacheTTL {
+		if db, err := os.Stat(dconfUserDB()); err != nil || db.ModTime().Before(fi.ModTime()) {
+			if b, err := os.ReadFile(file); err == nil {
+				var out []boundKey
+				for _, l := range strings.Split(string(b), "\n") {
+					if f := strings.Split(l, "\t"); len(f) == 3 {
+						out = append(out, boundKey{f[0], f[1], f[2]})
+					}
+				}
+				return out
+			}
+		}
+	}
+
+	var out []boundKey
+	var cache strings.Builder
+	rows, _, _ := collect(modLabels(kbPC))
+	for _, r := range rows {
+		for _, c := range append([]row{r}, r.lost...) {
+			out = append(out, boundKey{c.spec, c.schema, c.key})
+			fmt.Fprintf(&cache, "%s\t%s\t%s\n", c.spec, c.schema, c.key)
+		}
+	}
+	if file != "" && os.MkdirAll(filepath.Dir(file), 0o755) == nil {
+		os.WriteFile(file, []byte(cache.String()), 0o644)
+	}
+	return out
+}
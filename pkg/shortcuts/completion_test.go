@@ -0,0 +1,108 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestAccelCandidates(t *testing.T) {
+	saved := defaultsOnly
+	t.Cleanup(func() { defaultsOnly = saved })
+	withSchemaDir(t, nil)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defaultsOnly = true
+
+	for _, c := range []struct {
+		cur       string
+		has, lack []string
+	}{
+		{"", []string{"<Super>", "<Primary>", "q", "Page_Up"}, nil},
+		{"<Super>", []string{"<Super><Shift>", "<Super>h", "<Super>KP_7"}, []string{"<Super><Super>"}},
+		{"<Super><Sh", []string{"<Super><Shift>", "<Super><Control>"}, []string{"<Super>h"}},
+	} {
+		got := accelCandidates(c.cur)
+		for _, w := range c.has {
+			if !slices.Contains(got, w) {
+				t.Errorf("accelCandidates(%q) lacks %q", c.cur, w)
+			}
+		}
+		for _, w := range c.lack {
+			if slices.Contains(got, w) {
+				t.Errorf("accelCandidates(%q) has %q", c.cur, w)
+			}
+		}
+	}
+}
+
+func TestComplete(t *testing.T) {
+	saved := defaultsOnly
+	t.Cleanup(func() { defaultsOnly = saved })
+	withSchemaDir(t, nil)
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	defaultsOnly = true
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"get", "<Super><Sh"}, "<Super><Shift>\n"},
+		{[]string{"get", "'<Super><Sh"}, "<Super><Shift>\n"},
+		{[]string{"get", "<Super>Page_"}, "<Super>Page_Down\n<Super>Page_Up\n"},
+		{[]string{"get", "<Alt>KP_Ad"}, "<Alt>KP_Add\n"},
+		{[]string{"get", "--js"}, "--json\n"},
+		{[]string{"comp"}, "compare\ncompletion\n"},
+		{[]string{"nonsense", "x"}, ""},
+	} {
+		var b strings.Builder
+		complete(&b, c.args)
+		if b.String() != c.want {
+			t.Errorf("complete(%q) = %q, want %q", c.args, b.String(), c.want)
+		}
+	}
+}
+
+// TestCompleteArgs completes set, explain and disable from the fixture,
+// then from the cache the first TAB left behind.
+func TestCompleteArgs(t *testing.T) {
+	dir := filepath.Join("testdata", "golden", "gnome46-wayland")
+	savedDump, savedDir, savedCache := dumpOpt, schemaDirOpt, schemaCache
+	t.Cleanup(func() { dumpOpt, schemaDirOpt, schemaCache = savedDump, savedDir, savedCache })
+	d, err := readDump(filepath.Join(dir, "dump.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpOpt, schemaDirOpt, schemaCache = d, filepath.Join(dir, "schemas"), map[string]*schemaInfo{}
+	cache := t.TempDir()
+	t.Setenv("XDG_CACHE_HOME", cache)
+
+	for _, c := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"set", "org.gnome.desktop.wm.keyb"}, "org.gnome.desktop.wm.keybindings\n"},
+		{[]string{"set", "org.gnome.desktop.wm.keybindings", "panel-"}, "panel-run-dialog\n"},
+		{[]string{"set", "org.gnome.desktop.wm.keybindings", "panel-run-dialog", "<Super><Sh"}, "<Super><Shift>\n"},
+		{[]string{"explain", "<Control><Shift>q"}, "<Control><Shift>q\n"},
+		{[]string{"disable", "--dry-run", "<Super>Hom"}, "<Super>Home\n"},
+		{[]string{"disable", "--dry"}, "--dry-run\n"},
+	} {
+		var b strings.Builder
+		complete(&b, c.args)
+		if b.String() != c.want {
+			t.Errorf("complete(%q) = %q, want %q", c.args, b.String(), c.want)
+		}
+	}
+
+	file := filepath.Join(cache, "gnome-shortcuts", "complete-keys")
+	if err := os.WriteFile(file, []byte("<Super>F7\torg.test\tcached\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	complete(&b, []string{"set", "org.test", ""})
+	if b.String() != "cached\n" {
+		t.Errorf("with a fresh cache, complete = %q, want its key", b.String())
+	}
+}
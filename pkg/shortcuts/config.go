@@ -0,0 +1,174 @@
+package shortcuts
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*──────────── configuration archive ─────────────
+
+`config export` packs everything the tool keeps
+for a user into one .tar.gz:
+
+	config/…   $XDG_CONFIG_HOME/gnome-shortcuts
+	state/…    $XDG_STATE_HOME/gnome-shortcuts
+
+`config import` unpacks it; progress.json is
+merged (and renamed keys translated) rather than
+overwritten, every other file is replaced.
+*/
+
+func init() {
+	commands["config"] = command{
+		help: "export (FILE|-) or import (FILE|-) the tool's config and state",
+		run:  runConfig,
+	}
+}
+
+func configDir() string {
+	cfg, _ := os.UserConfigDir()
+	return filepath.Join(cfg, "gnome-shortcuts")
+}
+
+// archiveRoots maps archive prefixes to directories on disk.
+func archiveRoots() map[string]string {
+	return map[string]string{"config": configDir(), "state": stateDir()}
+}
+
+func runConfig(args []string) error {
+	if len(args) < 1 {
+		return fmt.Errorf("config: want export or import")
+	}
+	file := "-"
+	if len(args) > 1 {
+		file = args[1]
+	}
+	switch args[0] {
+	case "export":
+		w := io.Writer(os.Stdout)
+		if file != "-" {
+			f, err := os.Create(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			w = f
+		}
+		n, err := exportConfig(w)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "exported %d file(s)\n", n)
+		return nil
+	case "import":
+		r := io.Reader(os.Stdin)
+		if file != "-" {
+			f, err := os.Open(file)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			r = f
+		}
+		return importConfig(r)
+	}
+	return fmt.Errorf("config: unknown action %q (want export or import)", args[0])
+}
+
+func exportConfig(w io.Writer) (int, error) {
+	zw := gzip.NewWriter(w)
+	tw := tar.NewWriter(zw)
+	n := 0
+	for _, prefix := range sortedKeys(archiveRoots()) {
+		root := archiveRoots()[prefix]
+		err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !d.Type().IsRegular() || strings.HasSuffix(p, ".tmp") {
+				return nil
+			}
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			rel, _ := filepath.Rel(root, p)
+			hdr := &tar.Header{
+				Name:    path.Join(prefix, filepath.ToSlash(rel)),
+				Mode:    0o644,
+				Size:    int64(len(data)),
+				ModTime: time.Now(),
+			}
+			if err := tw.WriteHeader(hdr); err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			n++
+			return err
+		})
+		if err != nil {
+			return n, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return n, err
+	}
+	return n, zw.Close()
+}
+
+func importConfig(r io.Reader) error {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("config import: %w", err)
+	}
+	tr := tar.NewReader(zr)
+	roots := archiveRoots()
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("config import: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		prefix, rel, _ := strings.Cut(name, "/")
+		root, ok := roots[prefix]
+		if !ok || rel == "" || !fs.ValidPath(rel) {
+			fmt.Fprintf(os.Stderr, "skipped %s\n", hdr.Name)
+			continue
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return err
+		}
+		if prefix == "state" && rel == "progress.json" {
+			if err := importProgress(data); err != nil {
+				return err
+			}
+			continue
+		}
+		dst := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, data, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", dst)
+	}
+}
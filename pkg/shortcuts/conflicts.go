@@ -0,0 +1,259 @@
+package shortcuts
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*─────────────── conflict report ────────────────
+
+Every chord with more than one claimant: the
+binding that fires, the ones it shadows, where each
+is defined and how to clean up the dead entries.
+*/
+
+type claimant struct {
+	App        string   `json:"app"`
+	Action     string   `json:"action"`
+	Spec       string   `json:"spec"`
+	Schema     string   `json:"schema,omitempty"`
+	Key        string   `json:"key,omitempty"`
+	SchemaFile string   `json:"schema_file,omitempty"`
+	Path       string   `json:"dconf_path,omitempty"`
+	Locked     bool     `json:"locked,omitempty"`
+	AppLocal   bool     `json:"app_local,omitempty"`
+	Source     string   `json:"source,omitempty"`
+	Free       []string `json:"free_alternatives,omitempty"`
+}
+
+type conflict struct {
+	Shortcut string     `json:"shortcut"`
+	Winner   claimant   `json:"winner"`
+	Losers   []claimant `json:"shadowed"`
+	Suggest  []string   `json:"suggested_resolutions"`
+}
+
+var conflictsOpt struct {
+	format string
+	notify bool // -notify-conflicts, see notify.go
+}
+
+func init() {
+	commands["conflicts"] = command{
+		help: "chords claimed by several actions (-format text|json|md)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&conflictsOpt.format, "format", "text", "output format: text, json or md")
+			fs.BoolVar(&conflictsOpt.notify, "notify-conflicts", false, "notify about conflicts that are new since the last run, for a systemd timer")
+			conflictFlag(fs)
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runConflicts,
+	}
+}
+
+func toClaimant(r row) claimant {
+	c := claimant{App: r.app, Action: r.action, Spec: r.spec, Schema: r.schema, Key: r.key,
+		Path: dconfPath(r.schema, r.key), Locked: r.locked, AppLocal: r.rank >= appRank,
+		Source: r.source}
+	if r.schema != "" {
+		c.SchemaFile = lookupSchema(r.schema).file
+	}
+	if r.src != "" {
+		c.SchemaFile = r.src
+	}
+	return c
+}
+
+// findConflicts builds the report from resolved rows.
+func findConflicts(rows []row, lbl map[string]string) []conflict {
+	taken := map[string]bool{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok {
+			taken[a.spec()] = true
+		}
+	}
+
+	var out []conflict
+	for _, r := range rows {
+		if len(r.lost) == 0 {
+			continue
+		}
+		c := conflict{Shortcut: r.accel, Winner: toClaimant(r)}
+		for _, l := range r.lost {
+			lc := toClaimant(l)
+			if a, ok := parseAccel(l.spec); ok {
+				for _, f := range suggestFree(a, taken, 3) {
+					acc, _ := fmtAccel(f.spec(), lbl)
+					lc.Free = append(lc.Free, acc)
+				}
+			}
+			c.Losers = append(c.Losers, lc)
+			if l.schema != "" && !l.locked {
+				c.Suggest = append(c.Suggest, unbindCmd(l))
+			}
+		}
+		out = append(out, c)
+	}
+	return out
+}
+
+// scalarOff is the "unbound" value of schemas whose keys hold a single
+// string rather than a list.
+func scalarOff(schema string) (string, bool) {
+	switch {
+	case strings.HasPrefix(schema, terminalKeys), strings.HasPrefix(schema, marcoPrefix):
+		return "'disabled'", true
+	case strings.HasPrefix(schema, cinnamonCustom+":"): // 'as'
+		return "", false
+	case strings.Contains(schema, ":"), schema == mateMediaKeys:
+		return "''", true
+	}
+	return "", false
+}
+
+// unbindCmd is the gsettings call that drops only spec l from its key.
+func unbindCmd(l row) string {
+	v := unbindValue(l)
+	if _, ok := scalarOff(l.schema); ok {
+		return fmt.Sprintf("gsettings set %s %s %s", l.schema, l.key, v)
+	}
+	return fmt.Sprintf(`gsettings set %s %s "%s"`, l.schema, l.key, v)
+}
+
+// unbindValue is l's key without spec l; for xkb-options, without only
+// the option that makes XKB take it.
+func unbindValue(l row) string {
+	if off, ok := scalarOff(l.schema); ok {
+		return off
+	}
+	keep := []any{}
+	for _, s := range l.keySpecs {
+		drop := s == l.spec
+		if l.key == "xkb-options" {
+			action, spec := xkbOptionKey(s)
+			drop = action == l.action && spec == l.spec
+		}
+		if !drop {
+			keep = append(keep, s)
+		}
+	}
+	return gvText("as", keep)
+}
+
+func runConflicts([]string) error {
+	lbl := labels(conflictsOpt.format)
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+	cs := findConflicts(rows, lbl)
+	if conflictsOpt.notify {
+		return notifyConflicts(cs)
+	}
+	switch conflictsOpt.format {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		if cs == nil {
+			cs = []conflict{}
+		}
+		if err := enc.Encode(cs); err != nil {
+			return err
+		}
+	case "md", "markdown":
+		writeConflictsMD(os.Stdout, cs)
+	case "text":
+		writeConflictsText(os.Stdout, cs, colorOn())
+	default:
+		return fmt.Errorf("conflicts: unknown format %q", conflictsOpt.format)
+	}
+	return checkConflicts(rows)
+}
+
+func writeConflictsText(w io.Writer, cs []conflict, color bool) {
+	if len(cs) == 0 {
+		fmt.Fprintln(w, "No conflicting shortcuts.")
+		return
+	}
+	paint := func(code, s string) string {
+		if color {
+			return sgr(code, s)
+		}
+		return s
+	}
+	t := palette()
+	for _, c := range cs {
+		fmt.Fprintf(w, "%s\n  %s %s: %s  (%s)\n", paint(sgrBold, c.Shortcut), paint(t.winner, "✔"),
+			paint(familyColor(c.Winner.App), c.Winner.App), c.Winner.Action, where(c.Winner))
+		for _, l := range c.Losers {
+			fmt.Fprintf(w, "  %s %s: %s  (%s)\n", paint(t.conflict, "✘"), paint(familyColor(l.App), l.App), l.Action, where(l))
+			if len(l.Free) > 0 {
+				fmt.Fprintf(w, "      free nearby: %s\n", strings.Join(l.Free, ", "))
+			}
+		}
+		for _, s := range c.Suggest {
+			fmt.Fprintf(w, "    → %s\n", s)
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func writeConflictsMD(w io.Writer, cs []conflict) {
+	fmt.Fprintln(w, "# Shortcut conflicts")
+	fmt.Fprintln(w)
+	if len(cs) == 0 {
+		fmt.Fprintln(w, "No conflicting shortcuts.")
+		return
+	}
+	for _, c := range cs {
+		fmt.Fprintf(w, "## %s\n\n", c.Shortcut)
+		fmt.Fprintln(w, "| | Application | Action | Binding | Defined in | Free nearby |")
+		fmt.Fprintln(w, "|---|---|---|---|---|---|")
+		fmt.Fprintf(w, "| fires | %s | %s | `%s` | %s | |\n", c.Winner.App, c.Winner.Action, c.Winner.Spec, mdWhere(c.Winner))
+		for _, l := range c.Losers {
+			fmt.Fprintf(w, "| shadowed | %s | %s | `%s` | %s | %s |\n", l.App, l.Action, l.Spec, mdWhere(l), strings.Join(l.Free, "<br>"))
+		}
+		if len(c.Suggest) > 0 {
+			fmt.Fprintln(w, "\nSuggested resolution:\n\n```sh")
+			for _, s := range c.Suggest {
+				fmt.Fprintln(w, s)
+			}
+			fmt.Fprintln(w, "```")
+		}
+		fmt.Fprintln(w)
+	}
+}
+
+func where(c claimant) string {
+	s := "built-in"
+	switch {
+	case c.Path != "":
+		s = c.Path
+	case c.Schema != "":
+		s = c.Schema + " " + c.Key
+	case c.SchemaFile != "":
+		s = c.SchemaFile
+	}
+	if strings.HasPrefix(c.Source, "system:") {
+		s += ", set by " + sourceLabel(c.Source)
+	}
+	if c.Locked {
+		s += ", locked by the administrator"
+	}
+	return s
+}
+
+func mdWhere(c claimant) string {
+	s := "`" + where(c) + "`"
+	if c.SchemaFile != "" {
+		s += "<br>" + c.SchemaFile
+	}
+	return s
+}
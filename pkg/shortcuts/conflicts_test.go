@@ -0,0 +1,75 @@
+package shortcuts
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScalarOff(t *testing.T) {
+	for _, c := range []struct {
+		schema, want string
+		ok           bool
+	}{
+		{terminalKeys, "'disabled'", true},
+		{marcoPrefix + "window-keybindings", "'disabled'", true},
+		{mateMediaKeys, "''", true},
+		{"org.gnome.settings-daemon.plugins.media-keys.custom-keybinding:/org/gnome/settings-daemon/plugins/media-keys/custom-keybindings/custom0/", "''", true},
+		{cinnamonCustom + ":/org/cinnamon/desktop/keybindings/custom-keybindings/custom0/", "", false},
+		{"org.gnome.desktop.wm.keybindings", "", false},
+	} {
+		got, ok := scalarOff(c.schema)
+		if got != c.want || ok != c.ok {
+			t.Errorf("scalarOff(%q) = %q, %v; want %q, %v", c.schema, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestUnbindCmd(t *testing.T) {
+	for _, c := range []struct {
+		l    row
+		want string
+	}{
+		{row{schema: "org.gnome.desktop.wm.keybindings", key: "close", spec: "<Alt>F4",
+			keySpecs: []string{"<Super>q", "<Alt>F4"}},
+			`gsettings set org.gnome.desktop.wm.keybindings close "['<Super>q']"`},
+		{row{schema: "org.gnome.desktop.wm.keybindings", key: "close", spec: "<Alt>F4",
+			keySpecs: []string{"<Alt>F4"}},
+			`gsettings set org.gnome.desktop.wm.keybindings close "@as []"`},
+		{row{schema: terminalKeys, key: "copy", spec: "<Control><Shift>c"},
+			`gsettings set ` + terminalKeys + ` copy 'disabled'`},
+		{row{schema: mateMediaKeys, key: "home", spec: "<Super>e"},
+			`gsettings set ` + mateMediaKeys + ` home ''`},
+	} {
+		if got := unbindCmd(c.l); got != c.want {
+			t.Errorf("unbindCmd(%s %s) = %s\nwant %s", c.l.key, c.l.spec, got, c.want)
+		}
+	}
+}
+
+// TestFindConflictsLocked suggests no gsettings call for a loser a
+// system dconf lock holds, but still offers free chords.
+func TestFindConflictsLocked(t *testing.T) {
+	t.Setenv("KEY_LAYOUT", "pc")
+	loser := func(key string, locked bool) row {
+		return row{app: "wm", action: key, spec: "<Super>q", schema: "org.gnome.desktop.wm.keybindings",
+			key: key, keySpecs: []string{"<Super>q"}, locked: locked}
+	}
+	win := row{app: "shell", action: "Overview", accel: "Super+Q", spec: "<Super>q",
+		lost: []row{loser("close", true), loser("minimize", false)}}
+	cs := findConflicts([]row{win}, labelsOn(kbPC, "text"))
+	if len(cs) != 1 || len(cs[0].Losers) != 2 {
+		t.Fatalf("findConflicts = %+v, want one conflict with two losers", cs)
+	}
+	want := []string{`gsettings set org.gnome.desktop.wm.keybindings minimize "@as []"`}
+	if !slices.Equal(cs[0].Suggest, want) {
+		t.Errorf("Suggest = %q, want %q", cs[0].Suggest, want)
+	}
+	for _, l := range cs[0].Losers {
+		if len(l.Free) == 0 {
+			t.Errorf("%s: no free chords suggested", l.Key)
+		}
+	}
+	if !cs[0].Losers[0].Locked {
+		t.Error("locked loser not marked Locked")
+	}
+}
@@ -0,0 +1,201 @@
+package shortcuts
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*────────────────── dconf locks ─────────────────
+
+Administrators lock keys in the system databases
+named by the dconf profile ("system-db:local"):
+
+	/etc/dconf/db/local.d/locks/*   one path per line,
+	                                "/dir/" locks a subtree
+	/etc/dconf/db/local             compiled, ".locks" table
+
+A locked key cannot be changed by the user, so
+rebinding advice is pointless for it.  A lock also
+decides which database the value comes from: see
+provenance below.
+*/
+
+const dconfEtc = "/etc/dconf"
+
+// dconfProfile returns the system databases of the active profile.
+func dconfProfile() []string {
+	name := os.Getenv("DCONF_PROFILE")
+	if name == "" {
+		name = "user"
+	}
+	candidates := []string{name}
+	if !filepath.IsAbs(name) {
+		candidates = []string{
+			filepath.Join(dconfEtc, "profile", name),
+			filepath.Join("/usr/share/dconf/profile", name),
+		}
+	}
+	for _, p := range candidates {
+		f, err := os.Open(p)
+		if err != nil {
+			continue
+		}
+		defer f.Close()
+		var dbs []string
+		sc := bufio.NewScanner(f)
+		for sc.Scan() {
+			if db, ok := strings.CutPrefix(strings.TrimSpace(sc.Text()), "system-db:"); ok {
+				dbs = append(dbs, db)
+			}
+		}
+		return dbs
+	}
+	return nil // no profile: user database only, nothing locked
+}
+
+var lockCache [][]string // locked paths of each dconfProfile database
+
+func dconfLocks() [][]string {
+	if lockCache != nil {
+		return lockCache
+	}
+	lockCache = [][]string{}
+	if remote() || fromDump() {
+		return lockCache
+	}
+	for _, db := range dconfProfile() {
+		var locks []string
+		base := filepath.Join(dconfEtc, "db", db)
+		files, _ := filepath.Glob(filepath.Join(base+".d", "locks", "*"))
+		for _, f := range files {
+			data, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			for _, l := range strings.Split(string(data), "\n") {
+				if l = strings.TrimSpace(l); strings.HasPrefix(l, "/") {
+					locks = append(locks, l)
+				}
+			}
+		}
+		if t, err := openGVDB(base); err == nil {
+			if tbl, ok := t.table(".locks"); ok {
+				locks = append(locks, tbl.names()...)
+			}
+		}
+		lockCache = append(lockCache, locks)
+	}
+	return lockCache
+}
+
+// lockLevel is 1 + the index in dconfProfile of the last database
+// locking path, 0 when none does.  dconf reads path from that
+// database down, so the user and the databases above it are skipped.
+func lockLevel(path string) int {
+	if path == "" {
+		return 0
+	}
+	dbs := dconfLocks()
+	for i := len(dbs) - 1; i >= 0; i-- {
+		for _, l := range dbs[i] {
+			if l == path || strings.HasSuffix(l, "/") && strings.HasPrefix(path, l) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+func isLocked(path string) bool { return lockLevel(path) > 0 }
+
+// dconfPath is where schema (or "id:/path/") keeps key, "" if unknown.
+func dconfPath(schema, key string) string {
+	if schema == "" {
+		return ""
+	}
+	dir := lookupSchema(schema).path
+	if i := strings.IndexByte(schema, ':'); i >= 0 { // relocatable
+		dir = schema[i+1:]
+	}
+	if dir == "" {
+		return ""
+	}
+	return dir + key
+}
+
+/*────────────────── provenance ──────────────────
+
+Where a key's current value comes from.  dconf
+reads the user database first, then the system
+databases in profile order; a lock makes it start
+at the locking database, skipping the ones above,
+and the lowest lock in the profile wins.
+
+	user            ~/.config/dconf/user
+	system:<db>     /etc/dconf/db/<db>
+	default         nobody set it (schema or override)
+*/
+
+var dconfCache = map[string]*gvdbTable{}
+
+func dconfDB(path string) *gvdbTable {
+	if t, ok := dconfCache[path]; ok {
+		return t
+	}
+	t, err := openGVDB(path)
+	if !errors.Is(err, fs.ErrNotExist) {
+		diagnose(err)
+	}
+	dconfCache[path] = t
+	return t
+}
+
+func dconfUserDB() string {
+	cfg, _ := os.UserConfigDir()
+	return filepath.Join(cfg, "dconf", "user")
+}
+
+func dconfSource(path string) string {
+	if path == "" || remote() || fromDump() {
+		return ""
+	}
+	if defaultsOnly {
+		return "default"
+	}
+	if _, src, ok := dconfLookup(path); ok {
+		return src
+	}
+	return "default"
+}
+
+// sourceLabel is dconfSource for people.
+func sourceLabel(src string) string {
+	if db, ok := strings.CutPrefix(src, "system:"); ok {
+		return "system (" + db + ")"
+	}
+	return src
+}
+
+// dconfList names the keys and subdirs ("x/") under dir.
+func dconfList(dir string) []string {
+	if defaultsOnly {
+		return nil
+	}
+	if fromDump() {
+		return dumpOpt.list(dir)
+	}
+	if native() {
+		return nativeList(dir)
+	}
+	ctx, cancel := toolContext()
+	defer cancel()
+	out, err := toolCmd(ctx, "dconf", "list", dir).Output()
+	if err != nil {
+		diagnose(toolError(err, "dconf", "list", dir))
+	}
+	return strings.Fields(string(out))
+}
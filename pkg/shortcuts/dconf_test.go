@@ -0,0 +1,30 @@
+package shortcuts
+
+import "testing"
+
+func TestLockLevel(t *testing.T) {
+	saved := lockCache
+	t.Cleanup(func() { lockCache = saved })
+	lockCache = [][]string{ // profile: system-db:local, system-db:site
+		{"/org/gnome/desktop/wm/keybindings/"},
+		{"/org/gnome/desktop/wm/keybindings/close", "/org/gnome/shell/"},
+	}
+	for _, c := range []struct {
+		path string
+		want int
+	}{
+		{"/org/gnome/desktop/wm/keybindings/close", 2}, // the lowest lock wins
+		{"/org/gnome/desktop/wm/keybindings/minimize", 1},
+		{"/org/gnome/shell/keybindings/toggle-overview", 2},
+		{"/org/gnome/mutter/overlay-key", 0},
+		{"/org/gnome/desktop/wm/keybindings", 0}, // the dir itself is not a key under it
+		{"", 0},
+	} {
+		if got := lockLevel(c.path); got != c.want {
+			t.Errorf("lockLevel(%q) = %d, want %d", c.path, got, c.want)
+		}
+		if isLocked(c.path) != (c.want > 0) {
+			t.Errorf("isLocked(%q) = %v", c.path, !(c.want > 0))
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package shortcuts
+
+import (
+	"bytes"
+	"fmt"
+)
+
+/*──────────────── defaults only ─────────────────
+
+-defaults answers every gsettings query from the
+compiled schema defaults instead, so the table
+can be built in chroots, CI images and doc builds
+without a session bus.  The output has the shape
+of `gsettings list-recursively`.
+*/
+
+var defaultsOnly bool
+
+func defaultsDump(schema ...string) []byte {
+	ids := schema
+	if len(ids) == 0 {
+		// like gsettings: relocatable schemas only on request
+		for _, id := range compiledSchemaIDs() {
+			if lookupSchema(id).path != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	var b bytes.Buffer
+	for _, id := range ids {
+		d := lookupSchema(id).defaults
+		for _, k := range sortedKeys(d) {
+			fmt.Fprintf(&b, "%s %s %s\n", id, k, d[k])
+		}
+	}
+	return b.Bytes()
+}
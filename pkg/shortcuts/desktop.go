@@ -0,0 +1,128 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*─────────────────── desktops ───────────────────
+
+-desktop picks where bindings come from.  GNOME
+(with Cinnamon and MATE, which share its
+gsettings model) is collect's own path; other
+desktops register a collector here that reads
+their config and returns resolved rows.
+*/
+
+var (
+	desktopOpt  = "gnome"
+	desktopList []string // every -desktop given, for compare
+	wmConfig    string   // -wm-config, instead of the desktop's default file
+)
+
+var desktops = map[string]func(lbl map[string]string) []row{}
+
+func desktopNames() []string {
+	return append([]string{"gnome"}, sortedKeys(desktops)...)
+}
+
+// configFiles is what the config-file parsers share: $variables, and
+// include (source) lines followed with each file read once.
+type configFiles struct {
+	vars map[string]string
+	seen map[string]bool
+}
+
+func newConfigFiles() configFiles {
+	return configFiles{vars: map[string]string{}, seen: map[string]bool{}}
+}
+
+// expand replaces $vars, longest name first so $mod does not eat $mod2.
+func (c *configFiles) expand(s string) string {
+	names := sortedKeys(c.vars)
+	sort.SliceStable(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+	for _, n := range names {
+		s = strings.ReplaceAll(s, n, c.vars[n])
+	}
+	return s
+}
+
+// open opens path unless it was read already (or 64 files were, in
+// case of an include loop through links); then it is nil, nil.
+func (c *configFiles) open(path string) (*os.File, error) {
+	if c.seen[path] || len(c.seen) > 64 {
+		return nil, nil
+	}
+	c.seen[path] = true
+	return os.Open(path)
+}
+
+// include reads every file the glob pat matches with read; pat is
+// relative to the including file's dir, or to home after "~/".
+func (c *configFiles) include(from, pat string, read func(path string) error) {
+	if rest, ok := strings.CutPrefix(pat, "~/"); ok {
+		home, _ := os.UserHomeDir()
+		pat = filepath.Join(home, rest)
+	} else if !filepath.IsAbs(pat) {
+		pat = filepath.Join(filepath.Dir(from), pat)
+	}
+	matches, _ := filepath.Glob(pat)
+	for _, m := range matches {
+		read(m)
+	}
+}
+
+// tilingBind is one binding line of a config-file desktop; opts are
+// the i3/Sway options before the chord (--release, --to-code, …).
+type tilingBind struct {
+	mode, spec, cmd, src string
+	opts                 []string
+}
+
+// bindRows formats binds as rows of app ("app (mode)" inside a mode).
+func bindRows(app string, binds []tilingBind, lbl map[string]string) []row {
+	var rows []row
+	for i, b := range binds {
+		acc, ok := fmtKey(b.spec, lbl)
+		if !ok {
+			continue
+		}
+		name := app
+		if b.mode != "" && b.mode != "default" {
+			name += " (" + b.mode + ")"
+		}
+		rows = append(rows, row{accel: acc, app: name, action: b.cmd,
+			order: i, spec: b.spec, src: b.src})
+	}
+	return rows
+}
+
+// desktopClaim resolves rows the way config-file desktops do: a chord
+// bound again replaces the earlier line, and each mode is its own map.
+func desktopClaim(rows []row) []row {
+	chosen := map[string]row{}
+	var keys []string
+	for _, r := range rows {
+		a, _ := parseAccel(r.spec)
+		k := r.app + "\x00" + a.spec()
+		if old, ok := chosen[k]; ok {
+			r.lost = append(append(r.lost, old.lost...), withoutLost(old))
+		} else {
+			keys = append(keys, k)
+		}
+		chosen[k] = r
+	}
+	sort.Strings(keys)
+	out := make([]row, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, chosen[k])
+	}
+	return out
+}
+
+func withoutLost(r row) row {
+	r.lost = nil
+	return r
+}
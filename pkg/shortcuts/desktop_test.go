@@ -0,0 +1,114 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestConfigIncludes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"sway/config":        "set $mod Mod4\nset $mod2 Mod1\nbindsym $mod+q kill\ninclude config.d/*\ninclude config\n",
+		"sway/config.d/keys": "bindsym $mod2+Return exec foot\n",
+		"hypr/hyprland.conf": "$m = SUPER\n$m2 = ALT\nbind = $m, Q, killactive,\nsource = keys.conf\nsource = hyprland.conf\n",
+		"hypr/keys.conf":     "bind = $m2, Return, exec, foot\n",
+	})
+	specs := func(binds []tilingBind) []string {
+		var out []string
+		for _, b := range binds {
+			out = append(out, b.spec+" "+b.cmd)
+		}
+		return out
+	}
+
+	sway := &tilingParser{configFiles: newConfigFiles()}
+	if err := sway.file(filepath.Join(dir, "sway/config")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := specs(sway.binds), []string{"<Super>q kill", "<Alt>Return exec foot"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("sway binds = %q, want %q", got, want)
+	}
+
+	hypr := &hyprParser{configFiles: newConfigFiles()}
+	if err := hypr.file(filepath.Join(dir, "hypr/hyprland.conf")); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := specs(hypr.binds), []string{"<Super>Q killactive", "<Alt>Return exec foot"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hyprland binds = %q, want %q", got, want)
+	}
+}
+
+func TestTilingModes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config": `set $mod Mod4
+set $mode_system System (l) lock, (e) exit, (s) shutdown
+bindsym $mod+r mode "resize"
+mode --pango_markup "resize" {
+    bindsym Left resize shrink width 10px
+}
+mode "System (l) lock, (e) exit" {
+    bindsym l exec loginctl lock-session
+}
+mode "$mode_system" {
+    bindsym s exec systemctl poweroff
+    bindsym Escape mode "default"
+}
+`})
+	p := &tilingParser{configFiles: newConfigFiles()}
+	if err := p.file(filepath.Join(dir, "config")); err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, b := range p.binds {
+		got = append(got, b.mode+": "+b.spec)
+	}
+	want := []string{
+		": <Super>r",
+		"resize: Left",
+		"System (l) lock, (e) exit: l",
+		"System (l) lock, (e) exit, (s) shutdown: s",
+		"System (l) lock, (e) exit, (s) shutdown: Escape",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("modes =\n%q\nwant\n%q", got, want)
+	}
+}
+
+func TestCompareKDE(t *testing.T) {
+	kde := writeFiles(t, map[string]string{"kglobalshortcutsrc": `[kwin]
+_k_friendly_name=KWin
+Window Minimize=Meta+H,Meta+PgDown,Minimize Window
+Switch to Desktop 1=Meta+End,Ctrl+F1,Switch to Desktop 1
+Window Close=Alt+F4\tMeta+Q,Alt+F4,Close Window
+`})
+	dir, _ := filepath.Abs(filepath.Join("testdata", "golden", "gnome46-wayland"))
+	out := runFixture(t, dir, []string{"compare", "-session", "wayland", "-shell-version", "46.0",
+		"-desktop", "gnome", "-desktop", "kde", "-wm-config", filepath.Join(kde, "kglobalshortcutsrc")})
+	got := regexp.MustCompile(` {2,}`).ReplaceAllString(string(out), "|") // columns, whatever their width
+	for _, want := range []string{
+		"Window Manager: Minimize|Win (Caps) + H|Win (Caps) + H\n",
+		"Window Manager: Switch To Workspace 1|Win (Caps) + 1, Win (Caps) + Home|Win (Caps) + End|≠\n",
+		"KWin: Close Window|—|Alt + F4, Win (Caps) + Q|gap\n",
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("compare lacks %q:\n%s", want, got)
+		}
+	}
+}
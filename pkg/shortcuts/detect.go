@@ -0,0 +1,120 @@
+package shortcuts
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+)
+
+/*─────────────── keyboard detection ───────────────
+
+Before asking Apple, PC or Chromebook, the tool
+looks: each keyboard the kernel knows says who made
+it (USB vendor 05ac is Apple, 18d1 Google, and the
+names agree), and on a laptop the machine's own
+maker, from DMI, speaks for the built-in keyboard
+(an x86 Chromebook's is a plain AT one); hostnamectl
+chassis says whether it is a laptop.
+Anything else with keys is a PC keyboard.  When
+the clues agree the tool goes with them, and falls
+back to PC when they disagree or there are none.
+KEY_LAYOUT and config.toml still come first.
+*/
+
+const (
+	appleVendor  = "05ac"
+	googleVendor = "18d1"
+	busVirtual   = "0006" // uinput: remappers, ydotool
+)
+
+// busBuiltIn are the buses a laptop's own keyboard sits on: i8042,
+// I²C, the embedded controller, SPI.
+var busBuiltIn = []string{"0011", "0018", "0019", "001c"}
+
+// notKeyboards are names of devices that have keys but are not what
+// anyone types on: hotkey drivers, security keys, remotes.
+var notKeyboards = []string{
+	"button", "hotkey", "wmi", "hid events", "video bus", "consumer control",
+	"system control", "yubikey", "extra buttons", "lid switch", "receiver mouse",
+}
+
+// laptopChassis are hostnamectl's chassis for a machine with a keyboard
+// of its own.
+var laptopChassis = []string{"laptop", "convertible", "tablet", "handset"}
+
+// detectLayout guesses the keyboard; ok is false when the clues
+// disagree or there are none.  why names them.
+func detectLayout() (k kb, why string, ok bool) {
+	votes := map[kb][]string{}
+	own := false // the machine's maker speaks for its built-in keyboard
+	if slices.Contains(laptopChassis, chassis()) {
+		vendor, _ := os.ReadFile("/sys/class/dmi/id/sys_vendor")
+		switch v := strings.TrimSpace(string(vendor)); {
+		case strings.HasPrefix(v, "Apple"):
+			votes[kbApple], own = append(votes[kbApple], "an "+v+" laptop"), true
+		case v == "Google":
+			votes[kbChrome], own = append(votes[kbChrome], "a Chromebook"), true
+		}
+	}
+	for _, d := range inputKeyboards() {
+		if own && slices.Contains(busBuiltIn, d.bus) {
+			continue
+		}
+		if c, ok := deviceLayout(d); ok {
+			votes[c] = append(votes[c], d.name)
+		}
+	}
+	if len(votes) != 1 {
+		return kbPC, "", false
+	}
+	for c, names := range votes {
+		slices.Sort(names)
+		k, why = c, strings.Join(slices.Compact(names), ", ")
+	}
+	return k, why, true
+}
+
+// deviceLayout is what d says about the keyboard; ok is false when it
+// is not one to type on.
+func deviceLayout(d inputDevice) (kb, bool) {
+	name := strings.ToLower(d.name)
+	if d.bus == busVirtual || slices.ContainsFunc(notKeyboards, func(n string) bool { return strings.Contains(name, n) }) {
+		return 0, false
+	}
+	switch {
+	case d.vendor == appleVendor || strings.Contains(name, "apple"):
+		return kbApple, true
+	case d.vendor == googleVendor || strings.Contains(name, "cros_ec") || strings.Contains(name, "chromebook"):
+		return kbChrome, true
+	}
+	return kbPC, true
+}
+
+// chassis is hostnamectl's chassis ("laptop", "desktop"…), else DMI's
+// chassis type turned into one, else "".
+func chassis() string {
+	ctx, cancel := toolContext()
+	defer cancel()
+	if out, err := exec.CommandContext(ctx, "hostnamectl", "chassis").Output(); err == nil {
+		return strings.TrimSpace(string(out))
+	}
+	data, err := os.ReadFile("/sys/class/dmi/id/chassis_type")
+	if err != nil {
+		return ""
+	}
+	var n int
+	fmt.Sscan(string(data), &n)
+	switch n { // SMBIOS 3.x
+	case 8, 9, 10, 14:
+		return "laptop"
+	case 31:
+		return "convertible"
+	case 30, 32:
+		return "tablet"
+	case 3, 4, 5, 6, 7, 13, 15, 16, 35, 36:
+		return "desktop"
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package shortcuts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+)
+
+/*──────────────── saved dumps ──────────────────
+
+-from-dump FILE answers every settings query from
+a saved `gsettings list-recursively`, so the whole
+pipeline runs against a fixture.  Relocatable
+instances may follow as "id:/path/ key value"
+lines; keys the file leaves out read as schema
+defaults, found under -schema-dir if given.  Like
+-host, it reads nothing else of this machine: no
+dconf locks or sources, remaps, xbindkeys, GTK
+accels files or logind.conf.
+*/
+
+// savedDump is a -from-dump file, parsed.
+type savedDump struct {
+	file    string
+	entries []entry
+	vals    map[string]string // "schema key" → value
+}
+
+var dumpOpt *savedDump
+
+func readDump(file string) (*savedDump, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	d := &savedDump{file: file, entries: parseDump(data), vals: map[string]string{}}
+	if len(d.entries) == 0 {
+		return nil, &ParseError{File: file, Err: fmt.Errorf("no \"schema key value\" lines")}
+	}
+	for _, e := range d.entries {
+		d.vals[e.schema+" "+e.key] = e.val
+	}
+	return d, nil
+}
+
+// fromDump reports whether settings come from a saved dump.
+func fromDump() bool { return dumpOpt != nil }
+
+// dump is gsettingsDump: the schemas with a fixed path, or the ones
+// named; a named one missing from the file reads as its defaults.
+func (d *savedDump) dump(schema ...string) []byte {
+	var b bytes.Buffer
+	for _, e := range d.entries {
+		if len(schema) == 0 && !strings.Contains(e.schema, ":") || slices.Contains(schema, e.schema) {
+			fmt.Fprintf(&b, "%s %s %s\n", e.schema, e.key, e.val)
+		}
+	}
+	for _, id := range schema {
+		if !d.has(id) {
+			b.Write(defaultsDump(id))
+		}
+	}
+	return b.Bytes()
+}
+
+func (d *savedDump) has(schema string) bool {
+	for _, e := range d.entries {
+		if e.schema == schema {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *savedDump) get(schema, key string) string {
+	if v, ok := d.vals[schema+" "+key]; ok {
+		return v
+	}
+	return lookupSchema(schema).defaults[key]
+}
+
+// list is dconfList over the relocatable instances in the file.
+func (d *savedDump) list(dir string) []string {
+	seen := map[string]bool{}
+	for _, e := range d.entries {
+		_, path, ok := strings.Cut(e.schema, ":")
+		rest, under := strings.CutPrefix(path, dir)
+		if !ok || !under {
+			continue
+		}
+		if rest == "" {
+			rest = e.key
+		} else if i := strings.IndexByte(rest, '/'); i >= 0 {
+			rest = rest[:i+1]
+		}
+		seen[rest] = true
+	}
+	out := make([]string, 0, len(seen))
+	for n := range seen {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
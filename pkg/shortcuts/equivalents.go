@@ -0,0 +1,104 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*──────────── other systems' equivalents ────────────
+
+Tables mapping a GNOME "schema key" to the closest
+chord on another system and what it does there,
+for people arriving from it.  Chords are GTK specs
+with <Super> for the Windows or Command key; a
+trailing "-%" works as in wm_actions.tsv.
+*/
+
+type equivalent struct{ spec, action string }
+
+// system is one such table and the keyboard its chords are named for.
+type system struct {
+	title, file, data string
+	kb                kb
+}
+
+var systems = map[string]system{}
+
+func (s system) table() map[string]equivalent { return loadEquivalents(s.file, s.data) }
+
+// systemLabels names modifiers the way that keyboard prints them,
+// without the local XKB remaps.
+func systemLabels(lbl map[string]string, k kb) map[string]string {
+	out := map[string]string{"<Control>": "Ctrl", "<Shift>": "Shift", "<Alt>": "Alt", "<Super>": "Win"}
+	for kk, v := range lbl {
+		if !strings.HasPrefix(kk, "<") {
+			out[kk] = v
+		}
+	}
+	switch k {
+	case kbApple:
+		out["<Super>"], out["<Alt>"] = "Command", "Option"
+	case kbChrome:
+		out["<Super>"] = "Search"
+	}
+	return out
+}
+
+// loadEquivalents reads name from the config dir, else the embedded copy.
+func loadEquivalents(name, embedded string) map[string]equivalent {
+	file, data := name, embedded
+	cfg, _ := os.UserConfigDir()
+	user := filepath.Join(cfg, "gnome-shortcuts", name)
+	if b, err := os.ReadFile(user); err == nil {
+		file, data = user, string(b)
+	}
+	out := map[string]equivalent{}
+	for n, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 3 {
+			badLine(file, n, "want key<TAB>spec<TAB>action, got %q", l)
+			continue
+		}
+		out[f[0]] = equivalent{f[1], f[2]}
+	}
+	return out
+}
+
+// schemaKey names r the way the tables do; core rows go by their
+// backing key.
+func schemaKey(r row) string {
+	if r.schema != "" {
+		return r.schema + " " + strings.TrimSuffix(r.key, "-static")
+	}
+	if r.rank == -1 {
+		for _, s := range loadCoreShortcuts() {
+			if s.spec == r.spec && s.action == r.action {
+				return s.backing
+			}
+		}
+	}
+	return ""
+}
+
+func lookupEquivalent(m map[string]equivalent, r row) (equivalent, bool) {
+	return equivalentOf(m, schemaKey(r))
+}
+
+// equivalentOf looks k up, trying the "-%" entry for numbered keys.
+func equivalentOf(m map[string]equivalent, k string) (equivalent, bool) {
+	if e, ok := m[k]; ok && k != "" {
+		return e, true
+	}
+	n := trailingNum.FindStringSubmatch(k)
+	if n == nil {
+		return equivalent{}, false
+	}
+	e, ok := m[trailingNum.ReplaceAllString(k, "-%")]
+	e.spec = strings.ReplaceAll(e.spec, "%", n[1])
+	e.action = strings.ReplaceAll(e.action, "%", n[1])
+	return e, ok
+}
@@ -0,0 +1,107 @@
+package shortcuts
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+/*──────────────────── diagnostics ────────────────────
+
+What the tool works around instead of failing — a
+schema that is not installed, a gsettings that will
+not run, a line of a user's table it cannot read —
+goes to diagnose as one of the errors below.  They
+are dropped unless -verbose (or Options.Diagnose)
+asks for them, once each.
+*/
+
+// SchemaNotFoundError is a schema neither gsettings nor the schema
+// directories know.
+type SchemaNotFoundError struct{ Schema string }
+
+func (e *SchemaNotFoundError) Error() string {
+	return "schema " + e.Schema + " not installed"
+}
+
+// GSettingsUnavailableError is a gsettings or dconf call that failed;
+// Stderr holds what the tool said, if anything.
+type GSettingsUnavailableError struct {
+	Args   []string // tool and arguments
+	Stderr string
+	Err    error
+}
+
+func (e *GSettingsUnavailableError) Error() string {
+	msg := e.Err.Error()
+	if e.Stderr != "" {
+		msg = e.Stderr
+	}
+	return strings.Join(e.Args, " ") + ": " + msg
+}
+
+func (e *GSettingsUnavailableError) Unwrap() error { return e.Err }
+
+// ParseError is a file, or one line of it, that could not be read;
+// Line is 0 when the whole file is at fault.
+type ParseError struct {
+	File string
+	Line int
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.File, e.Line, e.Err)
+	}
+	return e.File + ": " + e.Err.Error()
+}
+
+func (e *ParseError) Unwrap() error { return e.Err }
+
+var (
+	diagnoseFn func(error) // set by -verbose and Options.Diagnose
+	diagnosed  = map[string]bool{}
+)
+
+var warnOut io.Writer = os.Stderr // Options.Warnings for the library
+
+// warn prints a problem the user should see even without -verbose: a
+// config that will not open, a collector that failed.
+func warn(err error) {
+	fmt.Fprintln(warnOut, "gnome-shortcuts: warning:", err)
+}
+
+func diagnose(err error) {
+	if err == nil || diagnoseFn == nil || diagnosed[err.Error()] {
+		return
+	}
+	diagnosed[err.Error()] = true
+	diagnoseFn(err)
+}
+
+// badLine reports line n (0-based) of a table as skipped.
+func badLine(file string, n int, format string, args ...any) {
+	diagnose(&ParseError{file, n + 1, fmt.Errorf(format, args...)})
+}
+
+// toolError types a failed `gsettings`/`dconf` call.  A missing key is
+// how optional keys are probed, so it is no error at all.
+func toolError(err error, args ...string) error {
+	var stderr string
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		stderr = strings.TrimSpace(string(ee.Stderr))
+	}
+	switch {
+	case strings.HasPrefix(stderr, "No such key"):
+		return nil
+	case strings.HasPrefix(stderr, "No such schema") && len(args) > 2:
+		id, _, _ := strings.Cut(args[2], ":") // relocatable id:/path/
+		return &SchemaNotFoundError{id}
+	}
+	return &GSettingsUnavailableError{args, stderr, err}
+}
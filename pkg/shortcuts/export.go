@@ -0,0 +1,138 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*──────────────────── export ────────────────────
+
+Resolved GNOME shortcuts in another tool's config
+format, to carry muscle memory over.  Each format
+registers a writer in exporters; actions are
+translated through wm_actions.tsv and anything
+without an equivalent is written as a comment.
+*/
+
+type exporter struct {
+	help  string
+	write func(w io.Writer, rows []row, lbl map[string]string) error
+}
+
+var exporters = map[string]exporter{}
+
+var exportOpt struct{ format string }
+
+func init() {
+	commands["export"] = command{
+		help: "write the shortcuts as another tool's config (-format sway, …)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&exportOpt.format, "format", "", "target: "+strings.Join(sortedKeys(exporters), ", "))
+			collectFlags(fs)
+		},
+		run: runExport,
+	}
+}
+
+func runExport([]string) error {
+	ex, ok := exporters[exportOpt.format]
+	if !ok {
+		return fmt.Errorf("-format: want one of %s", strings.Join(sortedKeys(exporters), ", "))
+	}
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+	var out []row
+	for _, r := range rows {
+		if r.rank < appRank && r.spec != "" { // chords GNOME itself handles
+			out = append(out, r)
+		}
+	}
+	return ex.write(os.Stdout, out, lbl)
+}
+
+/*──────── action map ────────*/
+
+//go:embed wm_actions.tsv
+var wmActionsTSV string
+
+// wmActions maps "schema key" to one command per target column.
+func wmActions() map[string][]string {
+	_, m := readWMActions()
+	return m
+}
+
+// readWMActions also returns the keys in file order, so the first of
+// several GNOME actions sharing an equivalent is the one compare maps
+// back to.
+func readWMActions() ([]string, map[string][]string) {
+	file, data := "wm_actions.tsv", wmActionsTSV
+	cfg, _ := os.UserConfigDir()
+	user := filepath.Join(cfg, "gnome-shortcuts", file)
+	if b, err := os.ReadFile(user); err == nil {
+		file, data = user, string(b)
+	}
+	var keys []string
+	out := map[string][]string{}
+	for n, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 2 {
+			badLine(file, n, "want key<TAB>actions…, got %q", l)
+			continue
+		}
+		out[f[0]] = f[1:]
+		keys = append(keys, f[0])
+	}
+	return keys, out
+}
+
+var trailingNum = regexp.MustCompile(`-(\d+)$`)
+
+// wmCommand translates r for target column col; custom shortcuts
+// become exec with fmtExec.
+func wmCommand(r row, col int, fmtExec string, actions map[string][]string) (string, bool) {
+	if isCustom(r.schema) {
+		cmd := customCommand(r.schema)
+		return fmt.Sprintf(fmtExec, cmd), cmd != ""
+	}
+	key := strings.TrimSuffix(r.key, "-static")
+	n := ""
+	cells, ok := actions[r.schema+" "+key]
+	if m := trailingNum.FindStringSubmatch(key); !ok && m != nil {
+		n = m[1]
+		cells, ok = actions[r.schema+" "+trailingNum.ReplaceAllString(key, "-%")]
+	}
+	if !ok || col >= len(cells) || cells[col] == "-" || cells[col] == "" {
+		return "", false
+	}
+	if n != "" {
+		return strings.ReplaceAll(cells[col], "%", n), true
+	}
+	return cells[col], true
+}
+
+// customCommand is what a custom shortcut runs.
+func customCommand(schema string) string {
+	cmd := gvTextString(gsettingsGet(schema, "command"))
+	if cmd == "" {
+		cmd = gvTextString(gsettingsGet(schema, "action")) // MATE
+	}
+	return cmd
+}
+
+// exportComment is the "# accel  App: Action" note above a binding.
+func exportComment(r row) string {
+	return fmt.Sprintf("# %s  %s: %s", r.accel, r.app, r.action)
+}
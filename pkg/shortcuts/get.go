@@ -0,0 +1,152 @@
+package shortcuts
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+/*──────────────── batch resolution ──────────────
+
+Resolve many accelerators in one process instead
+of forking per query.  Queries come from the
+arguments or, with -stdin, one per line.  Each
+answer is a tab-separated line
+
+	query  status  app  action  schema  key
+
+where status is bound, free or invalid; -json
+prints a single array instead.  -copy, for one
+accelerator, also puts it on the clipboard, or
+the command when a custom shortcut holds it.
+*/
+
+var getOpt struct{ stdin, json, copy bool }
+
+type resolution struct {
+	Query    string     `json:"query"`
+	Spec     string     `json:"spec,omitempty"`
+	Status   string     `json:"status"`
+	Shortcut string     `json:"shortcut,omitempty"`
+	Winner   *claimant  `json:"winner,omitempty"`
+	Shadowed []claimant `json:"shadowed,omitempty"`
+}
+
+func init() {
+	commands["get"] = command{
+		help: "resolve accelerators given as arguments or on stdin",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&getOpt.stdin, "stdin", false, "read one accelerator per line from stdin")
+			fs.BoolVar(&getOpt.json, "json", false, "print a JSON array instead of lines")
+			fs.BoolVar(&getOpt.copy, "copy", false, "copy the one accelerator given, or the command of the custom shortcut holding it")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run:  runGet,
+		args: accelArgs,
+	}
+}
+
+func resolve(q string, won map[string]row, lbl map[string]string) resolution {
+	res := resolution{Query: q, Status: "invalid"}
+	a, ok := parseAccel(q)
+	if !ok {
+		return res
+	}
+	res.Spec, res.Status = a.spec(), "free"
+	res.Shortcut, _ = fmtAccel(res.Spec, lbl)
+	if r, ok := won[res.Spec]; ok {
+		w := toClaimant(r)
+		res.Status, res.Winner = "bound", &w
+		for _, l := range r.lost {
+			res.Shadowed = append(res.Shadowed, toClaimant(l))
+		}
+	}
+	return res
+}
+
+func (r resolution) line() string {
+	f := []string{r.Query, r.Status}
+	if w := r.Winner; w != nil {
+		f = append(f, w.App, w.Action, w.Schema, w.Key)
+	}
+	return strings.Join(f, "\t")
+}
+
+func runGet(args []string) error {
+	if getOpt.stdin && len(args) > 0 {
+		return fmt.Errorf("get: give accelerators as arguments or -stdin, not both")
+	}
+	if !getOpt.stdin && len(args) == 0 {
+		return fmt.Errorf("get: no accelerators (try -stdin)")
+	}
+	if getOpt.copy && (getOpt.stdin || len(args) > 1) {
+		return fmt.Errorf("get: -copy takes one accelerator")
+	}
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	won := map[string]row{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok {
+			won[a.spec()] = r
+		}
+	}
+
+	var out []resolution
+	emit := func(q string) {
+		r := resolve(q, won, lbl)
+		if getOpt.json {
+			out = append(out, r)
+		} else {
+			fmt.Println(r.line()) // unbuffered: answers stream as lines arrive
+		}
+	}
+	if getOpt.stdin {
+		sc := bufio.NewScanner(os.Stdin)
+		for sc.Scan() {
+			if q := strings.TrimSpace(sc.Text()); q != "" {
+				emit(q)
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return err
+		}
+	}
+	for _, q := range args {
+		emit(q)
+	}
+	if getOpt.copy {
+		if err := copyResolved(args[0], won); err != nil {
+			return err
+		}
+	}
+	if !getOpt.json {
+		return nil
+	}
+	if out == nil {
+		out = []resolution{}
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(out)
+}
+
+// copyResolved copies q's winner, or q itself when the chord is free.
+func copyResolved(q string, won map[string]row) error {
+	a, ok := parseAccel(q)
+	if !ok {
+		return fmt.Errorf("get: not an accelerator: %q", q)
+	}
+	r, ok := won[a.spec()]
+	if !ok {
+		r = row{spec: a.spec()}
+	}
+	return copyText(clipValue(r))
+}
@@ -0,0 +1,1217 @@
+// Package shortcuts lists the GNOME keyboard shortcuts that actually
+// fire when several components claim the same key-combo.
+//
+// Collect, Resolve and Render are the library API (api.go); Main is
+// the gnome-shortcuts command line, built from cmd/gnome-shortcuts:
+//
+//	go build ./cmd/gnome-shortcuts
+//	KEY_LAYOUT=apple|pc|chrome ./gnome-shortcuts
+//	./gnome-shortcuts help     ← list sub-commands
+//
+// Goal
+//   - show *only* the shortcut that will actually fire
+//     when several GNOME actions share the same key-combo
+//   - no hand-written “special cases”
+//   - priority is derived from Mutter’s own gschema files
+package shortcuts
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	_ "embed"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/chzyer/readline"
+)
+
+/*──────────────── keyboard layout ───────────────*/
+
+type kb int
+
+const (
+	kbApple kb = iota
+	kbPC
+	kbChrome
+)
+
+// layoutNamed reads KEY_LAYOUT's (and config.toml's) spelling.
+func layoutNamed(s string) (kb, bool) {
+	switch strings.ToLower(s) {
+	case "apple", "mac":
+		return kbApple, true
+	case "pc", "windows":
+		return kbPC, true
+	case "chrome", "chromebook":
+		return kbChrome, true
+	}
+	if m, ok := models[modelKey(s)]; ok {
+		return m.kb, true
+	}
+	return 0, false
+}
+
+// presetLayout is the layout KEY_LAYOUT or config.toml names.
+func presetLayout() (kb, bool) {
+	if k, ok := layoutNamed(os.Getenv("KEY_LAYOUT")); ok {
+		return k, true
+	}
+	return layoutNamed(userPrefs.layout)
+}
+
+// detectedLayout is detectLayout's guess, noted under -verbose.  The
+// hardware probed is this machine's, so -host and -from-dump skip it.
+func detectedLayout() (kb, bool) {
+	if remote() || fromDump() {
+		return kbPC, false
+	}
+	k, why, ok := detectLayout()
+	if ok {
+		diagnose(fmt.Errorf("%s keyboard detected: %s; KEY_LAYOUT overrides", kbNames[k], why))
+	}
+	return k, ok
+}
+
+// layout is the preset, else the detected layout, else PC.  Nothing
+// prompts: browse's l key switches layouts, KEY_LAYOUT pins one.
+func layout() kb {
+	if k, ok := presetLayout(); ok {
+		return k
+	}
+	if k, ok := detectedLayout(); ok {
+		return k
+	}
+	diagnose(errors.New("keyboard layout unknown, assuming PC; set KEY_LAYOUT or layout in config.toml"))
+	return kbPC
+}
+
+/*────────── modifier → printable label ──────────*/
+
+func modLabels(k kb) map[string]string {
+	m := map[string]string{
+		"<Primary>": "Ctrl", "<Control>": "Ctrl", "<Ctrl>": "Ctrl",
+		"<Shift>": "Shift",
+	}
+	switch k {
+	case kbApple:
+		m["<Alt>"] = "Option"
+		m["<Super>"] = "Command"
+	case kbChrome:
+		m["<Alt>"] = "Alt"
+		m["<Super>"] = "Search"
+	default:
+		m["<Alt>"] = "Alt"
+		m["<Super>"] = "Win"
+	}
+	if k != kbApple { // Apple prints English words everywhere
+		for t, v := range localLabels() {
+			if strings.HasPrefix(t, "<") {
+				m[t] = v
+			}
+		}
+		m["<Primary>"], m["<Ctrl>"] = m["<Control>"], m["<Control>"]
+	}
+	if glyphsOnMod || keyStyle == "both" {
+		for t, g := range modGlyphs[k] {
+			if keyStyle == "both" {
+				g += " " + m[t]
+			}
+			m[t] = g
+		}
+	}
+	applyRemaps(m, keyRemaps()) // below XKB, so XKB swaps carry them along
+	applyXkbOptions(m, xkbOptions())
+	return m
+}
+
+/*────────────────── helpers ───────────────────*/
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(strings.ToLower(s))
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
+func humanise(s string) string {
+	s = strings.ReplaceAll(s, "_", " ")
+	s = strings.ReplaceAll(s, "-", " ")
+	w := strings.Fields(s)
+	for i := range w {
+		w[i] = titleCase(w[i])
+	}
+	return strings.Join(w, " ")
+}
+
+/*──────────── accelerator parsing ─────────────*/
+
+// Modifier bits, in the canonical order they are printed.
+const (
+	modCtrl = 1 << iota
+	modShift
+	modAlt
+	modSuper
+	modHyper
+	modMeta
+	modCount = iota
+)
+
+var modTokens = [modCount]string{
+	"<Control>", "<Shift>", "<Alt>", "<Super>", "<Hyper>", "<Meta>",
+}
+
+// every spelling GTK accepts; <Primary> is Control on Linux
+var modNames = map[string]int{
+	"primary": modCtrl, "control": modCtrl, "ctrl": modCtrl, "ctl": modCtrl,
+	"shift": modShift, "shft": modShift,
+	"alt": modAlt, "mod1": modAlt,
+	"super": modSuper, "mod4": modSuper, // Mod4 is Super on every stock keymap (MATE writes it)
+	"hyper": modHyper,
+	"meta":  modMeta,
+}
+
+type accel struct {
+	mods int
+	key  string
+}
+
+// parseAccel understands "<Primary><Shift>q" as well as the
+// "<Super>+Left" notation used in coreShortcuts.
+func parseAccel(spec string) (accel, bool) {
+	var a accel
+	s := strings.TrimSpace(spec)
+	for {
+		s = strings.TrimLeft(s, "+ ")
+		if !strings.HasPrefix(s, "<") {
+			break
+		}
+		end := strings.IndexByte(s, '>')
+		if end < 0 {
+			return a, false
+		}
+		bit, ok := modNames[strings.ToLower(s[1:end])]
+		if !ok {
+			return a, false
+		}
+		a.mods |= bit
+		s = s[end+1:]
+	}
+	if strings.ContainsAny(s, "<> \t") { // keysyms never do
+		return a, false
+	}
+	if s == "disabled" && a.mods == 0 { // how string-typed keys say "unbound"
+		return a, false
+	}
+	a.key = s
+	if len([]rune(s)) == 1 { // GTK stores letters lower-case
+		a.key = strings.ToLower(s)
+	}
+	return a, a.key != "" || a.mods != 0
+}
+
+// spec renders the canonical GTK form; equal chords give equal specs.
+func (a accel) spec() string {
+	var b strings.Builder
+	for i, t := range modTokens {
+		if a.mods&(1<<i) != 0 {
+			b.WriteString(t)
+		}
+	}
+	b.WriteString(a.key)
+	return b.String()
+}
+
+/*──────────── accelerator formatting ───────────*/
+
+func fmtAccel(spec string, lbl map[string]string) (string, bool) {
+	if strings.Contains(spec, "XF86") && !includeMedia { // media keys – skip
+		dropped(spec, "media key (see -include-media-keys)")
+		return "", false
+	}
+	acc, ok := fmtKey(spec, lbl)
+	switch {
+	case ok:
+	case spec == "" || spec == "disabled":
+		logger.Debug("unbound", "spec", spec)
+	default:
+		dropped(spec, "not an accelerator")
+	}
+	return acc, ok
+}
+
+// fmtKey is fmtAccel without the media-key filter.
+func fmtKey(spec string, lbl map[string]string) (string, bool) {
+	a, ok := parseAccel(spec)
+	if !ok {
+		return "", false
+	}
+	if a.key == "ISO_Left_Tab" && lbl[a.key] == "" { // what Shift+Tab sends
+		a.mods, a.key = a.mods|modShift, "Tab"
+	}
+	var out []string
+	for _, i := range modsIn(spec) {
+		t := modTokens[i]
+		if a.mods&(1<<i) == 0 {
+			continue
+		}
+		l := lbl[t]
+		if l == "" {
+			l = humanise(strings.Trim(t, "<>"))
+		}
+		out = append(out, l)
+	}
+	switch {
+	case a.key == "":
+	case lbl[a.key] != "":
+		out = append(out, lbl[a.key])
+	case strings.HasPrefix(a.key, "XF86"):
+		out = append(out, mediaLabel(a.key))
+	case isNumpad(a.key):
+		k := a.key[3:]
+		out = append(out, "Numpad "+cmp.Or(kpWords[k], keyWords[k], humanise(k)))
+	default:
+		if c, ok := keyChar(a.key); ok {
+			out = append(out, c)
+		} else {
+			out = append(out, humanise(a.key))
+		}
+	}
+	var b strings.Builder
+	for i, p := range out {
+		b.WriteString(p)
+		switch {
+		case i == len(out)-1:
+		case glyphsOnMod && !sepSet && isModGlyph(p): // ⌃⇧Q, as on the keycaps
+		default:
+			b.WriteString(accelSep)
+		}
+	}
+	return b.String(), true
+}
+
+// modsIn is the order fmtKey prints spec's modifiers in: modTokens',
+// or with -mod-order schema the order spec spells them.
+func modsIn(spec string) []int {
+	order := []int{}
+	if modOrder == "schema" {
+		for _, tok := range strings.Split(spec, "<")[1:] {
+			name, _, _ := strings.Cut(tok, ">")
+			if bit, ok := modNames[strings.ToLower(name)]; ok {
+				if i := bits.TrailingZeros(uint(bit)); !slices.Contains(order, i) {
+					order = append(order, i)
+				}
+			}
+		}
+	}
+	for i := range modCount {
+		if !slices.Contains(order, i) {
+			order = append(order, i)
+		}
+	}
+	return order
+}
+
+/*────── immutable Mutter shortcuts (Activities etc.) ─────*/
+
+type staticBind struct{ spec, action, backing string }
+
+//go:embed core_shortcuts.tsv
+var coreShortcutsTSV string
+
+var (
+	coreShortcuts = parseStaticBinds("core_shortcuts.tsv", coreShortcutsTSV)
+	coreOverride  = true
+)
+
+func parseStaticBinds(file, data string) []staticBind {
+	var out []staticBind
+	for n, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 2 {
+			badLine(file, n, "want spec<TAB>action, got %q", l)
+			continue
+		}
+		b := staticBind{spec: f[0], action: f[1]}
+		if len(f) > 2 {
+			b.backing = f[2]
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+// loadCoreShortcuts prefers the user's copy of the table.
+func loadCoreShortcuts() []staticBind {
+	cfg, _ := os.UserConfigDir()
+	file := filepath.Join(cfg, "gnome-shortcuts", "core_shortcuts.tsv")
+	if data, err := os.ReadFile(file); err == nil {
+		return parseStaticBinds(file, string(data))
+	}
+	return coreShortcuts
+}
+
+// holdsChord reports whether a gsettings value still binds spec.  A
+// bare modifier keysym (overlay-key 'Super_L') counts as that modifier.
+func holdsChord(val, spec string) bool {
+	want, _ := parseAccel(spec)
+	for _, s := range gvTextStrings(val) {
+		a, ok := parseAccel(s)
+		if !ok {
+			continue
+		}
+		side := strings.TrimSuffix(strings.TrimSuffix(a.key, "_L"), "_R")
+		if bit, ok := modNames[strings.ToLower(side)]; ok && a.mods == 0 {
+			a = accel{mods: bit}
+		}
+		if a.spec() == want.spec() {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+───────────────────── schema ordering ──────────
+
+	Mutter resolves conflicts by the *order in the
+	corresponding gschema.xml file*.
+
+	We parse those XML files once to obtain an
+	action → order index map (no hand-written list).
+	Paths and defaults come from gschemas.compiled,
+	which is what GSettings itself reads; it is a
+	hash table, so it cannot tell us the order.
+
+	Directories are searched the way GLib does:
+	$GSETTINGS_SCHEMA_DIR, then $XDG_DATA_HOME and
+	$XDG_DATA_DIRS (each + /glib-2.0/schemas), which
+	covers NixOS profiles, Silverblue and user installs,
+	then the user and system Flatpak exports.
+	-schema-dir replaces the whole search.
+*/
+
+var schemaDirOpt string
+
+func schemaDirs() []string {
+	if schemaDirOpt != "" {
+		return filepath.SplitList(schemaDirOpt)
+	}
+	var dirs []string
+	seen := map[string]bool{}
+	add := func(d string) {
+		if d != "" && !seen[d] {
+			seen[d] = true
+			dirs = append(dirs, d)
+		}
+	}
+	for _, d := range filepath.SplitList(os.Getenv("GSETTINGS_SCHEMA_DIR")) {
+		add(d)
+	}
+	data := os.Getenv("XDG_DATA_HOME")
+	if data == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			data = filepath.Join(home, ".local", "share")
+		}
+	}
+	if data != "" {
+		add(filepath.Join(data, "glib-2.0", "schemas"))
+	}
+	sys := os.Getenv("XDG_DATA_DIRS")
+	if sys == "" {
+		sys = "/usr/local/share:/usr/share"
+	}
+	for _, d := range filepath.SplitList(sys) {
+		add(filepath.Join(d, "glib-2.0", "schemas"))
+	}
+	// Flatpak apps export their schemas here; the profile snippet that
+	// adds these to XDG_DATA_DIRS is not sourced by every session.
+	for _, d := range flatpakDirs() {
+		if d != "" {
+			add(filepath.Join(d, "exports", "share", "glib-2.0", "schemas"))
+		}
+	}
+	// Snaps keep theirs inside the squashfs, which nothing adds at all.
+	for _, d := range snapDirs() {
+		add(d)
+	}
+	return dirs
+}
+
+// flatpakDirs lists the user and system installations, honouring
+// the same overrides flatpak itself does.
+func flatpakDirs() []string {
+	user := os.Getenv("FLATPAK_USER_DIR")
+	if user == "" {
+		if data := os.Getenv("XDG_DATA_HOME"); data != "" {
+			user = filepath.Join(data, "flatpak")
+		} else if home, err := os.UserHomeDir(); err == nil {
+			user = filepath.Join(home, ".local", "share", "flatpak")
+		}
+	}
+	sys := os.Getenv("FLATPAK_SYSTEM_DIR")
+	if sys == "" {
+		sys = "/var/lib/flatpak"
+	}
+	return []string{user, sys}
+}
+
+// snapMounts are where distributions mount snaps (/snap on Ubuntu).
+var snapMounts = []string{"/snap", "/var/lib/snapd/snap"}
+
+// snapDirs lists the schema dirs of installed snaps, by snap name.
+func snapDirs() []string {
+	var out []string
+	for _, m := range snapMounts {
+		dirs, _ := filepath.Glob(filepath.Join(m, "*", "current", "usr", "share", "glib-2.0", "schemas"))
+		out = append(out, dirs...)
+	}
+	return out
+}
+
+// schemaInfo is what we learn about one schema from its XML and compiled files.
+type schemaInfo struct {
+	file     string            // *.gschema.xml that defines it
+	domain   string            // gettext domain of its summaries
+	path     string            // dconf path, "" for relocatable schemas
+	order    map[string]int    // key → position in the file
+	defaults map[string]string // key → default value (GVariant text)
+	meta     map[string]keyMeta
+}
+
+var schemaCache = map[string]*schemaInfo{}
+
+// lookupSchema accepts "id" or the relocatable "id:/path/" form.
+func lookupSchema(schemaID string) *schemaInfo {
+	if i := strings.IndexByte(schemaID, ':'); i >= 0 {
+		schemaID = schemaID[:i]
+	}
+	if si, ok := schemaCache[schemaID]; ok {
+		return si
+	}
+	si := loadSchema(schemaID)
+	if runCtx.Err() == nil { // cut short: try again next run
+		schemaCache[schemaID] = si
+	}
+	return si
+}
+
+func loadSchema(schemaID string) *schemaInfo {
+	si := &schemaInfo{order: map[string]int{}, defaults: map[string]string{}, meta: map[string]keyMeta{}}
+	cs, db := lookupCompiled(schemaID)
+	if cs != nil {
+		si.path, si.defaults = cs.path, cs.defaults
+	}
+	if xs := schemaXML(schemaID); xs != nil {
+		si.file, si.domain = xs.file, xs.Domain
+		if cs == nil {
+			si.path = xs.Path
+		}
+		for i, k := range xs.keys() {
+			m := k.meta()
+			si.order[k.Name], si.meta[k.Name] = i, m
+			if cs == nil {
+				si.defaults[k.Name] = k.defaultText(m.typ)
+			}
+		}
+	}
+	switch {
+	case cs != nil:
+		applyOverrides(schemaID, filepath.Dir(db), si.defaults)
+	case si.file != "":
+		applyOverrides(schemaID, filepath.Dir(si.file), si.defaults)
+	}
+	if si.file == "" && cs == nil {
+		diagnose(&SchemaNotFoundError{schemaID})
+	}
+	if si.file == "" && cs != nil {
+		// XML not shipped: keep the keys, alphabetically
+		si.file = db
+		for i, k := range sortedKeys(cs.defaults) {
+			si.order[k] = i
+		}
+	}
+	return si // no file ⇒ every key is “last”
+}
+
+/*──────── schema → app & rank (family) ────────*/
+
+func classify(schema, key string) (app string, rank int) {
+	switch {
+	case strings.Contains(schema, ".desktop.wm.keybindings"),
+		schema == cinnamonKeys+".wm",
+		strings.HasPrefix(schema, marcoPrefix),
+		strings.Contains(schema, ".mutter.wayland.keybindings"),
+		strings.Contains(schema, ".mutter.keybindings"):
+		return "Window Manager", 0
+	case strings.Contains(schema, ".shell.keybindings"):
+		return "GNOME Shell", 1
+	case strings.Contains(schema, ".settings-daemon.plugins.media-keys"):
+		return "Media Keys", 1
+	case schema == cinnamonKeys+".media-keys", schema == mateMediaKeys:
+		return "Media Keys", 1
+	case schema == cinnamonKeys:
+		return "Cinnamon", 1
+	case strings.Contains(schema, ".custom-keybinding"):
+		return "Custom", 3
+	case isInputMethod(schema, key):
+		return "Input Method", 2
+	}
+	trim, _, _ := strings.Cut(schema, ":") // relocatable: id:/path/
+	trim = strings.TrimSuffix(trim, ".keybindings")
+	trim = strings.TrimPrefix(trim, "org.")
+	if idx := strings.IndexByte(trim, '.'); idx >= 0 {
+		trim = trim[idx+1:]
+	}
+	seg := trim[strings.LastIndexByte(trim, '.')+1:]
+	return humanise(seg), 2
+}
+
+/*──────────── gather gsettings bindings ───────*/
+
+type row struct {
+	accel, app, action string
+	rank, order        int
+	spec, schema, key  string   // raw binding and where it came from
+	keySpecs           []string // every spec the key holds, XF86 ones included
+	lost               []row    // claimants shadowed by this row
+	locked             bool     // key locked by a system dconf database
+	source             string   // dconfSource of the key, "" when built in
+	src                string   // "file:line" for desktops configured by file
+}
+
+// pressed is the spec r's key is pressed with: keys inside shell
+// screens hold no chord, but still have one.
+func (r row) pressed() string {
+	if r.spec == "" && len(r.keySpecs) == 1 {
+		return r.keySpecs[0]
+	}
+	return r.spec
+}
+
+// nearDup is a pair of bindings written differently (<Primary>q vs
+// <Control>q, or modifiers in another order) that are one chord.
+type nearDup struct{ a, b row }
+
+// gsettingsDump lists every key, or only those of schema[:path].
+func gsettingsDump(schema ...string) []byte {
+	if defaultsOnly {
+		return defaultsDump(schema...)
+	}
+	if fromDump() {
+		return dumpOpt.dump(schema...)
+	}
+	if native() {
+		return nativeDump(schema...)
+	}
+	ctx, cancel := toolContext()
+	defer cancel()
+	args := append([]string{"list-recursively"}, schema...)
+	out, err := toolCmd(ctx, "gsettings", args...).Output()
+	if err != nil {
+		warnRemote(err)
+		diagnose(toolError(err, append([]string{"gsettings"}, args...)...))
+	}
+	return out
+}
+
+func gsettingsGet(schema, key string) string {
+	if defaultsOnly {
+		return lookupSchema(schema).defaults[key]
+	}
+	if fromDump() {
+		return dumpOpt.get(schema, key)
+	}
+	if native() {
+		return nativeGet(schema, key)
+	}
+	ctx, cancel := toolContext()
+	defer cancel()
+	out, err := toolCmd(ctx, "gsettings", "get", schema, key).Output()
+	if err != nil {
+		diagnose(toolError(err, "gsettings", "get", schema, key))
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func gsettingsSet(schema, key, val string) error {
+	if defaultsOnly {
+		return fmt.Errorf("gsettings set: not available with -defaults")
+	}
+	if fromDump() {
+		return fmt.Errorf("gsettings set: not available with -from-dump")
+	}
+	ctx, cancel := toolContext()
+	defer cancel()
+	out, err := toolCmd(ctx, "gsettings", "set", schema, key, val).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(out)); msg != "" {
+			return fmt.Errorf("gsettings set: %s", msg)
+		}
+		return fmt.Errorf("gsettings set: %w", err)
+	}
+	return nil
+}
+
+// entry is one "schema key value" line of `gsettings list-recursively`.
+type entry struct{ schema, key, val string }
+
+func parseDump(data []byte) []entry {
+	var out []entry
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		f := strings.Fields(sc.Text())
+		if len(f) < 3 {
+			continue
+		}
+		out = append(out, entry{f[0], f[1], strings.Join(f[2:], " ")})
+	}
+	return out
+}
+
+type custom struct {
+	binds     []string
+	name, cmd string
+}
+
+const customSchema = "org.gnome.settings-daemon.plugins.media-keys.custom-keybinding"
+
+// claimChord files r under its chord in chosen, the resolver deciding
+// which claimant fires; ok reports the chord spelt another way before.
+func claimChord(chosen map[string]row, res resolver, r row) (d nearDup, ok bool) {
+	a, _ := parseAccel(r.spec)
+	k := a.spec()
+	old, taken := chosen[k]
+	if !taken {
+		chosen[k] = r
+		return d, false
+	}
+	for _, c := range append([]row{old}, old.lost...) {
+		if c.spec != r.spec {
+			d, ok = nearDup{c, r}, true
+			break
+		}
+	}
+	if res.wins(r, old) {
+		prev := old.lost
+		old.lost = nil
+		r.lost = append(prev, old)
+		chosen[k] = r
+	} else {
+		old.lost = append(old.lost, r)
+		chosen[k] = old
+	}
+	return d, ok
+}
+
+func collect(lbl map[string]string) ([]row, []nearDup, error) {
+	return collectContext(runCtx, lbl)
+}
+
+// collectContext claims every collector's rows, one claim mode after
+// the other (see collector.go).  Once ctx is done it stops with
+// ctx's error rather than return a partial table.
+func collectContext(ctx context.Context, lbl map[string]string) ([]row, []nearDup, error) {
+	defer withRunCtx(ctx)()
+	if other := desktops[desktopOpt]; other != nil {
+		rows := other(lbl)
+		return rows, nil, ctx.Err()
+	}
+	p := newPass(ctx, lbl)
+	byMode := map[claimMode][]row{}
+	for _, c := range append(slices.Clip(collectors), plugins()...) {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		mode, rows := collected(c, p)
+		byMode[mode] = append(byMode[mode], rows...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// canonical spec → chosen row
+	chosen := map[string]row{}
+	res, err := activeResolver()
+	if err != nil {
+		return nil, nil, err
+	}
+	var near []nearDup
+	for _, r := range byMode[claimed] {
+		if r.spec == "" { // the lid: listed, holds no chord
+			chosen["\x00"+r.app+"\x00"+r.accel] = r
+		} else if d, ok := claimChord(chosen, res, r); ok {
+			near = append(near, d)
+		}
+	}
+
+	/* immutable core shortcuts override everything but XKB; the row
+	   they replace is Mutter's own binding, so it is not a claimant */
+	for _, r := range byMode[replacing] {
+		a, _ := parseAccel(r.spec)
+		k := a.spec()
+		if old, ok := chosen[k]; ok && old.rank < -1 {
+			dropped(r.spec, "XKB holds the chord", "action", r.action)
+			continue
+		}
+		r.lost = chosen[k].lost
+		chosen[k] = r
+	}
+
+	/* a11y gestures and keys inside shell screens shadow nothing */
+	for _, r := range byMode[listed] {
+		chosen["\x00"+r.app+"\x00"+r.accel+"\x00"+r.key] = r
+	}
+
+	/* terminals and apps: any system binding shadows them, but each
+	   app's chords are its own, so they never shadow one another, and
+	   two actions of one app on a chord are both listed */
+	for _, r := range byMode[appLocal] {
+		a, _ := parseAccel(r.spec)
+		if sys, ok := chosen[a.spec()]; ok {
+			sys.lost = append(sys.lost, r)
+			chosen[a.spec()] = sys
+			continue
+		}
+		chosen[a.spec()+"\x00"+r.app+"\x00"+r.action] = r
+	}
+
+	out := make([]row, 0, len(chosen))
+	for _, r := range chosen {
+		for _, l := range r.lost {
+			dropped(l.spec, "shadowed", "lost", origin(l), "by", origin(r))
+		}
+		r.locked = isLocked(dconfPath(r.schema, r.key))
+		r.source = dconfSource(dconfPath(r.schema, r.key))
+		for i, l := range r.lost {
+			r.lost[i].locked = isLocked(dconfPath(l.schema, l.key))
+			r.lost[i].source = dconfSource(dconfPath(l.schema, l.key))
+		}
+		out = append(out, r)
+	}
+	return out, near, nil
+}
+
+/*────────────────── table helpers ──────────────*/
+
+// dispWidth counts terminal columns; emoji (media key labels) take two.
+func dispWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		switch {
+		case r >= 0x1F300 && r <= 0x1FAFF, r >= 0x23E9 && r <= 0x23EC, r == 0x23F0, r == 0x23F3:
+			n += 2
+		default:
+			n++
+		}
+	}
+	return n
+}
+
+// padTo right-pads s to n columns, since %-Ns counts runes.
+func padTo(s string, n int) string {
+	if w := dispWidth(s); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
+
+// termSize falls back to 100×24 when stdout is not a terminal.
+func termSize() (width, height int) {
+	w, h, err := readline.GetSize(int(os.Stdout.Fd()))
+	if err != nil || w <= 0 || h <= 0 {
+		return 100, 24
+	}
+	return w, h
+}
+
+/*─────────────────── ordering ──────────────────*/
+
+func sortRows(rows []row) {
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].rank != rows[j].rank {
+			return rows[i].rank < rows[j].rank
+		}
+		if rows[i].order != rows[j].order {
+			return rows[i].order < rows[j].order
+		}
+		if rows[i].app != rows[j].app {
+			return rows[i].app < rows[j].app
+		}
+		if rows[i].action != rows[j].action {
+			return rows[i].action < rows[j].action
+		}
+		if rows[i].accel != rows[j].accel { // one key, several chords
+			return rows[i].accel < rows[j].accel
+		}
+		return rows[i].spec < rows[j].spec
+	})
+}
+
+/*─────────────────── commands ──────────────────*/
+
+var listOpt struct {
+	numpad, source bool
+	format         string
+	groupBy        string // "" or "app"
+	sort           string // "" (priority, or key with groupBy), or a sortBy key
+	watch          bool
+	grep           string
+	positions      []string // -positions: alternative layouts
+}
+
+type command struct {
+	help  string
+	flags func(fs *flag.FlagSet)
+	run   func(args []string) error
+	args  func(prev []string, cur string) []string // candidates for positional arguments (completion)
+}
+
+// exitCode lets a command end with a specific status without being
+// reported as a failure of the tool itself.
+type exitCode struct {
+	code int
+	msg  string
+}
+
+func (e exitCode) Error() string { return e.msg }
+
+const exitConflict = 3
+
+var failOnConflict bool
+
+func conflictFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&failOnConflict, "fail-on-conflict", false,
+		fmt.Sprintf("exit %d when any chord has more than one claimant", exitConflict))
+}
+
+// checkConflicts turns claimants into an exit status for CI pipelines.
+func checkConflicts(rows []row) error {
+	if !failOnConflict {
+		return nil
+	}
+	n := 0
+	for _, r := range rows {
+		if len(r.lost) > 0 {
+			n++
+		}
+	}
+	if n == 0 {
+		return nil
+	}
+	return exitCode{exitConflict, fmt.Sprintf("%d conflicting shortcut(s)", n)}
+}
+
+// commands is filled by init() in the file implementing each command.
+var commands = map[string]command{}
+
+func init() {
+	commands["list"] = command{
+		help: "print the resolved shortcut table (default)",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&listOpt.numpad, "numpad", true, "show the numeric keypad layer")
+			fs.BoolVar(&listOpt.source, "source", false, "tag each row with where its value comes from: user, system (db) or default")
+			fs.Func("positions", "tag each row with where its key sits on these layouts, e.g. dvorak,colemak (as a QWERTY key)", func(s string) error {
+				listOpt.positions = strings.Split(s, ",")
+				for _, l := range listOpt.positions {
+					if _, ok := loadKeymap(altLayout(l)); !ok {
+						return fmt.Errorf("no xkb symbols for %q", l)
+					}
+				}
+				return nil
+			})
+			fs.StringVar(&listOpt.format, "format", cmp.Or(userPrefs.format, "text"), "output format: "+strings.Join(sortedKeys(renderers), ", "))
+			fs.Func("group-by", "app: one table per application, sorted by key, instead of one in priority order", func(s string) error {
+				if s != "app" && s != "none" {
+					return fmt.Errorf("want app or none")
+				}
+				listOpt.groupBy = strings.TrimPrefix(s, "none")
+				return nil
+			})
+			fs.Func("sort", "row order in each table: priority (default), accel, app or action", func(s string) error {
+				if _, ok := sortBy[s]; !ok {
+					return fmt.Errorf("want one of %s", strings.Join(sortedKeys(sortBy), ", "))
+				}
+				listOpt.sort = s
+				return nil
+			})
+			conflictFlag(fs)
+			pagerFlag(fs)
+			fs.BoolVar(&listOpt.watch, "watch", false, "redraw the table whenever a setting changes, until Ctrl-C")
+			fs.StringVar(&listOpt.grep, "grep", "", "only rows whose shortcut, application, action or spec contains this text (any case)")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runList,
+	}
+	commands["help"] = command{
+		help: "show this overview",
+		run:  func([]string) error { usage(); return nil },
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: gnome-shortcuts [command] [flags]\n\ncommands:")
+	names := make([]string, 0, len(commands))
+	for n := range commands {
+		if !strings.HasPrefix(n, "__") { // hidden helpers
+			names = append(names, n)
+		}
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", n, commands[n].help)
+	}
+}
+
+/*───────────────────── main ────────────────────*/
+
+var (
+	timeoutOpt time.Duration // bounds a whole run, every external call included
+	verboseOpt bool          // print what was worked around
+)
+
+// addFlags registers c's flags and those every command takes.
+func (c command) addFlags(fs *flag.FlagSet) {
+	if c.flags != nil {
+		c.flags(fs)
+	}
+	fs.DurationVar(&timeoutOpt, "timeout", 0, "give up after this long, e.g. 10s (0: no limit)")
+	fs.BoolVar(&verboseOpt, "verbose", false, "report missing schemas, failed gsettings calls and unreadable files")
+	colorFlag(fs)
+	themeFlag(fs)
+	logFlags(fs)
+}
+
+// Main runs the command line on args (os.Args[1:]) and returns the
+// exit status.
+func Main(args []string) int {
+	name := "list"
+	if len(args) == 0 && readline.IsTerminal(int(os.Stdin.Fd())) && readline.IsTerminal(int(os.Stdout.Fd())) {
+		name = "browse" // someone at a terminal
+	}
+	if len(args) > 0 && (args[0] == "--record" || args[0] == "-record") {
+		args[0] = "__record" // hidden: see record.go
+	}
+	if len(args) > 0 && (args[0] == "--notify-conflicts" || args[0] == "-notify-conflicts") {
+		args = append([]string{"conflicts"}, args...) // for the systemd timer: see notify.go
+	}
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+	c, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", name)
+		usage()
+		return 2
+	}
+	if err := loadPrefs(); err != nil {
+		fmt.Fprintf(os.Stderr, "gnome-shortcuts: %v; ignoring config.toml\n", err)
+	}
+	if err := loadLabelFile(); err != nil {
+		fmt.Fprintf(os.Stderr, "gnome-shortcuts: %v; ignoring labels.toml\n", err)
+	}
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	c.addFlags(fs)
+	fs.Parse(args)
+	if verboseOpt {
+		diagnoseFn = func(err error) { fmt.Fprintln(os.Stderr, "gnome-shortcuts: note:", err) }
+	}
+	startLogging()
+	ctx, cancel := context.WithCancel(context.Background())
+	if timeoutOpt > 0 {
+		ctx, cancel = context.WithTimeout(context.Background(), timeoutOpt)
+	}
+	defer cancel()
+	defer withRunCtx(ctx)()
+	if err := c.run(fs.Args()); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() != nil {
+			err = fmt.Errorf("timed out after %s", timeoutOpt)
+		}
+		fmt.Fprintln(os.Stderr, "gnome-shortcuts:", err)
+		var ec exitCode
+		if errors.As(err, &ec) {
+			return ec.code
+		}
+		return 1
+	}
+	return 0
+}
+
+func runList([]string) error {
+	rd, err := rendererFor(listOpt.format)
+	if err != nil {
+		return err
+	}
+	if _, ok := rd.(textRenderer); ok {
+		t := textRenderer{color: colorOn(), mark: listOpt.grep}
+		if readline.IsTerminal(int(os.Stdout.Fd())) {
+			t.width, _ = termSize()
+		}
+		rd = t
+	}
+	if listOpt.watch {
+		return watchList(rd)
+	}
+	l, err := buildListing()
+	if err != nil {
+		return err
+	}
+	if listOpt.grep != "" && len(l.sections) == 0 {
+		return fmt.Errorf("no binding matches %q", listOpt.grep)
+	}
+	if err := paged(func(w io.Writer) error { return rd.Render(runCtx, w, l.sections) }); err != nil {
+		return err
+	}
+	warnNumLock(l.pad)
+	warnNearDups(l.near)
+	warnLayout(l.rows)
+	return checkConflicts(l.rows)
+}
+
+// listing is one collection laid out as list prints it.
+type listing struct {
+	rows, pad []row // everything collected; the numpad layer shown
+	near      []nearDup
+	sections  []Section
+}
+
+func buildListing() (listing, error) {
+	var l listing
+	rows, near, err := collect(labels(listOpt.format))
+	if err != nil {
+		return l, err
+	}
+	sortRows(rows)
+	l.rows, l.near = rows, near
+
+	var shown []row
+	for _, r := range rows {
+		if !excluded(r) {
+			shown = append(shown, r)
+		}
+	}
+	main, pad := splitNumpad(shown)
+	l.pad = pad
+	var sys, apps []row
+	for _, r := range main {
+		if r.rank >= appRank {
+			apps = append(apps, r)
+		} else {
+			sys = append(sys, r)
+		}
+	}
+	ss := []Section{{Bindings: listBindings(sys)}}
+	if listOpt.groupBy == "app" {
+		ss = byApp(main)
+	} else if len(apps) > 0 {
+		sort.SliceStable(apps, func(i, j int) bool { return apps[i].app < apps[j].app })
+		ss = append(ss, Section{"Applications (app-local: any system shortcut above wins)", listBindings(apps)})
+	}
+	if listOpt.numpad && len(pad) > 0 {
+		ss = append(ss, Section{"Numpad layer", listBindings(pad)})
+	}
+	order := listOpt.sort
+	if order == "" && listOpt.groupBy == "app" {
+		order = "accel"
+	}
+	for _, s := range ss {
+		if listOpt.grep != "" {
+			s.Bindings = slices.DeleteFunc(s.Bindings, func(b Binding) bool { return !matches(b, listOpt.grep) })
+		}
+		slices.SortStableFunc(s.Bindings, sortBy[cmp.Or(order, "priority")])
+		if listOpt.grep == "" || len(s.Bindings) > 0 {
+			l.sections = append(l.sections, s)
+		}
+	}
+	return l, nil
+}
+
+// byApp is a section per application, in priority order.
+func byApp(rows []row) []Section {
+	var ss []Section
+	for _, c := range categories(rows) {
+		ss = append(ss, Section{c.name, listBindings(c.rows)})
+	}
+	return ss
+}
+
+// sortBy orders a table's bindings; priority keeps the order they
+// come in.
+var sortBy = map[string]func(a, b Binding) int{
+	"priority": func(a, b Binding) int { return 0 },
+	"accel": func(a, b Binding) int { // by key, so every chord on Up sits together
+		key := func(x Binding) string {
+			if k, ok := parseAccel(x.Spec); ok {
+				return strings.ToLower(k.key)
+			}
+			return strings.ToLower(x.Accel)
+		}
+		return cmp.Or(cmp.Compare(key(a), key(b)), cmp.Compare(a.Accel, b.Accel))
+	},
+	"app":    func(a, b Binding) int { return cmp.Compare(strings.ToLower(a.App), strings.ToLower(b.App)) },
+	"action": func(a, b Binding) int { return cmp.Compare(strings.ToLower(a.Action), strings.ToLower(b.Action)) },
+}
+
+// matches is the -grep and browse filter: f anywhere in what the
+// row shows, or in its spec, in any case.
+func matches(b Binding, f string) bool {
+	hay := strings.ToLower(b.Accel + "\x00" + b.App + "\x00" + tagged(b) + "\x00" + b.Spec)
+	return strings.Contains(hay, strings.ToLower(f))
+}
+
+func printTable(rows []row) {
+	textRenderer{}.Render(runCtx, os.Stdout, []Section{{Bindings: listBindings(rows)}})
+}
+
+// listBindings are rows as list prints them, with -source,
+// -positions and config.toml's favorites filled in.
+func listBindings(rows []row) []Binding {
+	bs := toBindings(rows, listOpt.positions)
+	if !listOpt.source {
+		var drop func(bs []Binding)
+		drop = func(bs []Binding) {
+			for i := range bs {
+				bs[i].Source = ""
+				drop(bs[i].Shadowed)
+			}
+		}
+		drop(bs)
+	}
+	return bs
+}
+
+/*──────────── near-duplicate warnings ──────────*/
+
+func warnNearDups(near []nearDup) {
+	if len(near) == 0 {
+		return
+	}
+	fmt.Fprintln(os.Stderr, "\nwarning: bindings written differently but bound to the same chord:")
+	for _, d := range near {
+		fmt.Fprintf(os.Stderr, "  %-24s %-40s ≡ %-24s %s\n",
+			d.a.spec, origin(d.a), d.b.spec, origin(d.b))
+	}
+}
+
+func origin(r row) string {
+	if r.schema == "" {
+		return r.action
+	}
+	return r.schema + " " + r.key
+}
@@ -0,0 +1,117 @@
+package shortcuts
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite testdata/golden/*/want from the fixtures")
+
+// TestMain doubles as the command: the golden cases run the test
+// binary itself, so each gets a fresh process and its own environment.
+func TestMain(m *testing.M) {
+	if os.Getenv("GNOME_SHORTCUTS_GOLDEN") == "1" {
+		os.Exit(Main(os.Args[1:]))
+	}
+	os.Exit(m.Run())
+}
+
+// TestGolden replays every testdata/golden/<case>/args against the
+// case's dump.txt, schemas/ and xkb/, with nothing of this machine:
+// no tools on PATH, an empty home and config dir.
+func TestGolden(t *testing.T) {
+	cases, _ := filepath.Glob(filepath.Join("testdata", "golden", "*", "args"))
+	if len(cases) == 0 {
+		t.Fatal("no golden cases")
+	}
+	for _, a := range cases {
+		dir, _ := filepath.Abs(filepath.Dir(a))
+		t.Run(filepath.Base(dir), func(t *testing.T) {
+			b, err := os.ReadFile(a)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := runFixture(t, dir, strings.Fields(string(b)))
+			want := filepath.Join(dir, "want")
+			if *update {
+				if err := os.WriteFile(want, got, 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			exp, err := os.ReadFile(want)
+			if err != nil {
+				t.Fatalf("%v (go test -run Golden -update writes it)", err)
+			}
+			if !bytes.Equal(got, exp) {
+				t.Errorf("output differs from %s:\n%s", want, lineDiff(string(exp), string(got)))
+			}
+		})
+	}
+}
+
+// runFixture runs the command args against the fixtures in dir, with
+// nothing of this machine, and returns what it printed.
+func runFixture(t *testing.T, dir string, args []string) []byte {
+	t.Helper()
+	args = append([]string{args[0],
+		"-from-dump", filepath.Join(dir, "dump.txt"),
+		"-schema-dir", filepath.Join(dir, "schemas")}, args[1:]...)
+	home := t.TempDir()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = []string{"GNOME_SHORTCUTS_GOLDEN=1", "KEY_LAYOUT=pc", "PATH=" + home,
+		"HOME=" + home, "XDG_CONFIG_HOME=" + home, "XDG_DATA_HOME=" + home,
+		"XDG_DATA_DIRS=" + home, "XDG_STATE_HOME=" + home,
+		"XKB_CONFIG_ROOT=" + filepath.Join(dir, "xkb")}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	got, err := cmd.Output()
+	if err != nil {
+		t.Fatalf("%s: %v\n%s", strings.Join(args, " "), err, stderr.String())
+	}
+	return got
+}
+
+// TestFixtureCommands runs the commands without a golden case of their
+// own against one fixture, so each must take -from-dump and -schema-dir.
+func TestFixtureCommands(t *testing.T) {
+	dir, _ := filepath.Abs(filepath.Join("testdata", "golden", "gnome46-wayland"))
+	for _, c := range []struct{ args, want string }{
+		{"audit -session wayland -shell-version 46.0", "Close                        close the focused window"},
+		{"audit -session wayland -shell-version 46.0", "Toggle Overview              Ctrl + Shift + Q goes to Window Manager: Panel Run Dialog"},
+		{"layout-check -xkb us -session wayland -shell-version 46.0", "Every binding is reachable on us."},
+		{"input-method -session wayland -shell-version 46.0", "Ctrl + Shift + U         Input Method: Unicode Entry"},
+		{"explain -session wayland -shell-version 46.0 <Control><Shift>q", "shadowed (lower family): GNOME Shell: Toggle Overview"},
+		{"disable -dry-run -session wayland -shell-version 46.0 <Shift><Primary>q", `gsettings set org.gnome.desktop.wm.keybindings panel-run-dialog "['<Alt>F2']"`},
+		{"steals -session wayland -shell-version 46.0", "No application accelerators are shadowed"},
+		{"present -once -interval 1ms -session wayland -shell-version 46.0", "Win (Caps) + Space Switch Layout"},
+	} {
+		t.Run(strings.Fields(c.args)[0], func(t *testing.T) {
+			if got := runFixture(t, dir, strings.Fields(c.args)); !bytes.Contains(got, []byte(c.want)) {
+				t.Errorf("%s: output lacks %q:\n%s", c.args, c.want, got)
+			}
+		})
+	}
+}
+
+// lineDiff compares want and got line by line.
+func lineDiff(want, got string) string {
+	w, g := strings.Split(want, "\n"), strings.Split(got, "\n")
+	var b strings.Builder
+	for i := 0; i < len(w) || i < len(g); i++ {
+		switch {
+		case i >= len(g):
+			b.WriteString("- " + w[i] + "\n")
+		case i >= len(w):
+			b.WriteString("+ " + g[i] + "\n")
+		case w[i] != g[i]:
+			b.WriteString("- " + w[i] + "\n+ " + g[i] + "\n")
+		}
+	}
+	return b.String()
+}
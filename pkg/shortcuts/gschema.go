@@ -0,0 +1,270 @@
+package shortcuts
+
+import (
+	"cmp"
+	"encoding/xml"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+/*──────────────── gschema XML ───────────────────
+
+The *.gschema.xml sources give what the compiled
+database drops: key order (our precedence),
+summaries and their gettext domain (i18n.go), enum
+and flags types, and <choices>.
+A schema that extends another gets the parent's
+keys first, with its own <override>s applied.
+*/
+
+type xmlSchemaList struct {
+	Domain  string      `xml:"gettext-domain,attr"`
+	Enums   []xmlEnum   `xml:"enum"`
+	Flags   []xmlEnum   `xml:"flags"`
+	Schemas []xmlSchema `xml:"schema"`
+}
+
+type xmlEnum struct {
+	ID     string `xml:"id,attr"`
+	Values []struct {
+		Nick string `xml:"nick,attr"`
+	} `xml:"value"`
+}
+
+type xmlSchema struct {
+	ID        string   `xml:"id,attr"`
+	Path      string   `xml:"path,attr"`
+	Extends   string   `xml:"extends,attr"`
+	Domain    string   `xml:"gettext-domain,attr"` // the list's when unset
+	Keys      []xmlKey `xml:"key"`
+	Overrides []struct {
+		Name    string `xml:"name,attr"`
+		Default string `xml:",chardata"`
+	} `xml:"override"`
+	file string
+}
+
+type xmlKey struct {
+	Name        string `xml:"name,attr"`
+	Type        string `xml:"type,attr"`
+	Enum        string `xml:"enum,attr"`
+	Flags       string `xml:"flags,attr"`
+	Default     string `xml:"default"`
+	Summary     string `xml:"summary"`
+	Description string `xml:"description"`
+	Choices     []struct {
+		Value string `xml:"value,attr"`
+	} `xml:"choices>choice"`
+}
+
+// keyMeta is what the XML says about one key.
+type keyMeta struct {
+	typ                  string   // GVariant type; enums are "s", flags "as"
+	summary, description string   // as written, untranslated
+	choices              []string // enum nicks or <choices>, nil when open
+}
+
+// gschemaDir is every schema and enum defined under one schema dir.
+type gschemaDir struct {
+	schemas map[string]*xmlSchema
+	enums   map[string][]string // enum or flags id → nicks
+}
+
+var gschemaCache = map[string]*gschemaDir{}
+
+// indexSchemaDirs indexes every dir not seen yet in one pass: the
+// walks list the files, a pool of workers parses them, and the
+// results are merged in walk order so the first definition wins.
+// A cancelled run indexes nothing, so no partial index is cached.
+func indexSchemaDirs(dirs []string) {
+	type file struct{ dir, path string }
+	var files []file
+	var fresh []string
+	for _, dir := range dirs {
+		if _, ok := gschemaCache[dir]; ok {
+			continue
+		}
+		fresh = append(fresh, dir)
+		gschemaCache[dir] = &gschemaDir{schemas: map[string]*xmlSchema{}, enums: map[string][]string{}}
+		filepath.WalkDir(dir, func(p string, d os.DirEntry, _ error) error {
+			if runCtx.Err() != nil {
+				return filepath.SkipAll
+			}
+			if strings.HasSuffix(p, ".gschema.xml") || strings.HasSuffix(p, ".enums.xml") {
+				files = append(files, file{dir, p})
+			}
+			return nil
+		})
+	}
+	parsed := make([]*xmlSchemaList, len(files))
+	errs := make([]error, len(files))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(runtime.NumCPU(), len(files)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if runCtx.Err() == nil {
+					parsed[i], errs[i] = parseGschema(files[i].path)
+				}
+			}
+		}()
+	}
+	for i := range files {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	if runCtx.Err() != nil {
+		for _, dir := range fresh {
+			delete(gschemaCache, dir)
+		}
+		return
+	}
+	for i, f := range files {
+		diagnose(errs[i])
+		if parsed[i] != nil {
+			logger.Debug("schema file parsed", "file", f.path, "schemas", len(parsed[i].Schemas))
+			gschemaCache[f.dir].add(f.path, parsed[i])
+		}
+	}
+	for _, dir := range fresh {
+		logger.Info("schema dir indexed", "dir", dir, "schemas", len(gschemaCache[dir].schemas))
+	}
+}
+
+func parseGschema(path string) (*xmlSchemaList, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, &ParseError{File: path, Err: err}
+	}
+	var sl xmlSchemaList
+	if err := xml.Unmarshal(b, &sl); err != nil {
+		pe := &ParseError{File: path, Err: err}
+		var se *xml.SyntaxError
+		if errors.As(err, &se) {
+			pe.Line, pe.Err = se.Line, errors.New(se.Msg)
+		}
+		return nil, pe
+	}
+	return &sl, nil
+}
+
+func (g *gschemaDir) add(path string, sl *xmlSchemaList) {
+	for i := range sl.Schemas {
+		if s := &sl.Schemas[i]; g.schemas[s.ID] == nil {
+			s.file, s.Domain = path, cmp.Or(s.Domain, sl.Domain)
+			g.schemas[s.ID] = s
+		}
+	}
+	for _, e := range append(sl.Enums, sl.Flags...) {
+		if _, ok := g.enums[e.ID]; !ok {
+			g.enums[e.ID] = []string{}
+			for _, v := range e.Values {
+				g.enums[e.ID] = append(g.enums[e.ID], v.Nick)
+			}
+		}
+	}
+}
+
+// schemaXML finds id in the first schema dir that defines it.
+func schemaXML(id string) *xmlSchema {
+	dirs := schemaDirs()
+	indexSchemaDirs(dirs)
+	for _, dir := range dirs {
+		if g := gschemaCache[dir]; g != nil && g.schemas[id] != nil {
+			return g.schemas[id]
+		}
+	}
+	return nil
+}
+
+// xmlSchemaIDs lists every schema the dirs' XML files define.
+func xmlSchemaIDs() []string {
+	dirs := schemaDirs()
+	indexSchemaDirs(dirs)
+	var out []string
+	for _, dir := range dirs {
+		if g := gschemaCache[dir]; g != nil {
+			out = append(out, sortedKeys(g.schemas)...)
+		}
+	}
+	return out
+}
+
+// enumNicks resolves an enum or flags id; enums may live in any dir.
+func enumNicks(id string) []string {
+	dirs := schemaDirs()
+	indexSchemaDirs(dirs)
+	for _, dir := range dirs {
+		if g := gschemaCache[dir]; g != nil && g.enums[id] != nil {
+			return g.enums[id]
+		}
+	}
+	return nil
+}
+
+// keys lists s's keys after those it extends, each schema's
+// <override>s applied on the way down.
+func (s *xmlSchema) keys() []xmlKey {
+	chain := []*xmlSchema{s}
+	for p := s; p.Extends != "" && len(chain) < 8; chain = append(chain, p) {
+		if p = schemaXML(p.Extends); p == nil {
+			break
+		}
+	}
+	var out []xmlKey
+	for i := len(chain) - 1; i >= 0; i-- {
+		out = append(out, chain[i].Keys...)
+		for _, o := range chain[i].Overrides {
+			for j := range out {
+				if out[j].Name == o.Name {
+					out[j].Default = o.Default
+				}
+			}
+		}
+	}
+	return out
+}
+
+func (k xmlKey) meta() keyMeta {
+	m := keyMeta{typ: k.Type, summary: strings.TrimSpace(k.Summary),
+		description: strings.Join(strings.Fields(k.Description), " ")}
+	switch {
+	case k.Enum != "":
+		m.typ = "s"
+		m.choices = enumNicks(k.Enum)
+	case k.Flags != "":
+		m.typ = "as"
+		m.choices = enumNicks(k.Flags)
+	}
+	for _, c := range k.Choices {
+		m.choices = append(m.choices, c.Value)
+	}
+	return m
+}
+
+// defaultText is k's <default> in the form gsettings prints; only
+// string and string-list values are re-spelt, the rest kept as written.
+func (k xmlKey) defaultText(typ string) string {
+	s := strings.TrimSpace(k.Default)
+	if typ != "s" && typ != "as" {
+		return s
+	}
+	v, err := parseGVText("@" + typ + " " + s)
+	if err != nil {
+		return s
+	}
+	if str, ok := v.(string); ok && typ == "s" {
+		return gvText(typ, str)
+	}
+	if items, ok := v.([]any); ok && typ == "as" && len(gvStrings(items)) == len(items) {
+		return gvText(typ, items)
+	}
+	return s
+}
@@ -0,0 +1,135 @@
+package shortcuts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testSchemas = `<?xml version="1.0" encoding="UTF-8"?>
+<schemalist gettext-domain="test-domain">
+  <schema id="org.test.base">
+    <key name="close" type="as">
+      <default>['&lt;Super&gt;q']</default>
+      <summary>  Close window  </summary>
+      <description>
+        Closes the
+        focused window.
+      </description>
+    </key>
+    <key name="mode" enum="org.test.Mode">
+      <default>'fast'</default>
+    </key>
+  </schema>
+  <schema id="org.test.child" path="/org/test/child/" extends="org.test.base" gettext-domain="child-domain">
+    <override name="close">["&lt;Alt&gt;F4"]</override>
+    <key name="size" type="s">
+      <default>"big"</default>
+      <choices><choice value="big"/><choice value="small"/></choices>
+    </key>
+  </schema>
+</schemalist>
+`
+
+const testEnums = `<schemalist>
+  <enum id="org.test.Mode">
+    <value nick="fast" value="0"/>
+    <value nick="slow" value="1"/>
+  </enum>
+</schemalist>
+`
+
+func withSchemaDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, body := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	saved := schemaDirOpt
+	schemaDirOpt = dir
+	t.Cleanup(func() { schemaDirOpt = saved })
+	return dir
+}
+
+func TestGschema(t *testing.T) {
+	withSchemaDir(t, map[string]string{
+		"org.test.gschema.xml": testSchemas,
+		"org.test.enums.xml":   testEnums,
+	})
+	child := schemaXML("org.test.child")
+	if child == nil {
+		t.Fatal("org.test.child not found")
+	}
+	if child.Path != "/org/test/child/" || child.Domain != "child-domain" {
+		t.Errorf("child = path %q domain %q", child.Path, child.Domain)
+	}
+	if base := schemaXML("org.test.base"); base == nil || base.Domain != "test-domain" {
+		t.Errorf("base schema does not inherit the list's gettext domain: %+v", base)
+	}
+
+	keys := child.keys()
+	var names []string
+	for _, k := range keys {
+		names = append(names, k.Name)
+	}
+	if want := []string{"close", "mode", "size"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("keys = %q, want %q", names, want)
+	}
+	if got, want := keys[0].defaultText("as"), "['<Alt>F4']"; got != want {
+		t.Errorf("overridden default = %s, want %s", got, want)
+	}
+
+	for _, c := range []struct {
+		key  xmlKey
+		want keyMeta
+	}{
+		{keys[0], keyMeta{typ: "as", summary: "Close window", description: "Closes the focused window."}},
+		{keys[1], keyMeta{typ: "s", choices: []string{"fast", "slow"}}},
+		{keys[2], keyMeta{typ: "s", choices: []string{"big", "small"}}},
+	} {
+		if got := c.key.meta(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: meta = %+v, want %+v", c.key.Name, got, c.want)
+		}
+	}
+	if schemaXML("org.test.missing") != nil {
+		t.Error("found a schema that is not defined")
+	}
+}
+
+func TestDefaultText(t *testing.T) {
+	for _, c := range []struct {
+		typ, def, want string
+	}{
+		{"s", `"big"`, "'big'"},
+		{"s", `'it\'s'`, `"it's"`},
+		{"as", `[]`, "@as []"},
+		{"as", ` ["<Super>a", '<Super>b'] `, "['<Super>a', '<Super>b']"},
+		{"u", ` 5 `, "5"},
+		{"s", `'broken`, "'broken"},
+	} {
+		if got := (xmlKey{Default: c.def}).defaultText(c.typ); got != c.want {
+			t.Errorf("defaultText(%s, %s) = %s, want %s", c.typ, c.def, got, c.want)
+		}
+	}
+}
+
+func TestParseGschemaMalformed(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "bad.gschema.xml")
+	body := "<schemalist>\n  <schema id=\"x\">\n    <key name=\"k\" type=\"s\">\n  </schema>\n</schemalist>\n"
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	_, err := parseGschema(path)
+	var pe *ParseError
+	if !errors.As(err, &pe) || pe.File != path || pe.Line != 4 {
+		t.Errorf("err = %#v, want a *ParseError at %s:4", err, path)
+	}
+	if _, err := parseGschema(filepath.Join(dir, "missing.gschema.xml")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want ErrNotExist", err)
+	}
+}
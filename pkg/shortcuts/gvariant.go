@@ -0,0 +1,363 @@
+package shortcuts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*
+───────────────────── GVariant (serialised) ──────────
+
+	Just enough of the GVariant wire format to read
+	what glib-compile-schemas and dconf store: basic
+	types, arrays, maybes, tuples, dict entries and
+	variants.  Values decode to plain Go values:
+
+	  b → bool        s o g → string
+	  y n q i u x t → int64 / uint64, d → float64
+	  a* → []any      (…) {…} → []any
+	  m* → nil or the value, v → gvariant
+*/
+
+// gvariant is a typed serialised value.
+type gvariant struct {
+	typ  string
+	data []byte
+}
+
+// nextType splits the first complete type off a signature.
+func nextType(sig string) (string, string) {
+	if sig == "" {
+		return "", ""
+	}
+	switch sig[0] {
+	case 'a', 'm':
+		t, rest := nextType(sig[1:])
+		return sig[:1+len(t)], rest
+	case '(', '{':
+		depth := 0
+		for i := 0; i < len(sig); i++ {
+			switch sig[i] {
+			case '(', '{':
+				depth++
+			case ')', '}':
+				if depth--; depth == 0 {
+					return sig[:i+1], sig[i+1:]
+				}
+			}
+		}
+		return sig, ""
+	}
+	return sig[:1], sig[1:]
+}
+
+// members lists the child types of a tuple or dict entry.
+func members(t string) []string {
+	var out []string
+	for rest := t[1 : len(t)-1]; rest != ""; {
+		var m string
+		m, rest = nextType(rest)
+		out = append(out, m)
+	}
+	return out
+}
+
+func gvAlign(t string) int {
+	switch t[0] {
+	case 'n', 'q':
+		return 2
+	case 'i', 'u', 'h':
+		return 4
+	case 'x', 't', 'd', 'v':
+		return 8
+	case 'a', 'm':
+		return gvAlign(t[1:])
+	case '(', '{':
+		a := 1
+		for _, m := range members(t) {
+			a = max(a, gvAlign(m))
+		}
+		return a
+	}
+	return 1
+}
+
+// gvFixed returns the fixed size of t, or 0 when it is variable.
+func gvFixed(t string) int {
+	switch t[0] {
+	case 'b', 'y':
+		return 1
+	case 'n', 'q':
+		return 2
+	case 'i', 'u', 'h':
+		return 4
+	case 'x', 't', 'd':
+		return 8
+	case '(', '{':
+		size := 0
+		for _, m := range members(t) {
+			f := gvFixed(m)
+			if f == 0 {
+				return 0
+			}
+			size = alignUp(size, gvAlign(m)) + f
+		}
+		if size == 0 {
+			return 1 // the unit tuple
+		}
+		return alignUp(size, gvAlign(t))
+	}
+	return 0
+}
+
+func alignUp(n, a int) int { return (n + a - 1) / a * a }
+
+func offsetSize(n int) int {
+	switch {
+	case n == 0:
+		return 0
+	case n <= 0xff:
+		return 1
+	case n <= 0xffff:
+		return 2
+	case n <= 0xffffffff:
+		return 4
+	}
+	return 8
+}
+
+func readOffset(b []byte, sz int) int {
+	var v uint64
+	for i := sz - 1; i >= 0; i-- {
+		v = v<<8 | uint64(b[i])
+	}
+	return int(v)
+}
+
+var errGVariant = fmt.Errorf("malformed gvariant")
+
+// unwrap opens a serialised "v": child data, NUL, type string.
+func unwrap(data []byte) (gvariant, error) {
+	for i := len(data) - 1; i >= 0; i-- {
+		if data[i] == 0 {
+			t := string(data[i+1:])
+			if first, rest := nextType(t); first == "" || rest != "" {
+				return gvariant{}, errGVariant
+			}
+			return gvariant{t, data[:i]}, nil
+		}
+	}
+	return gvariant{}, errGVariant
+}
+
+func (v gvariant) decode() (any, error) {
+	t, d := v.typ, v.data
+	if f := gvFixed(t); f != 0 && len(d) != f && t[0] != '(' && t[0] != '{' {
+		return nil, errGVariant
+	}
+	le := binary.LittleEndian
+	switch t[0] {
+	case 'b':
+		return d[0] != 0, nil
+	case 'y':
+		return uint64(d[0]), nil
+	case 'n':
+		return int64(int16(le.Uint16(d))), nil
+	case 'q':
+		return uint64(le.Uint16(d)), nil
+	case 'i', 'h':
+		return int64(int32(le.Uint32(d))), nil
+	case 'u':
+		return uint64(le.Uint32(d)), nil
+	case 'x':
+		return int64(le.Uint64(d)), nil
+	case 't':
+		return le.Uint64(d), nil
+	case 'd':
+		return math.Float64frombits(le.Uint64(d)), nil
+	case 's', 'o', 'g':
+		if len(d) == 0 || d[len(d)-1] != 0 {
+			return nil, errGVariant
+		}
+		return string(d[:len(d)-1]), nil
+	case 'v':
+		return unwrap(d)
+	case 'm':
+		if len(d) == 0 {
+			return nil, nil
+		}
+		if gvFixed(t[1:]) == 0 {
+			d = d[:len(d)-1]
+		}
+		return gvariant{t[1:], d}.decode()
+	case 'a':
+		return decodeArray(t[1:], d)
+	case '(', '{':
+		return decodeTuple(members(t), d)
+	}
+	return nil, errGVariant
+}
+
+func decodeArray(elem string, d []byte) (any, error) {
+	out := []any{}
+	if len(d) == 0 {
+		return out, nil
+	}
+	if f := gvFixed(elem); f != 0 {
+		if len(d)%f != 0 {
+			return nil, errGVariant
+		}
+		for i := 0; i < len(d); i += f {
+			v, err := gvariant{elem, d[i : i+f]}.decode()
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, v)
+		}
+		return out, nil
+	}
+	osz := offsetSize(len(d))
+	table := readOffset(d[len(d)-osz:], osz)
+	if table > len(d) || (len(d)-table)%osz != 0 {
+		return nil, errGVariant
+	}
+	start := 0
+	for i := table; i < len(d); i += osz {
+		end := readOffset(d[i:], osz)
+		if end < start || end > table {
+			return nil, errGVariant
+		}
+		v, err := gvariant{elem, d[start:end]}.decode()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		start = alignUp(end, gvAlign(elem))
+	}
+	return out, nil
+}
+
+func decodeTuple(ms []string, d []byte) (any, error) {
+	out := []any{}
+	osz := offsetSize(len(d))
+	frame := len(d) // framing offsets are read back from here
+	pos := 0
+	for i, m := range ms {
+		pos = alignUp(pos, gvAlign(m))
+		var end int
+		switch f := gvFixed(m); {
+		case f != 0:
+			end = pos + f
+		case i == len(ms)-1:
+			end = frame
+		default:
+			frame -= osz
+			if frame < 0 {
+				return nil, errGVariant
+			}
+			end = readOffset(d[frame:], osz)
+		}
+		if pos > end || end > len(d) {
+			return nil, errGVariant
+		}
+		v, err := gvariant{m, d[pos:end]}.decode()
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		pos = end
+	}
+	return out, nil
+}
+
+// gvText prints a decoded value of type t the way `gsettings get` does.
+func gvText(t string, v any) string {
+	switch t[0] {
+	case 'b':
+		return strconv.FormatBool(v.(bool))
+	case 's', 'o', 'g':
+		s, q := v.(string), "'"
+		if strings.Contains(s, q) && !strings.Contains(s, `"`) {
+			q = `"`
+		}
+		s = strings.NewReplacer(`\`, `\\`, q, `\`+q, "\n", `\n`).Replace(s)
+		return q + s + q
+	case 'd':
+		s := strconv.FormatFloat(v.(float64), 'g', -1, 64)
+		if !strings.ContainsAny(s, ".e") {
+			s += ".0"
+		}
+		return s
+	case 'v':
+		g := v.(gvariant)
+		inner, err := g.decode()
+		if err != nil {
+			return "<?>"
+		}
+		return "<" + gvText(g.typ, inner) + ">"
+	case 'm':
+		if v == nil {
+			return "nothing"
+		}
+		return gvText(t[1:], v)
+	case 'a':
+		items := v.([]any)
+		if len(items) == 0 {
+			return "@" + t + " []"
+		}
+		parts := make([]string, len(items))
+		for i, it := range items {
+			parts[i] = gvText(t[1:], it)
+		}
+		if t[1] == '{' {
+			return "{" + strings.Join(parts, ", ") + "}"
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+	case '(':
+		items := v.([]any)
+		parts := make([]string, len(items))
+		for i, m := range members(t) {
+			parts[i] = gvText(m, items[i])
+		}
+		if len(parts) == 1 {
+			return "(" + parts[0] + ",)"
+		}
+		return "(" + strings.Join(parts, ", ") + ")"
+	case '{':
+		items := v.([]any)
+		ms := members(t)
+		return gvText(ms[0], items[0]) + ": " + gvText(ms[1], items[1])
+	}
+	return fmt.Sprint(v)
+}
+
+// gvStrings flattens an "as" (or "s") value; other types yield nil.
+func gvStrings(v any) []string {
+	switch x := v.(type) {
+	case string:
+		return []string{x}
+	case []any:
+		var out []string
+		for _, it := range x {
+			if s, ok := it.(string); ok {
+				out = append(out, s)
+			}
+		}
+		return out
+	}
+	return nil
+}
+
+// sortedKeys is a helper for deterministic iteration.
+func sortedKeys[V any](m map[string]V) []string {
+	ks := make([]string, 0, len(m))
+	for k := range m {
+		ks = append(ks, k)
+	}
+	sort.Strings(ks)
+	return ks
+}
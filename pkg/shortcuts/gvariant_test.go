@@ -0,0 +1,104 @@
+package shortcuts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNextType(t *testing.T) {
+	for _, c := range []struct{ sig, first, rest string }{
+		{"s", "s", ""},
+		{"asb", "as", "b"},
+		{"a{sv}i", "a{sv}", "i"},
+		{"(s(ii))u", "(s(ii))", "u"},
+		{"maas", "maas", ""},
+		{"", "", ""},
+	} {
+		if first, rest := nextType(c.sig); first != c.first || rest != c.rest {
+			t.Errorf("nextType(%q) = %q, %q, want %q, %q", c.sig, first, rest, c.first, c.rest)
+		}
+	}
+}
+
+func TestGVariantDecode(t *testing.T) {
+	for _, c := range []struct {
+		typ  string
+		data string
+		want any
+	}{
+		{"b", "\x01", true},
+		{"y", "\x07", uint64(7)},
+		{"n", "\xfe\xff", int64(-2)},
+		{"u", "\x05\x00\x00\x00", uint64(5)},
+		{"i", "\xff\xff\xff\xff", int64(-1)},
+		{"d", "\x00\x00\x00\x00\x00\x00\x04\x40", 2.5},
+		{"s", "hi\x00", "hi"},
+		{"ms", "", nil},
+		{"ms", "hi\x00\x00", "hi"},
+		{"au", "\x01\x00\x00\x00\x02\x00\x00\x00", []any{uint64(1), uint64(2)}},
+		{"as", "", []any{}},
+		{"as", "ab\x00c\x00\x03\x05", []any{"ab", "c"}},
+		{"(ui)", "\x01\x00\x00\x00\xff\xff\xff\xff", []any{uint64(1), int64(-1)}},
+		{"(ss)", "x\x00yz\x00\x02", []any{"x", "yz"}},
+		{"{sb}", "k\x00\x01\x02", []any{"k", true}},
+		{"v", "\x05\x00\x00\x00\x00u", gvariant{"u", []byte("\x05\x00\x00\x00")}},
+	} {
+		got, err := gvariant{c.typ, []byte(c.data)}.decode()
+		if err != nil {
+			t.Errorf("decode %s %q: %v", c.typ, c.data, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("decode %s %q = %#v, want %#v", c.typ, c.data, got, c.want)
+		}
+	}
+}
+
+func TestGVariantMalformed(t *testing.T) {
+	for _, c := range []struct{ typ, data string }{
+		{"u", "\x05\x00\x00"},         // short fixed value
+		{"s", "hi"},                   // no NUL
+		{"au", "\x01\x00\x00"},        // not a whole number of elements
+		{"as", "ab\x00\x09"},          // offset table past the end
+		{"as", "ab\x00c\x00\x09\x05"}, // element past the table
+		{"(ss)", "x\x00yz\x00\x09"},   // framing offset out of range
+	} {
+		if v, err := (gvariant{c.typ, []byte(c.data)}).decode(); err == nil {
+			t.Errorf("decode %s %q = %#v, want an error", c.typ, c.data, v)
+		}
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	v, err := unwrap([]byte("hi\x00\x00s"))
+	if err != nil || v.typ != "s" || string(v.data) != "hi\x00" {
+		t.Errorf("unwrap = %+v, %v", v, err)
+	}
+	for _, in := range []string{"no nul", "x\x00ss", "x\x00"} {
+		if v, err := unwrap([]byte(in)); err == nil {
+			t.Errorf("unwrap(%q) = %+v, want an error", in, v)
+		}
+	}
+}
+
+func TestGVText(t *testing.T) {
+	for _, c := range []struct {
+		typ  string
+		v    any
+		want string
+	}{
+		{"s", "it's", `"it's"`},
+		{"s", `a'b"c`, `'a\'b"c'`},
+		{"as", []any{}, "@as []"},
+		{"as", []any{"<Super>q", "<Alt>F4"}, "['<Super>q', '<Alt>F4']"},
+		{"(u)", []any{uint64(5)}, "(5,)"},
+		{"a{sb}", []any{[]any{"k", true}}, "{'k': true}"},
+		{"d", 2.0, "2.0"},
+		{"ms", nil, "nothing"},
+		{"v", gvariant{"u", []byte("\x05\x00\x00\x00")}, "<5>"},
+	} {
+		if got := gvText(c.typ, c.v); got != c.want {
+			t.Errorf("gvText(%s, %#v) = %s, want %s", c.typ, c.v, got, c.want)
+		}
+	}
+}
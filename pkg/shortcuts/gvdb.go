@@ -0,0 +1,238 @@
+package shortcuts
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"sort"
+	"strings"
+)
+
+/*
+───────────────────── GVDB reader ──────────
+
+	gschemas.compiled (and dconf's user/system
+	databases) are GVDB files: a header pointing at
+	a hash table whose items are either serialised
+	variants ('v'), nested tables ('H') or lists of
+	child names ('L').
+
+	  header  "GVariant" version options root{start,end}
+	  table   bloom_words n_buckets bloom[] buckets[] items[]
+	  item    hash parent key_start key_size type _ value{start,end}
+
+	Items are read linearly; we never need the hash.
+*/
+
+type gvdbItem struct {
+	parent   uint32
+	key      string
+	kind     byte
+	start    uint32
+	end      uint32
+	resolved string
+}
+
+type gvdbTable struct {
+	data  []byte
+	items []gvdbItem
+	index map[string]int
+}
+
+const gvdbNoParent = 0xffffffff
+
+func openGVDB(path string) (*gvdbTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < 24 || string(data[:8]) != "GVariant" {
+		return nil, &ParseError{File: path, Err: errors.New("not a GVDB file")}
+	}
+	le := binary.LittleEndian
+	t, err := gvdbAt(data, le.Uint32(data[16:]), le.Uint32(data[20:]))
+	if err != nil {
+		return nil, &ParseError{File: path, Err: err}
+	}
+	return t, nil
+}
+
+func gvdbAt(data []byte, start, end uint32) (*gvdbTable, error) {
+	if start > end || int(end) > len(data) || end-start < 8 {
+		return nil, fmt.Errorf("gvdb: bad table pointer")
+	}
+	le := binary.LittleEndian
+	b := data[start:end]
+	bloom := le.Uint32(b) & (1<<27 - 1)
+	buckets := le.Uint32(b[4:])
+	off := 8 + 4*uint64(bloom) + 4*uint64(buckets)
+	if off > uint64(len(b)) {
+		return nil, fmt.Errorf("gvdb: truncated table")
+	}
+	t := &gvdbTable{data: data, index: map[string]int{}}
+	for p := b[off:]; len(p) >= 24; p = p[24:] {
+		ks, kn := le.Uint32(p[8:]), uint32(le.Uint16(p[12:]))
+		if uint64(ks)+uint64(kn) > uint64(len(data)) {
+			return nil, fmt.Errorf("gvdb: key out of range")
+		}
+		t.items = append(t.items, gvdbItem{
+			parent: le.Uint32(p[4:]),
+			key:    string(data[ks : ks+kn]),
+			kind:   p[14],
+			start:  le.Uint32(p[16:]),
+			end:    le.Uint32(p[20:]),
+		})
+	}
+	for i := range t.items {
+		t.index[t.name(i, 0)] = i
+	}
+	return t, nil
+}
+
+// name joins an item's key onto its parents' (dconf stores paths that way).
+func (t *gvdbTable) name(i, depth int) string {
+	it := &t.items[i]
+	if it.resolved != "" || depth > len(t.items) {
+		return it.resolved
+	}
+	it.resolved = it.key
+	if it.parent != gvdbNoParent && int(it.parent) < len(t.items) {
+		it.resolved = t.name(int(it.parent), depth+1) + it.key
+	}
+	return it.resolved
+}
+
+// names lists every full key in file order.
+func (t *gvdbTable) names() []string {
+	out := make([]string, len(t.items))
+	for i := range t.items {
+		out[i] = t.name(i, 0)
+	}
+	return out
+}
+
+func (t *gvdbTable) item(name string, kind byte) (gvdbItem, bool) {
+	i, ok := t.index[name]
+	if !ok || t.items[i].kind != kind {
+		return gvdbItem{}, false
+	}
+	it := t.items[i]
+	if it.start > it.end || int(it.end) > len(t.data) {
+		return gvdbItem{}, false
+	}
+	return it, true
+}
+
+// table opens a nested 'H' item.
+func (t *gvdbTable) table(name string) (*gvdbTable, bool) {
+	it, ok := t.item(name, 'H')
+	if !ok {
+		return nil, false
+	}
+	sub, err := gvdbAt(t.data, it.start, it.end)
+	return sub, err == nil
+}
+
+// value returns the variant stored under name, already unwrapped.
+func (t *gvdbTable) value(name string) (gvariant, bool) {
+	it, ok := t.item(name, 'v')
+	if !ok {
+		return gvariant{}, false
+	}
+	v, err := unwrap(t.data[it.start:it.end])
+	return v, err == nil
+}
+
+/*──────── compiled schemas ────────*/
+
+// compiledSchema is one schema as glib-compile-schemas stored it.
+type compiledSchema struct {
+	path     string            // ".path", "" when relocatable
+	extends  string            // ".extends"
+	defaults map[string]string // key → default, GVariant text
+}
+
+var compiledCache = map[string]*gvdbTable{}
+
+func compiledDB(dir string) *gvdbTable {
+	if t, ok := compiledCache[dir]; ok {
+		return t
+	}
+	t, err := openGVDB(dir + "/gschemas.compiled")
+	if !errors.Is(err, fs.ErrNotExist) {
+		diagnose(err)
+	}
+	compiledCache[dir] = t
+	return t
+}
+
+// lookupCompiled searches the schema dirs' compiled databases in order.
+func lookupCompiled(schemaID string) (*compiledSchema, string) {
+	for _, dir := range schemaDirs() {
+		db := compiledDB(dir)
+		if db == nil {
+			continue
+		}
+		st, ok := db.table(schemaID)
+		if !ok {
+			continue
+		}
+		cs := &compiledSchema{defaults: map[string]string{}}
+		for _, k := range st.names() {
+			v, ok := st.value(k)
+			if !ok {
+				continue
+			}
+			val, err := v.decode()
+			if err != nil {
+				continue
+			}
+			switch k {
+			case ".path":
+				cs.path, _ = val.(string)
+			case ".extends":
+				cs.extends, _ = val.(string)
+			default:
+				// keys hold (default, …); child schemas are a bare string
+				if k[0] == '.' || v.typ[0] != '(' {
+					continue
+				}
+				cs.defaults[k] = gvText(members(v.typ)[0], val.([]any)[0])
+			}
+		}
+		return cs, dir + "/gschemas.compiled"
+	}
+	return nil, ""
+}
+
+// schemaIDCache is compiledSchemaIDs by schema dir list; native()
+// asks on every read.
+var schemaIDCache = map[string][]string{}
+
+// compiledSchemaIDs lists every schema in the compiled databases once.
+func compiledSchemaIDs() []string {
+	dirs := schemaDirs()
+	key := strings.Join(dirs, "\x00")
+	if ids, ok := schemaIDCache[key]; ok {
+		return ids
+	}
+	out := []string{}
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		db := compiledDB(dir)
+		if db == nil {
+			continue
+		}
+		for _, id := range db.names() {
+			if _, ok := db.table(id); ok && !seen[id] {
+				seen[id] = true
+				out = append(out, id)
+			}
+		}
+	}
+	sort.Strings(out)
+	schemaIDCache[key] = out
+	return out
+}
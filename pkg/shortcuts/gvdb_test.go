@@ -0,0 +1,142 @@
+package shortcuts
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// gvdbEntry is one item of a GVDB table built by buildGVDB; sub is
+// the nested table of an 'H' item.
+type gvdbEntry struct {
+	parent uint32
+	key    string
+	kind   byte
+	value  []byte
+	sub    []gvdbEntry
+}
+
+// gvdbTableAt appends keys, values and then the table for items to
+// buf, returning where the table starts and ends.
+func gvdbTableAt(buf *[]byte, items []gvdbEntry) (uint32, uint32) {
+	le := binary.LittleEndian
+	type span struct{ ks, kn, vs, ve uint32 }
+	spans := make([]span, len(items))
+	for i, it := range items {
+		var s span
+		if it.kind == 'H' {
+			s.vs, s.ve = gvdbTableAt(buf, it.sub)
+		} else {
+			s.vs = uint32(len(*buf))
+			*buf = append(*buf, it.value...)
+			s.ve = uint32(len(*buf))
+		}
+		s.ks, s.kn = uint32(len(*buf)), uint32(len(it.key))
+		*buf = append(*buf, it.key...)
+		spans[i] = s
+	}
+	start := uint32(len(*buf))
+	*buf = append(*buf, make([]byte, 8)...) // no bloom words, no buckets
+	for i, it := range items {
+		p := make([]byte, 24)
+		le.PutUint32(p[4:], it.parent)
+		le.PutUint32(p[8:], spans[i].ks)
+		le.PutUint16(p[12:], uint16(spans[i].kn))
+		p[14] = it.kind
+		le.PutUint32(p[16:], spans[i].vs)
+		le.PutUint32(p[20:], spans[i].ve)
+		*buf = append(*buf, p...)
+	}
+	return start, uint32(len(*buf))
+}
+
+func buildGVDB(t *testing.T, items []gvdbEntry) string {
+	t.Helper()
+	buf := append([]byte("GVariant"), make([]byte, 16)...)
+	start, end := gvdbTableAt(&buf, items)
+	binary.LittleEndian.PutUint32(buf[16:], start)
+	binary.LittleEndian.PutUint32(buf[20:], end)
+	path := filepath.Join(t.TempDir(), "db")
+	if err := os.WriteFile(path, buf, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestGVDB(t *testing.T) {
+	path := buildGVDB(t, []gvdbEntry{
+		{parent: gvdbNoParent, key: "/org/gnome/", kind: 'L'},
+		{parent: 0, key: "terminal", kind: 'v', value: []byte("'x'\x00\x00s")},
+		{parent: gvdbNoParent, key: "org.gnome.Test", kind: 'H', sub: []gvdbEntry{
+			{parent: gvdbNoParent, key: ".path", kind: 'v', value: []byte("/org/gnome/test/\x00\x00s")},
+		}},
+	})
+	db, err := openGVDB(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := db.names(), []string{"/org/gnome/", "/org/gnome/terminal", "org.gnome.Test"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("names = %q, want %q", got, want)
+	}
+	v, ok := db.value("/org/gnome/terminal")
+	if !ok {
+		t.Fatal("value /org/gnome/terminal missing")
+	}
+	if got, err := v.decode(); err != nil || got != "'x'" {
+		t.Errorf("decode = %#v, %v", got, err)
+	}
+	if _, ok := db.value("/org/gnome/"); ok {
+		t.Error("a list item read as a value")
+	}
+	if _, ok := db.table("/org/gnome/terminal"); ok {
+		t.Error("a value item opened as a table")
+	}
+	sub, ok := db.table("org.gnome.Test")
+	if !ok {
+		t.Fatal("table org.gnome.Test missing")
+	}
+	v, ok = sub.value(".path")
+	if got, err := v.decode(); !ok || err != nil || got != "/org/gnome/test/" {
+		t.Errorf(".path = %#v, %v", got, err)
+	}
+}
+
+func TestGVDBMalformed(t *testing.T) {
+	dir := t.TempDir()
+	le := binary.LittleEndian
+	header := func(start, end uint32) []byte {
+		b := append([]byte("GVariant"), make([]byte, 16)...)
+		le.PutUint32(b[16:], start)
+		le.PutUint32(b[20:], end)
+		return b
+	}
+	badKey := header(24, 56)
+	badKey = append(badKey, make([]byte, 8)...)
+	item := make([]byte, 24)
+	le.PutUint32(item[8:], 1000) // key starts past the end
+	le.PutUint16(item[12:], 4)
+	badKey = append(badKey, item...)
+	for name, data := range map[string][]byte{
+		"short":     []byte("GVariant"),
+		"magic":     append([]byte("NotGVDB!"), make([]byte, 16)...),
+		"pointer":   header(24, 4096),
+		"truncated": append(header(24, 32), 0, 0, 0, 0, 9, 0, 0, 0),
+		"key":       badKey,
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, data, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		_, err := openGVDB(path)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.File != path {
+			t.Errorf("%s: err = %v, want a *ParseError for %s", name, err, path)
+		}
+	}
+	if _, err := openGVDB(filepath.Join(dir, "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: err = %v, want ErrNotExist", err)
+	}
+}
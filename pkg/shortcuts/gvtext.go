@@ -0,0 +1,350 @@
+package shortcuts
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+/*
+───────────────────── GVariant (text) ────────────────
+
+	The format `gsettings get` prints and `gsettings
+	set` reads: '…' and "…" strings with escapes,
+	@type annotations (@as []), typed keywords
+	(uint32 5), true/false, nothing/just, [arrays],
+	(tuples), {dicts} and <variants>.  Values decode
+	to the same Go shapes as the serialised form,
+	except that a variant yields its inner value.
+*/
+
+type gvParser struct {
+	s   string
+	pos int
+}
+
+var gvKeywords = map[string]string{
+	"boolean": "b", "byte": "y", "int16": "n", "uint16": "q",
+	"int32": "i", "uint32": "u", "handle": "h", "int64": "x",
+	"uint64": "t", "double": "d", "string": "s",
+	"objectpath": "o", "signature": "g",
+}
+
+// parseGVText parses one value in GVariant text format.
+func parseGVText(s string) (any, error) {
+	p := &gvParser{s: s}
+	v, err := p.value("")
+	if err != nil {
+		return nil, err
+	}
+	if p.space(); p.pos != len(p.s) {
+		return nil, p.errorf("trailing text")
+	}
+	return v, nil
+}
+
+// gvTextStrings is the strings held by a text "s" or "as" value,
+// nil when it does not parse.
+func gvTextStrings(val string) []string {
+	v, err := parseGVText(val)
+	if err != nil {
+		return nil
+	}
+	return gvStrings(v)
+}
+
+// gvTextString is a text "s" value, "" when it is not one.
+func gvTextString(val string) string {
+	v, _ := parseGVText(val)
+	s, _ := v.(string)
+	return s
+}
+
+func (p *gvParser) errorf(format string, a ...any) error {
+	return fmt.Errorf("gvariant text at %d: %s", p.pos, fmt.Sprintf(format, a...))
+}
+
+func (p *gvParser) space() {
+	for p.pos < len(p.s) && strings.IndexByte(" \t\r\n", p.s[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+func (p *gvParser) peek() byte {
+	if p.space(); p.pos < len(p.s) {
+		return p.s[p.pos]
+	}
+	return 0
+}
+
+func (p *gvParser) expect(c byte) error {
+	if p.peek() != c {
+		return p.errorf("want %q", c)
+	}
+	p.pos++
+	return nil
+}
+
+// word reads an identifier-like token (keywords, numbers).
+func (p *gvParser) word() string {
+	p.space()
+	start := p.pos
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		if c == '_' || c == '.' || c == '+' || c == '-' ||
+			'0' <= c && c <= '9' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.s[start:p.pos]
+}
+
+// value parses one value; typ, when known, steers numbers and
+// empty containers.
+func (p *gvParser) value(typ string) (any, error) {
+	switch c := p.peek(); {
+	case c == 0:
+		return nil, p.errorf("unexpected end")
+	case c == '@':
+		p.pos++
+		p.space()
+		t, _ := nextType(p.s[p.pos:])
+		if t == "" {
+			return nil, p.errorf("bad type")
+		}
+		p.pos += len(t)
+		return p.value(t)
+	case c == '\'' || c == '"':
+		return p.str()
+	case c == 'b' && p.pos+1 < len(p.s) && (p.s[p.pos+1] == '\'' || p.s[p.pos+1] == '"'):
+		p.pos++ // bytestring
+		return p.str()
+	case c == '[':
+		return p.array(typ)
+	case c == '(':
+		return p.tuple(typ)
+	case c == '{':
+		return p.dict(typ)
+	case c == '<':
+		p.pos++
+		v, err := p.value("")
+		if err != nil {
+			return nil, err
+		}
+		return v, p.expect('>')
+	}
+	w := p.word()
+	switch w {
+	case "":
+		return nil, p.errorf("unexpected %q", p.s[p.pos])
+	case "true", "false":
+		return w == "true", nil
+	case "nothing":
+		return nil, nil
+	case "just":
+		if strings.HasPrefix(typ, "m") {
+			typ = typ[1:]
+		}
+		return p.value(typ)
+	}
+	if t, ok := gvKeywords[w]; ok {
+		return p.value(t)
+	}
+	return p.number(w, typ)
+}
+
+func (p *gvParser) number(w, typ string) (any, error) {
+	t := byte(0)
+	if typ != "" {
+		t = typ[0]
+	}
+	switch w {
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan":
+		return math.NaN(), nil
+	}
+	if t == 'd' || strings.ContainsAny(w, ".eE") && !strings.HasPrefix(w, "0x") {
+		f, err := strconv.ParseFloat(w, 64)
+		if err != nil {
+			return nil, p.errorf("bad number %q", w)
+		}
+		return f, nil
+	}
+	if strings.IndexByte("yqut", t) >= 0 && t != 0 {
+		u, err := strconv.ParseUint(w, 0, 64)
+		if err != nil {
+			return nil, p.errorf("bad number %q", w)
+		}
+		return u, nil
+	}
+	if i, err := strconv.ParseInt(w, 0, 64); err == nil {
+		return i, nil
+	}
+	if u, err := strconv.ParseUint(w, 0, 64); err == nil {
+		return u, nil
+	}
+	return nil, p.errorf("bad token %q", w)
+}
+
+func (p *gvParser) str() (string, error) {
+	q := p.s[p.pos]
+	p.pos++
+	var b strings.Builder
+	for p.pos < len(p.s) {
+		c := p.s[p.pos]
+		p.pos++
+		switch {
+		case c == q:
+			return b.String(), nil
+		case c != '\\':
+			b.WriteByte(c)
+			continue
+		}
+		if p.pos == len(p.s) {
+			break
+		}
+		e := p.s[p.pos]
+		p.pos++
+		switch e {
+		case 'n':
+			b.WriteByte('\n')
+		case 't':
+			b.WriteByte('\t')
+		case 'r':
+			b.WriteByte('\r')
+		case 'b':
+			b.WriteByte('\b')
+		case 'f':
+			b.WriteByte('\f')
+		case 'v':
+			b.WriteByte('\v')
+		case 'a':
+			b.WriteByte('\a')
+		case 'u', 'U':
+			n := 4
+			if e == 'U' {
+				n = 8
+			}
+			if p.pos+n > len(p.s) {
+				return "", p.errorf("short \\%c escape", e)
+			}
+			r, err := strconv.ParseUint(p.s[p.pos:p.pos+n], 16, 32)
+			if err != nil || !utf8.ValidRune(rune(r)) {
+				return "", p.errorf("bad \\%c escape", e)
+			}
+			b.WriteRune(rune(r))
+			p.pos += n
+		case '0', '1', '2', '3', '4', '5', '6', '7':
+			v := int(e - '0')
+			for i := 0; i < 2 && p.pos < len(p.s) && '0' <= p.s[p.pos] && p.s[p.pos] <= '7'; i++ {
+				v = v*8 + int(p.s[p.pos]-'0')
+				p.pos++
+			}
+			b.WriteByte(byte(v))
+		default: // \\ \' \" and anything else stand for themselves
+			b.WriteByte(e)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
+
+// items parses "v, v, …" up to the closing byte.
+func (p *gvParser) items(end byte, typeOf func(i int) string) ([]any, error) {
+	out := []any{}
+	p.pos++
+	if p.peek() == end {
+		p.pos++
+		return out, nil
+	}
+	for {
+		v, err := p.value(typeOf(len(out)))
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, v)
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case end:
+			p.pos++
+			return out, nil
+		default:
+			return nil, p.errorf("want ',' or %q", end)
+		}
+	}
+}
+
+func (p *gvParser) array(typ string) (any, error) {
+	elem := ""
+	if strings.HasPrefix(typ, "a") {
+		elem = typ[1:]
+	}
+	return p.items(']', func(int) string { return elem })
+}
+
+func (p *gvParser) tuple(typ string) (any, error) {
+	var ms []string
+	if strings.HasPrefix(typ, "(") {
+		ms = members(typ)
+	}
+	return p.items(')', func(i int) string {
+		if i < len(ms) {
+			return ms[i]
+		}
+		return ""
+	})
+}
+
+// dict parses {k: v, …} into [k, v] pairs, or a lone {k, v} entry.
+func (p *gvParser) dict(typ string) (any, error) {
+	var kt, vt string
+	if strings.HasPrefix(typ, "a{") || strings.HasPrefix(typ, "{") {
+		if ms := members(strings.TrimPrefix(typ, "a")); len(ms) == 2 {
+			kt, vt = ms[0], ms[1]
+		}
+	}
+	p.pos++
+	out := []any{}
+	if p.peek() == '}' {
+		p.pos++
+		return out, nil
+	}
+	for {
+		k, err := p.value(kt)
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() == ',' && len(out) == 0 { // a single dict entry
+			p.pos++
+			v, err := p.value(vt)
+			if err != nil {
+				return nil, err
+			}
+			return []any{k, v}, p.expect('}')
+		}
+		if err := p.expect(':'); err != nil {
+			return nil, err
+		}
+		v, err := p.value(vt)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, []any{k, v})
+		switch p.peek() {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return out, nil
+		default:
+			return nil, p.errorf("want ',' or '}'")
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package shortcuts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseGVText(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want any
+	}{
+		{`'<Super>q'`, "<Super>q"},
+		{`"it's"`, "it's"},
+		{`'it\'s'`, "it's"},
+		{`'a\nb\tc\\d'`, "a\nb\tc\\d"},
+		{`'é\U0001F50D'`, "é🔍"},
+		{`'\101\0'`, "A\x00"},
+		{`['<Super>h', '<Alt>F4']`, []any{"<Super>h", "<Alt>F4"}},
+		{`[]`, []any{}},
+		{`@as []`, []any{}},
+		{`@as ['x']`, []any{"x"}},
+		{`('xkb', 'us')`, []any{"xkb", "us"}},
+		{`[('xkb', 'us'), ('xkb', 'de+neo')]`, []any{[]any{"xkb", "us"}, []any{"xkb", "de+neo"}}},
+		{`(uint32 5, true)`, []any{uint64(5), true}},
+		{`@(ud) (5, 1)`, []any{uint64(5), 1.0}},
+		{`int32 -3`, int64(-3)},
+		{`0x10`, int64(16)},
+		{`2.5`, 2.5},
+		{`<'inner'>`, "inner"},
+		{`{'a': 1, 'b': 2}`, []any{[]any{"a", int64(1)}, []any{"b", int64(2)}}},
+		{`nothing`, nil},
+		{`just 'x'`, "x"},
+		{`b'bytes'`, "bytes"},
+		{`  'spaced'  `, "spaced"},
+	} {
+		got, err := parseGVText(c.in)
+		if err != nil {
+			t.Errorf("parseGVText(%s): %v", c.in, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("parseGVText(%s) = %#v, want %#v", c.in, got, c.want)
+		}
+	}
+}
+
+func TestParseGVTextMalformed(t *testing.T) {
+	for _, in := range []string{
+		``,
+		`'open`,
+		`'\u12'`,
+		`'\uZZZZ'`,
+		`['a' 'b']`,
+		`['a',`,
+		`('a'`,
+		`<'a'`,
+		`@ []`,
+		`'a' trailing`,
+		`uint32 -1`,
+		`bogus`,
+		`)`,
+	} {
+		if v, err := parseGVText(in); err == nil {
+			t.Errorf("parseGVText(%s) = %#v, want an error", in, v)
+		}
+	}
+}
+
+func TestGVTextStrings(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want []string
+	}{
+		{`['<Super>a', '<Super>b']`, []string{"<Super>a", "<Super>b"}},
+		{`'one'`, []string{"one"}},
+		{`@as []`, nil},
+		{`['unterminated`, nil},
+	} {
+		if got := gvTextStrings(c.in); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("gvTextStrings(%s) = %q, want %q", c.in, got, c.want)
+		}
+	}
+}
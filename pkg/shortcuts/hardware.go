@@ -0,0 +1,124 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*─────────────────── hardware ───────────────────
+
+What the power, sleep and hibernate keys and the
+lid switch do.  The keys are media-keys bindings
+on XF86 keysyms, listed here with or without
+-include-media-keys; what the power key actually
+does is power-button-action.  The lid claims no
+chord: its row reports gsd's lid actions where the
+schema still has them, else logind's
+HandleLidSwitch.
+*/
+
+const (
+	hwApp       = "Hardware"
+	powerSchema = "org.gnome.settings-daemon.plugins.power"
+)
+
+var hwMediaKeys = map[string]string{
+	"power":     "Power Button",
+	"suspend":   "Suspend",
+	"hibernate": "Hibernate",
+}
+
+var hwActions = map[string]string{
+	"interactive": "Ask", "nothing": "Nothing", "ignore": "Nothing",
+	"poweroff": "Power Off", "blank": "Blank Screen",
+}
+
+// hwAction names a power action given as a gsettings value
+// ('suspend') or a bare logind one (suspend).
+func hwAction(v string) string {
+	if s := gvTextString(v); s != "" {
+		v = s
+	}
+	if a, ok := hwActions[v]; ok {
+		return a
+	}
+	return humanise(v)
+}
+
+func isHwKey(schema, key string) bool {
+	_, ok := hwMediaKeys[strings.TrimSuffix(key, "-static")]
+	return ok && isMediaSchema(schema, key)
+}
+
+// hardwareRows are the power-related chords plus the lid pseudo-row;
+// vals holds "schema key" values.
+func hardwareRows(vals map[string]string, lbl map[string]string) []row {
+	var out []row
+	for _, key := range sortedKeys(vals) {
+		schema, k, _ := strings.Cut(key, " ")
+		if !isHwKey(schema, k) {
+			continue
+		}
+		act := hwMediaKeys[strings.TrimSuffix(k, "-static")]
+		if v, ok := vals[powerSchema+" power-button-action"]; ok && act == "Power Button" {
+			act += ": " + hwAction(v)
+		}
+		specs := gvTextStrings(vals[key])
+		ord, ok := lookupSchema(schema).order[k]
+		if !ok {
+			ord = 1 << 20
+		}
+		for _, spec := range specs {
+			if acc, ok := fmtKey(spec, lbl); ok {
+				out = append(out, row{accel: acc, app: hwApp, action: act, rank: 1,
+					order: ord, spec: spec, schema: schema, key: k, keySpecs: specs})
+			}
+		}
+	}
+	if lid := lidRow(vals); lid.action != "" {
+		out = append(out, lid)
+	}
+	return out
+}
+
+func lidRow(vals map[string]string) row {
+	r := row{accel: "Lid closed", app: hwApp, rank: 1, order: 1 << 20}
+	ac, okAC := vals[powerSchema+" lid-close-ac-action"]
+	bat, okBat := vals[powerSchema+" lid-close-battery-action"]
+	switch {
+	case okAC && okBat && ac != bat:
+		r.action = hwAction(bat) + " on battery, " + hwAction(ac) + " on AC"
+		r.schema, r.key = powerSchema, "lid-close-battery-action"
+	case okAC:
+		r.action = hwAction(ac)
+		r.schema, r.key = powerSchema, "lid-close-ac-action"
+	case remote(), fromDump():
+		return row{} // logind.conf is on the other machine
+	default:
+		r.action = hwAction(logindLid()) + " (logind)"
+	}
+	return r
+}
+
+// logindLid reads HandleLidSwitch; drop-ins override the main file.
+func logindLid() string {
+	v := "suspend"
+	files := []string{"/etc/systemd/logind.conf"}
+	for _, d := range []string{"/usr/lib/systemd/logind.conf.d", "/etc/systemd/logind.conf.d"} {
+		m, _ := filepath.Glob(filepath.Join(d, "*.conf"))
+		files = append(files, m...)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		for _, l := range strings.Split(string(data), "\n") {
+			if s, ok := strings.CutPrefix(strings.TrimSpace(l), "HandleLidSwitch="); ok {
+				v = strings.TrimSpace(s)
+			}
+		}
+	}
+	return v
+}
@@ -0,0 +1,323 @@
+package shortcuts
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"io"
+	"math/bits"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*──────────────────── heatmap ────────────────────
+
+A keyboard drawn in the terminal, each key shaded
+by how many bindings use it: bright keys are
+crowded, dark ones free.  A chord counts for its
+key and for every modifier it holds, so modifiers
+are always busy and stay out of the scale.  Keys
+sit where the active layout puts them (-xkb draws
+another), on the board of the keyboard model
+(models.go).  Without colour the shading is ASCII,
+. : + # from few bindings to the most.
+*/
+
+var heatmapOpt struct{ xkb string }
+
+func init() {
+	commands["heatmap"] = command{
+		help: "a keyboard shaded by how many bindings use each key",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&heatmapOpt.xkb, "xkb", "", `layout to draw, e.g. "de+nodeadkeys" (default: active input source)`)
+			shapeFlag(fs)
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runHeatmap,
+	}
+}
+
+// heatKey is one key cap: its XKB name, the US keysym for when the
+// layout has none, and its width in half keys.  No name is a gap.
+type heatKey struct {
+	code, sym string
+	w         int
+}
+
+// heatBoard is a tenkeyless PC keyboard.
+var heatBoard = board{{
+	{{"ESC", "Escape", 2}, {w: 2}, {"FK01", "F1", 2}, {"FK02", "F2", 2}, {"FK03", "F3", 2}, {"FK04", "F4", 2}, {w: 1},
+		{"FK05", "F5", 2}, {"FK06", "F6", 2}, {"FK07", "F7", 2}, {"FK08", "F8", 2}, {w: 1},
+		{"FK09", "F9", 2}, {"FK10", "F10", 2}, {"FK11", "F11", 2}, {"FK12", "F12", 2}},
+	{{"TLDE", "grave", 2}, {"AE01", "1", 2}, {"AE02", "2", 2}, {"AE03", "3", 2}, {"AE04", "4", 2}, {"AE05", "5", 2},
+		{"AE06", "6", 2}, {"AE07", "7", 2}, {"AE08", "8", 2}, {"AE09", "9", 2}, {"AE10", "0", 2},
+		{"AE11", "minus", 2}, {"AE12", "equal", 2}, {"BKSP", "BackSpace", 4}},
+	{{"TAB", "Tab", 3}, {"AD01", "q", 2}, {"AD02", "w", 2}, {"AD03", "e", 2}, {"AD04", "r", 2}, {"AD05", "t", 2},
+		{"AD06", "y", 2}, {"AD07", "u", 2}, {"AD08", "i", 2}, {"AD09", "o", 2}, {"AD10", "p", 2},
+		{"AD11", "bracketleft", 2}, {"AD12", "bracketright", 2}, {"BKSL", "backslash", 3}},
+	{{"CAPS", "Caps_Lock", 4}, {"AC01", "a", 2}, {"AC02", "s", 2}, {"AC03", "d", 2}, {"AC04", "f", 2}, {"AC05", "g", 2},
+		{"AC06", "h", 2}, {"AC07", "j", 2}, {"AC08", "k", 2}, {"AC09", "l", 2}, {"AC10", "semicolon", 2},
+		{"AC11", "apostrophe", 2}, {"RTRN", "Return", 4}},
+	{{"LFSH", "Shift_L", 5}, {"AB01", "z", 2}, {"AB02", "x", 2}, {"AB03", "c", 2}, {"AB04", "v", 2}, {"AB05", "b", 2},
+		{"AB06", "n", 2}, {"AB07", "m", 2}, {"AB08", "comma", 2}, {"AB09", "period", 2}, {"AB10", "slash", 2},
+		{"RTSH", "Shift_R", 5}},
+	{{"LCTL", "Control_L", 3}, {"LWIN", "Super_L", 3}, {"LALT", "Alt_L", 3}, {"SPCE", "space", 12},
+		{"RALT", "Alt_R", 3}, {"MENU", "Menu", 3}, {"RCTL", "Control_R", 3}},
+}, {
+	{{"PRSC", "Print", 2}, {"SCLK", "Scroll_Lock", 2}, {"PAUS", "Pause", 2}},
+	{{"INS", "Insert", 2}, {"HOME", "Home", 2}, {"PGUP", "Page_Up", 2}},
+	{{"DELE", "Delete", 2}, {"END", "End", 2}, {"PGDN", "Page_Down", 2}},
+	{},
+	{{w: 2}, {"UP", "Up", 2}},
+	{{"LEFT", "Left", 2}, {"DOWN", "Down", 2}, {"RGHT", "Right", 2}},
+}}
+
+// heatNames fit a key cap where the table's names would not.
+var heatNames = map[string]string{
+	"Escape": "Esc", "BackSpace": "Bksp", "Caps_Lock": "Caps", "Return": "Enter",
+	"ISO_Level3_Shift": "AltGr", "Print": "Prt", "Scroll_Lock": "ScL",
+	"Pause": "Brk", "Insert": "Ins", "Home": "Hm", "Page_Up": "PgU", "Delete": "Del",
+	"Page_Down": "PgD", "Left": "←", "Right": "→", "Up": "↑", "Down": "↓",
+	"grave": "`", "minus": "-", "equal": "=", "bracketleft": "[", "bracketright": "]",
+	"backslash": `\`, "semicolon": ";", "apostrophe": "'", "comma": ",", "period": ".",
+	"slash": "/", "plus": "+", "numbersign": "#", "asciicircum": "^",
+	"dead_circumflex": "^", "dead_acute": "´", "dead_grave": "`", "dead_tilde": "~",
+	"dead_diaeresis": "¨", "dead_abovering": "˚", "less": "<", "yen": "¥",
+	"Zenkaku_Hankaku": "Zen", "Muhenkan": "Muh", "Henkan_Mode": "Hen", "Hiragana_Katakana": "Kana",
+}
+
+// heatAliases are keysyms bindings use for a key the layout names
+// otherwise.
+var heatAliases = map[string]string{
+	"Prior": "Page_Up", "Next": "Page_Down", "ISO_Left_Tab": "Tab",
+}
+
+// heatShades run from a free key to the busiest: 256-colour
+// backgrounds, or ASCII fill.
+var (
+	heatColors = [...]string{"48;5;236;38;5;244", "48;5;240", "48;5;245;30", "48;5;250;30", "48;5;255;30"}
+	heatFill   = [...]string{" ", ".", ":", "+", "#"}
+)
+
+func runHeatmap([]string) error {
+	name, opts := heatmapOpt.xkb, []string(nil)
+	if name == "" {
+		name, opts = activeLayout(), xkbOptions()
+	}
+	km := layoutKeymap(name, opts)
+
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	boardShape = keyboardShape(name)
+	return drawHeatmap(os.Stdout, km, keyboardModel(), rows, lbl, colorOn())
+}
+
+// drawHeatmap draws m's board for km with rows counted on it.
+func drawHeatmap(w io.Writer, km keymap, m kbModel, rows []row, lbl map[string]string, color bool) error {
+	syms := map[string]string{} // XKB name → keysym
+	for _, block := range slices.Concat(heatBoard[:], m.keys()[:]) {
+		for _, r := range block {
+			for _, k := range r {
+				s := k.sym
+				if ks := km[k.code]; len(ks) > 0 && ks[0] != "" && ks[0] != "NoSymbol" {
+					s = cmp.Or(heatAliases[ks[0]], ks[0])
+				}
+				syms[k.code] = s
+			}
+		}
+	}
+	uses := map[string]int{} // keysym → bindings
+	mods := map[int]int{}    // modifier bit → bindings
+	for _, r := range rows {
+		a, ok := parseAccel(r.spec)
+		if !ok {
+			continue
+		}
+		k := cmp.Or(heatAliases[a.key], a.key)
+		if k == "Above_Tab" { // Mutter's name for the key above Tab
+			k = syms["TLDE"]
+		}
+		if k != "" {
+			uses[k]++
+		}
+		for b := 1; b < 1<<modCount; b <<= 1 {
+			if a.mods&b != 0 {
+				mods[b]++
+			}
+		}
+	}
+	count := func(sym string) int { return uses[sym] + mods[keysymMod[sym]] }
+
+	most := 0
+	drawn := map[string]bool{}
+	var keys []string // on the board, modifiers aside
+	for _, block := range m.keys() {
+		for _, r := range block {
+			for _, k := range r {
+				s := syms[k.code]
+				if k.code == "" || drawn[s] {
+					continue
+				}
+				drawn[s] = true
+				if keysymMod[s] == 0 {
+					keys = append(keys, s)
+					most = max(most, count(s))
+				}
+			}
+		}
+	}
+	shade := func(n int) int {
+		switch {
+		case n == 0:
+			return 0
+		case most == 0:
+			return len(heatFill) - 1
+		}
+		return min(len(heatFill)-1, (n*(len(heatFill)-1)+most-1)/most)
+	}
+	label := func(sym string, n int) string { // the first name that fits
+		names := []string{lbl[sym], heatNames[sym]}
+		if b := keysymMod[sym]; b != 0 {
+			t := modTokens[bits.TrailingZeros(uint(b))]
+			names = []string{lbl[t], humanise(strings.Trim(t, "<>"))}
+		} else if l, ok := fmtKey(sym, lbl); ok {
+			names = append(names, l)
+		}
+		for _, l := range names {
+			if l != "" && dispWidth(l) <= n {
+				return l
+			}
+		}
+		return fitTo(cmp.Or(names...), n)
+	}
+	name := func(sym string) string { // in the lists below the board
+		if l, ok := fmtKey(sym, lbl); ok {
+			return l
+		}
+		return sym
+	}
+
+	// Each key is two lines, its name over its count.
+	mainW := 0 // the main block's widest row, in columns
+	for _, r := range m.keys()[0] {
+		w := 0
+		for _, k := range r {
+			w += 2 * k.w
+		}
+		mainW = max(mainW, w)
+	}
+	shown := map[string]bool{}
+	for i := range m.keys()[0] {
+		if len(m.keys()[0][i]) == 0 && len(m.keys()[1][i]) == 0 {
+			continue
+		}
+		var top, bottom strings.Builder
+		for bi, block := range m.keys() {
+			if bi > 0 {
+				top.WriteString("  ")
+				bottom.WriteString("  ")
+			}
+			width := 0
+			for _, k := range block[i] {
+				n := 2*k.w - 1
+				width += 2 * k.w
+				if k.code == "" {
+					top.WriteString(k.sym + padding(k.sym, n+1))
+					bottom.WriteString(strings.Repeat(" ", n+1))
+					continue
+				}
+				s := syms[k.code]
+				c := count(s)
+				l := label(s, n)
+				num := ""
+				if c > 0 {
+					num = strconv.Itoa(c)
+				}
+				if shown[k.code] { // the foot of a tall Enter
+					l, num = "", ""
+				}
+				shown[k.code] = true
+				if color {
+					bg := heatColors[shade(c)]
+					top.WriteString(sgr(bg, l+padding(l, n)) + " ")
+					bottom.WriteString(sgr(bg, padding(num, n)+num) + " ")
+				} else {
+					top.WriteString(l + padding(l, n) + " ")
+					bottom.WriteString(strings.Repeat(heatFill[shade(c)], max(n-len(num), 0)) + num + " ")
+				}
+			}
+			if bi == 0 && width < mainW {
+				top.WriteString(strings.Repeat(" ", mainW-width))
+				bottom.WriteString(strings.Repeat(" ", mainW-width))
+			}
+		}
+		fmt.Fprintln(w, strings.TrimRight(top.String(), " "))
+		fmt.Fprintln(w, strings.TrimRight(bottom.String(), " "))
+	}
+
+	fmt.Fprintln(w)
+	if color {
+		fmt.Fprintf(w, "Brighter keys have more bindings (the busiest has %d); modifiers count every chord that holds them.\n", most)
+	} else {
+		fmt.Fprintf(w, "Shading . : + # grows with a key's bindings (the busiest has %d); modifiers count every chord that holds them.\n", most)
+	}
+	busy := slices.Clone(keys)
+	slices.SortStableFunc(busy, func(a, b string) int { return count(b) - count(a) })
+	var items []string
+	for _, s := range busy[:min(5, len(busy))] {
+		if count(s) > 0 {
+			items = append(items, fmt.Sprintf("%s (%d)", name(s), count(s)))
+		}
+	}
+	heatLine(w, "Busiest", items)
+	items = nil
+	for _, s := range keys {
+		if count(s) == 0 {
+			items = append(items, name(s))
+		}
+	}
+	heatLine(w, "Free", items)
+	var off []string
+	for s := range uses {
+		if !drawn[s] {
+			off = append(off, s)
+		}
+	}
+	slices.SortFunc(off, func(a, b string) int { return cmp.Or(uses[b]-uses[a], strings.Compare(a, b)) })
+	var fn []string
+	items = nil
+	for _, s := range off {
+		if m.behindFn(s) {
+			fn = append(fn, fmt.Sprintf("%s (%d)", name(s), uses[s]))
+		} else {
+			items = append(items, fmt.Sprintf("%s (%d)", name(s), uses[s]))
+		}
+	}
+	heatLine(w, "Behind Fn", fn)
+	heatLine(w, "Elsewhere", items)
+	return nil
+}
+
+// heatLine prints items after a heading, wrapped to the board's width.
+func heatLine(w io.Writer, head string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	line := padTo(head, 10)
+	for i, it := range items {
+		if i > 0 && dispWidth(line)+3+dispWidth(it) > 80 {
+			fmt.Fprintln(w, line)
+			line = padTo("", 10)
+		} else if i > 0 {
+			line += " · "
+		}
+		line += it
+	}
+	fmt.Fprintln(w, line)
+}
@@ -0,0 +1,235 @@
+package shortcuts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*─────────────────── Hyprland ───────────────────
+
+-desktop hyprland reads hyprland.conf:
+
+	$mainMod = SUPER
+	bind = $mainMod, Q, killactive,
+	bindd = $mainMod, E, File manager, exec, nautilus
+	bind = $mainMod SHIFT, code:10, movetoworkspace, 1
+	submap = resize
+	binde = , right, resizeactive, 10 0
+	submap = reset
+	unbind = $mainMod, M
+	source = ~/.config/hypr/keys.conf
+
+Every bind on a chord runs, so repeated chords
+are all listed rather than shadowing each other;
+unbind removes the earlier ones.
+*/
+
+func init() {
+	desktops["hyprland"] = hyprRows
+	exporters["hyprland"] = exporter{"Hyprland bind lines", writeHyprland}
+}
+
+var hyprMods = map[string]string{
+	"super": "<Super>", "win": "<Super>", "logo": "<Super>", "mod4": "<Super>",
+	"shift": "<Shift>",
+	"ctrl":  "<Control>", "control": "<Control>",
+	"alt": "<Alt>", "mod1": "<Alt>",
+}
+
+var hyprMouse = map[string]string{
+	"mouse:272": "Mouse_Left", "mouse:273": "Mouse_Right", "mouse:274": "Mouse_Middle",
+	"mouse_up": "Scroll_Up", "mouse_down": "Scroll_Down",
+}
+
+// hyprKeyNames maps lower-cased keysyms to the spelling labels use.
+var hyprKeyNames = func() map[string]string {
+	m := map[string]string{}
+	for k := range keyWords {
+		m[strings.ToLower(k)] = k
+	}
+	for k := range keyGlyphs {
+		m[strings.ToLower(k)] = k
+	}
+	return m
+}()
+
+func hyprSpec(mods, key string) (string, bool) {
+	var b strings.Builder
+	for _, m := range strings.FieldsFunc(mods, func(r rune) bool { return r == ' ' || r == '_' || r == '+' }) {
+		t, ok := hyprMods[strings.ToLower(m)]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(t)
+	}
+	key = strings.TrimSpace(key)
+	switch {
+	case key == "":
+		return "", false
+	case hyprMouse[strings.ToLower(key)] != "":
+		key = hyprMouse[strings.ToLower(key)]
+	case strings.HasPrefix(key, "code:"):
+		key = "Keycode_" + key[5:]
+	case hyprKeyNames[strings.ToLower(key)] != "":
+		key = hyprKeyNames[strings.ToLower(key)]
+	}
+	b.WriteString(key)
+	return b.String(), true
+}
+
+// hyprLine drops a comment; "##" is a literal '#'.
+func hyprLine(l string) string {
+	var b strings.Builder
+	for i := 0; i < len(l); i++ {
+		if l[i] == '#' {
+			if i+1 < len(l) && l[i+1] == '#' {
+				b.WriteByte('#')
+				i++
+				continue
+			}
+			break
+		}
+		b.WriteByte(l[i])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+type hyprParser struct {
+	configFiles
+	mode  string
+	binds []tilingBind
+}
+
+func (p *hyprParser) file(path string) error {
+	f, err := p.open(path)
+	if f == nil {
+		return err
+	}
+	defer f.Close()
+
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		kw, val, ok := strings.Cut(hyprLine(sc.Text()), "=")
+		if !ok {
+			continue
+		}
+		kw, val = strings.TrimSpace(kw), strings.TrimSpace(val)
+		if strings.HasPrefix(kw, "$") {
+			p.vars[kw] = p.expand(val)
+			continue
+		}
+		val = p.expand(val)
+		switch {
+		case kw == "source":
+			p.include(path, val, p.file)
+		case kw == "submap":
+			p.mode = val
+			if val == "reset" {
+				p.mode = ""
+			}
+		case kw == "unbind":
+			mods, key, _ := strings.Cut(val, ",")
+			if spec, ok := hyprSpec(mods, key); ok {
+				p.drop(spec)
+			}
+		case strings.HasPrefix(kw, "bind"):
+			flags := kw[4:]
+			fields := strings.Split(val, ",")
+			if len(fields) < 3 {
+				continue
+			}
+			spec, ok := hyprSpec(fields[0], fields[1])
+			if !ok {
+				continue
+			}
+			rest := fields[2:]
+			action := ""
+			if strings.Contains(flags, "d") && len(rest) > 1 { // bindd: description first
+				action, rest = strings.TrimSpace(rest[0]), rest[1:]
+			}
+			if action == "" {
+				action = strings.TrimSpace(strings.TrimSpace(rest[0]) + " " + strings.TrimSpace(strings.Join(rest[1:], ",")))
+			}
+			p.binds = append(p.binds, tilingBind{p.mode, spec, action, fmt.Sprintf("%s:%d", path, n), nil})
+		}
+	}
+	return sc.Err()
+}
+
+// drop removes earlier binds of spec in the current submap.
+func (p *hyprParser) drop(spec string) {
+	want, _ := parseAccel(spec)
+	keep := p.binds[:0]
+	for _, b := range p.binds {
+		if a, _ := parseAccel(b.spec); b.mode != p.mode || a.spec() != want.spec() {
+			keep = append(keep, b)
+		}
+	}
+	p.binds = keep
+}
+
+func hyprRows(lbl map[string]string) []row {
+	path := wmConfig
+	if path == "" {
+		cfg, _ := os.UserConfigDir()
+		path = filepath.Join(cfg, "hypr", "hyprland.conf")
+	}
+	p := &hyprParser{configFiles: newConfigFiles()}
+	if err := p.file(path); err != nil {
+		warn(err)
+	}
+	return bindRows("Hyprland", p.binds, lbl)
+}
+
+/*──────── export -format hyprland ────────*/
+
+var hyprExportMods = []struct {
+	bit  int
+	name string
+}{{modSuper, "$mainMod"}, {modCtrl, "CTRL"}, {modAlt, "ALT"}, {modShift, "SHIFT"}}
+
+// hyprCombo turns "<Super><Shift>q" into "$mainMod SHIFT", "Q".
+func hyprCombo(spec string) (mods, key string, ok bool) {
+	a, ok := parseAccel(spec)
+	if !ok || a.key == "" || a.mods&(modHyper|modMeta) != 0 {
+		return "", "", false
+	}
+	var parts []string
+	for _, m := range hyprExportMods {
+		if a.mods&m.bit != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	key = a.key
+	switch {
+	case strings.HasPrefix(key, "Keycode_"):
+		key = "code:" + key[8:]
+	case len(key) == 1:
+		key = strings.ToUpper(key)
+	}
+	return strings.Join(parts, " "), key, true
+}
+
+func writeHyprland(w io.Writer, rows []row, _ map[string]string) error {
+	actions := wmActions()
+	fmt.Fprint(w, "# Generated by `gnome-shortcuts export -format hyprland` from the resolved GNOME shortcuts.\n"+
+		"$mainMod = SUPER\n$terminal = kitty\n$menu = wofi --show drun\n")
+	var skipped []string
+	for _, r := range rows {
+		cmd, ok := wmCommand(r, 1, "exec, %s", actions)
+		mods, key, ok2 := hyprCombo(r.spec)
+		if !ok || !ok2 {
+			skipped = append(skipped, exportComment(r))
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\nbind = %s, %s, %s\n", exportComment(r), mods, key, cmd)
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "\n# No Hyprland equivalent:\n%s\n", strings.Join(skipped, "\n"))
+	}
+	return nil
+}
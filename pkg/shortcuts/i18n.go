@@ -0,0 +1,167 @@
+package shortcuts
+
+import (
+	"encoding/binary"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*─────────────────── translations ───────────────────
+
+In a language other than English, actions come out
+as GNOME itself names them there: a schema key's
+summary ("Switch to workspace 1") looked up in the
+gettext catalogue of the schema's domain (mutter,
+gnome-shell, gnome-settings-daemon…), the same .mo
+files Settings reads, under each $XDG_DATA_DIRS
+/locale.  LANGUAGE, LC_ALL, LC_MESSAGES and LANG
+choose the language the way gettext does, and
+-lang overrides them.  A key
+with no summary or no translation keeps its
+humanised English name.
+*/
+
+// messageLangs are the catalogues to try, best first: "pt_BR.UTF-8"
+// gives pt_BR, then pt.  English and the C locale give none.
+func messageLangs() []string {
+	var langs []string
+	for _, env := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(env); v != "" {
+			langs = []string{v}
+			break
+		}
+	}
+	if langOpt != "" {
+		return langCandidates([]string{langOpt})
+	}
+	if len(langs) == 1 && langs[0] != "C" && langs[0] != "POSIX" {
+		if l := os.Getenv("LANGUAGE"); l != "" { // only heeded outside C, as gettext does
+			langs = strings.Split(l, ":")
+		}
+	}
+	return langCandidates(langs)
+}
+
+// langCandidates spells each of langs the ways catalogues are named.
+func langCandidates(langs []string) []string {
+	var out []string
+	for _, l := range langs {
+		l, _, _ = strings.Cut(l, ".") // codeset
+		l, mod, _ := strings.Cut(l, "@")
+		if l == "" || l == "C" || l == "POSIX" || l == "en" || strings.HasPrefix(l, "en_") {
+			break // what is untranslated is English
+		}
+		base, _, _ := strings.Cut(l, "_")
+		for _, c := range []string{l + "@" + mod, l, base} {
+			if !strings.HasSuffix(c, "@") && !slices.Contains(out, c) {
+				out = append(out, c)
+			}
+		}
+	}
+	return out
+}
+
+// localeDirs are where catalogues live.
+func localeDirs() []string {
+	sys := os.Getenv("XDG_DATA_DIRS")
+	if sys == "" {
+		sys = "/usr/local/share:/usr/share"
+	}
+	var out []string
+	for _, d := range filepath.SplitList(sys) {
+		out = append(out, filepath.Join(d, "locale"))
+	}
+	return out
+}
+
+var catalogs = map[string]map[string]string{} // domain → msgid → msgstr
+
+// gettext is msgid in domain's catalogue for the user's language.
+func gettext(domain, msgid string) (string, bool) {
+	if domain == "" || msgid == "" {
+		return "", false
+	}
+	cat, ok := catalogs[domain]
+	if !ok {
+		cat = loadCatalog(domain)
+		catalogs[domain] = cat
+	}
+	s, ok := cat[msgid]
+	return s, ok && s != ""
+}
+
+// loadCatalog merges domain's .mo files, the best language winning.
+func loadCatalog(domain string) map[string]string {
+	cat := map[string]string{}
+	langs := messageLangs()
+	for i := len(langs) - 1; i >= 0; i-- {
+		for _, dir := range localeDirs() {
+			file := filepath.Join(dir, langs[i], "LC_MESSAGES", domain+".mo")
+			data, err := os.ReadFile(file)
+			if err != nil {
+				continue
+			}
+			m, err := parseMO(data)
+			if err != nil {
+				diagnose(&ParseError{File: file, Err: err})
+				continue
+			}
+			for k, v := range m {
+				cat[k] = v
+			}
+			break
+		}
+	}
+	return cat
+}
+
+// parseMO reads a GNU gettext catalogue; plural and context entries
+// are left out.
+func parseMO(data []byte) (map[string]string, error) {
+	if len(data) < 28 {
+		return nil, fmt.Errorf("not a .mo file")
+	}
+	var bo binary.ByteOrder = binary.LittleEndian
+	switch bo.Uint32(data) {
+	case 0x950412de:
+	case 0xde120495:
+		bo = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("not a .mo file")
+	}
+	n, orig, trans := bo.Uint32(data[8:]), bo.Uint32(data[12:]), bo.Uint32(data[16:])
+	str := func(table, i uint32) (string, bool) {
+		at := uint64(table) + 8*uint64(i)
+		if at+8 > uint64(len(data)) {
+			return "", false
+		}
+		l, off := uint64(bo.Uint32(data[at:])), uint64(bo.Uint32(data[at+4:]))
+		if off+l > uint64(len(data)) {
+			return "", false
+		}
+		return string(data[off : off+l]), true
+	}
+	out := map[string]string{}
+	for i := range n {
+		id, ok1 := str(orig, i)
+		s, ok2 := str(trans, i)
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("entry %d runs past the end", i)
+		}
+		if id == "" || strings.ContainsAny(id, "\x00\x04") {
+			continue // the header, plurals, contexts
+		}
+		out[id] = s
+	}
+	return out, nil
+}
+
+// localAction is key's summary in the user's language, if GNOME has
+// one.
+func localAction(schema, key string) (string, bool) {
+	si := lookupSchema(schema)
+	return gettext(si.domain, si.meta[key].summary)
+}
@@ -0,0 +1,70 @@
+package shortcuts
+
+import (
+	"encoding/binary"
+	"reflect"
+	"testing"
+)
+
+// buildMO lays out a .mo catalogue of pairs in byte order bo.
+func buildMO(bo binary.ByteOrder, pairs [][2]string) []byte {
+	n := uint32(len(pairs))
+	orig, trans := uint32(28), 28+8*n
+	data := make([]byte, 28+16*n)
+	bo.PutUint32(data, 0x950412de)
+	bo.PutUint32(data[8:], n)
+	bo.PutUint32(data[12:], orig)
+	bo.PutUint32(data[16:], trans)
+	for i, p := range pairs {
+		for j, s := range p {
+			at := []uint32{orig, trans}[j] + 8*uint32(i)
+			bo.PutUint32(data[at:], uint32(len(s)))
+			bo.PutUint32(data[at+4:], uint32(len(data)))
+			data = append(data, s...)
+			data = append(data, 0)
+		}
+	}
+	return data
+}
+
+func TestParseMO(t *testing.T) {
+	pairs := [][2]string{
+		{"", "Content-Type: text/plain; charset=UTF-8\n"},
+		{"Close window", "Fenster schließen"},
+		{"file\x00files", "Datei\x00Dateien"},
+		{"menu\x04Open", "Öffnen"},
+		{"Hide all normal windows", "Alle normalen Fenster verbergen"},
+	}
+	want := map[string]string{
+		"Close window":            "Fenster schließen",
+		"Hide all normal windows": "Alle normalen Fenster verbergen",
+	}
+	for _, bo := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		got, err := parseMO(buildMO(bo, pairs))
+		if err != nil {
+			t.Errorf("%v: %v", bo, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%v: parseMO = %q, want %q", bo, got, want)
+		}
+	}
+}
+
+func TestParseMOMalformed(t *testing.T) {
+	good := buildMO(binary.LittleEndian, [][2]string{{"a", "b"}})
+	pastEnd := append([]byte(nil), good...)
+	binary.LittleEndian.PutUint32(pastEnd[28+4:], 1<<20) // "a" starts past the end
+	shortTable := append([]byte(nil), good...)
+	binary.LittleEndian.PutUint32(shortTable[8:], 50) // 50 entries, 1 stored
+	for name, data := range map[string][]byte{
+		"short":       good[:20],
+		"magic":       append([]byte{1, 2, 3, 4}, good[4:]...),
+		"string":      pastEnd,
+		"entry count": shortTable,
+	} {
+		if m, err := parseMO(data); err == nil {
+			t.Errorf("%s: parseMO = %q, want an error", name, m)
+		}
+	}
+}
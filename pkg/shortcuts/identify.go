@@ -0,0 +1,382 @@
+package shortcuts
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"encoding/binary"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+/*──────────────────── identify ────────────────────
+
+Press a chord, read what GNOME would run.  Keys
+come straight from the kernel (/dev/input/event*,
+so the input group or root is needed), below GNOME,
+which is why even Super alone or a chord the shell
+grabs gets through.  The keyboards are grabbed
+while identify runs, so nothing fires; -no-grab
+lets GNOME act as well.  Esc or Ctrl+C ends it.
+
+Key codes become keysyms through the active layout's
+XKB files, plus the caps:, ctrl: and altwin: options
+set in GNOME.
+*/
+
+var identifyOpt struct{ noGrab bool }
+
+func init() {
+	commands["identify"] = command{
+		help: "press chords to see what each runs (reads /dev/input)",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&identifyOpt.noGrab, "no-grab", false, "let GNOME see the keys too, so the chords also fire")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runIdentify,
+	}
+}
+
+// Linux input event types and codes (linux/input-event-codes.h).
+const (
+	evKey      = 1
+	keyEsc     = 1
+	keyRelease = 0
+	keyPress   = 1
+	eviocgrab  = 0x40044590 // _IOW('E', 0x90, int)
+)
+
+// inputEventSize is struct input_event: a timeval, then type, code
+// and value.
+var inputEventSize = 2*strconv.IntSize/8 + 8
+
+type keyEvent struct {
+	code  uint16
+	value int32
+}
+
+// inputDevice is one block of /proc/bus/input/devices.
+type inputDevice struct {
+	name, bus, vendor, event string
+}
+
+// keyboards lists the event devices the kernel calls keyboards.
+func keyboards() []string {
+	var out []string
+	for _, d := range inputKeyboards() {
+		out = append(out, filepath.Join("/dev/input", d.event))
+	}
+	return out
+}
+
+// inputKeyboards are the input devices with a "kbd" handler and key
+// repeat (EV_REP), which mice and power buttons lack.
+func inputKeyboards() []inputDevice {
+	data, err := os.ReadFile("/proc/bus/input/devices")
+	if err != nil {
+		return nil
+	}
+	var out []inputDevice
+	for _, dev := range strings.Split(string(data), "\n\n") {
+		var d inputDevice
+		kbd, rep := false, false
+		for _, l := range strings.Split(dev, "\n") {
+			if id, ok := strings.CutPrefix(l, "I: "); ok {
+				for _, f := range strings.Fields(id) {
+					k, v, _ := strings.Cut(f, "=")
+					switch k {
+					case "Bus":
+						d.bus = v
+					case "Vendor":
+						d.vendor = v
+					}
+				}
+			}
+			if n, ok := strings.CutPrefix(l, "N: Name="); ok {
+				d.name = strings.Trim(n, `"`)
+			}
+			if h, ok := strings.CutPrefix(l, "H: Handlers="); ok {
+				for _, f := range strings.Fields(h) {
+					kbd = kbd || f == "kbd"
+					if strings.HasPrefix(f, "event") {
+						d.event = f
+					}
+				}
+			}
+			if ev, ok := strings.CutPrefix(l, "B: EV="); ok {
+				bits, _ := strconv.ParseUint(ev, 16, 64)
+				rep = bits&(1<<20) != 0
+			}
+		}
+		if kbd && rep && d.event != "" {
+			out = append(out, d)
+		}
+	}
+	return out
+}
+
+// readKeys sends devs' key events to out until each device ends.
+func readKeys(devs []string, grab bool, out chan<- keyEvent) ([]*os.File, error) {
+	var files []*os.File
+	for _, d := range devs {
+		f, err := os.Open(d)
+		if err != nil {
+			for _, f := range files {
+				f.Close()
+			}
+			if errors.Is(err, os.ErrPermission) {
+				return nil, fmt.Errorf("%w (join the input group, then log in again)", err)
+			}
+			return nil, err
+		}
+		if grab {
+			if _, _, e := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), eviocgrab, 1); e != 0 {
+				diagnose(fmt.Errorf("cannot grab %s: %w", d, e))
+			}
+		}
+		files = append(files, f)
+	}
+	done := make(chan struct{})
+	for _, f := range files {
+		go func(r io.Reader) {
+			defer func() { done <- struct{}{} }()
+			buf := make([]byte, inputEventSize)
+			br := bufio.NewReader(r)
+			for {
+				if _, err := io.ReadFull(br, buf); err != nil {
+					return
+				}
+				typ := binary.NativeEndian.Uint16(buf[inputEventSize-8:])
+				if typ == evKey {
+					out <- keyEvent{binary.NativeEndian.Uint16(buf[inputEventSize-6:]), int32(binary.NativeEndian.Uint32(buf[inputEventSize-4:]))}
+				}
+			}
+		}(f)
+	}
+	go func() {
+		for range files {
+			<-done
+		}
+		close(out)
+	}()
+	return files, nil
+}
+
+/*──────── key codes to keysyms ────────*/
+
+var (
+	xkbKeycodeRE = regexp.MustCompile(`<(\w+)>\s*=\s*(\d+)\s*;`)
+	xkbAliasRE   = regexp.MustCompile(`alias\s+<(\w+)>\s*=\s*<(\w+)>\s*;`)
+)
+
+// evdevKeysyms maps kernel key codes to level-one keysyms on layout:
+// keycodes/evdev names the key, symbols/pc and the layout say what it
+// types.
+func evdevKeysyms(layout string, opts []string) (map[uint16]string, error) {
+	data, err := os.ReadFile(filepath.Join(xkbRoot(), "keycodes", "evdev"))
+	if err != nil {
+		return nil, err
+	}
+	names := map[int][]string{} // X keycode → key names, aliases too
+	canon := map[string]int{}
+	for _, m := range xkbKeycodeRE.FindAllStringSubmatch(string(data), -1) {
+		n, _ := strconv.Atoi(m[2])
+		if _, dup := canon[m[1]]; !dup {
+			canon[m[1]] = n
+			names[n] = append(names[n], m[1])
+		}
+	}
+	for _, m := range xkbAliasRE.FindAllStringSubmatch(string(data), -1) {
+		if n, ok := canon[m[2]]; ok {
+			names[n] = append(names[n], m[1])
+		}
+	}
+	km := layoutKeymap(layout, opts)
+	out := map[uint16]string{}
+	for n, ns := range names {
+		for _, name := range ns {
+			if syms := km[name]; len(syms) > 0 && syms[0] != "" && syms[0] != "NoSymbol" {
+				out[uint16(n-8)] = syms[0] // X keycodes are evdev's plus 8
+				break
+			}
+		}
+	}
+	return out, nil
+}
+
+// layoutKeymap is every key on layout, the keys all layouts share
+// (symbols/pc) included, with the caps:, ctrl: and altwin: options
+// applied.
+func layoutKeymap(layout string, opts []string) keymap {
+	km := keymap{}
+	includeSymbols(km, "pc", 0)
+	lay, _ := loadKeymap(layout)
+	for k, v := range lay {
+		km[k] = v
+	}
+	for _, o := range opts { // "caps:super" is symbols/caps(super)
+		if grp, name, ok := strings.Cut(o, ":"); ok && (grp == "caps" || grp == "ctrl" || grp == "altwin") {
+			includeSymbols(km, grp+"("+name+")", 0)
+		}
+	}
+	return km
+}
+
+// keysymMod is the modifier bit a modifier keysym holds.
+var keysymMod = map[string]int{
+	"Control_L": modCtrl, "Control_R": modCtrl,
+	"Shift_L": modShift, "Shift_R": modShift,
+	"Alt_L": modAlt, "Alt_R": modAlt, "Meta_L": modAlt, "Meta_R": modAlt,
+	"Super_L": modSuper, "Super_R": modSuper,
+	"Hyper_L": modHyper, "Hyper_R": modHyper,
+}
+
+/*──────── chords ────────*/
+
+// chord is what a press (or a lone modifier's release) makes: code
+// is the kernel's for a key, so a key the layout lacks has a code but
+// no keysym.
+type chord struct {
+	accel
+	code uint16
+}
+
+// quit is Esc or Ctrl+C, which end identify and quiz -press.
+func (c chord) quit() bool {
+	return c.code == keyEsc && c.mods == 0 || c.key == "c" && c.mods == modCtrl
+}
+
+// keyboard reads chords from the event devices.  isKey says which
+// modifier keysyms XKB gives a job of their own (Compose on Right
+// Alt): those count as a key when released alone.
+type keyboard struct {
+	events chan keyEvent
+	files  []*os.File
+	syms   map[uint16]string
+	isKey  func(sym string) bool
+	held   map[uint16]int // modifier keys down → their bit
+	lone   bool           // only modifiers pressed since the last chord
+}
+
+// openKeyboard listens to devs, or to every keyboard when there are
+// none.
+func openKeyboard(devs []string, grab bool, isKey func(string) bool) (*keyboard, error) {
+	if len(devs) == 0 {
+		if devs = keyboards(); len(devs) == 0 {
+			return nil, fmt.Errorf("no keyboards in /proc/bus/input/devices")
+		}
+	}
+	syms, err := evdevKeysyms(activeLayout(), xkbOptions())
+	if err != nil {
+		return nil, err
+	}
+	k := &keyboard{events: make(chan keyEvent), syms: syms, isKey: isKey, held: map[uint16]int{}}
+	if k.files, err = readKeys(devs, grab, k.events); err != nil {
+		return nil, err
+	}
+	return k, nil
+}
+
+func (k *keyboard) close() {
+	for _, f := range k.files {
+		f.Close()
+	}
+}
+
+// next waits for the next chord; io.EOF once every device is gone.
+func (k *keyboard) next(ctx context.Context) (chord, error) {
+	for {
+		var ev keyEvent
+		select {
+		case <-ctx.Done():
+			return chord{}, ctx.Err()
+		case e, ok := <-k.events:
+			if !ok {
+				return chord{}, io.EOF
+			}
+			ev = e
+		}
+		mods := 0
+		for _, m := range k.held {
+			mods |= m
+		}
+		sym := k.syms[ev.code]
+		switch {
+		case ev.value == keyPress && keysymMod[sym] != 0:
+			k.held[ev.code] = keysymMod[sym]
+			k.lone = true
+		case ev.value == keyRelease && keysymMod[sym] != 0:
+			delete(k.held, ev.code)
+			lone := k.lone
+			k.lone = false
+			switch {
+			case !lone:
+			case k.isKey(sym) && mods == keysymMod[sym]:
+				return chord{accel: accel{key: sym}}, nil
+			default: // a modifier alone, like Super for the overview
+				return chord{accel: accel{mods: mods}}, nil
+			}
+		case ev.value == keyPress:
+			k.lone = false
+			return chord{accel{mods: mods, key: sym}, ev.code}, nil
+		}
+	}
+}
+
+func runIdentify(args []string) error {
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	won := map[string]row{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok {
+			won[a.spec()] = r
+		}
+	}
+
+	kb, err := openKeyboard(args, !identifyOpt.noGrab, func(sym string) bool { _, ok := won[sym]; return ok })
+	if err != nil {
+		return fmt.Errorf("identify: %w", err)
+	}
+	defer kb.close()
+	fmt.Fprintln(os.Stderr, "Press a chord; Esc or Ctrl+C ends.")
+	for {
+		c, err := kb.next(runCtx)
+		switch {
+		case err == io.EOF:
+			return nil
+		case err != nil:
+			return err
+		case c.quit():
+			return nil
+		case c.key == "" && c.code != 0:
+			fmt.Printf("key code %d: not on the layout\n", c.code)
+		default:
+			identifyChord(c.accel, won, lbl)
+		}
+	}
+}
+
+// identifyChord prints what a prints as and what it runs.
+func identifyChord(a accel, won map[string]row, lbl map[string]string) {
+	res := resolve(a.spec(), won, lbl)
+	what := "free"
+	if w := res.Winner; w != nil {
+		what = w.App + ": " + w.Action
+		if n := len(res.Shadowed); n > 0 {
+			what += fmt.Sprintf("  (shadows %d)", n)
+		}
+	}
+	fmt.Printf("%s %s\n", padTo(cmp.Or(res.Shortcut, a.spec()), 28), what)
+}
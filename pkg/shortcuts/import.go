@@ -0,0 +1,191 @@
+package shortcuts
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"slices"
+	"strings"
+)
+
+/*──────────────────── import ────────────────────
+
+The other direction of export: `bindsym … exec CMD`
+lines of an i3 or Sway config become GNOME custom
+shortcuts.  Chords GNOME already uses are skipped
+and reported, as are bindings GNOME cannot make:
+keycodes, mouse buttons, one device's keys, and
+options the -from dialect does not have.  -dry-run
+prints the gsettings calls instead of making them.
+*/
+
+var importOpt struct {
+	from   string
+	dryRun bool
+}
+
+// importApps is the application name of each -from dialect's rows.
+var importApps = map[string]string{"i3": "i3", "sway": "Sway"}
+
+// bindOptions are the bindsym/bindcode options each dialect has.
+// GNOME custom shortcuts have none: --release and --to-code fire on
+// the same key, the rest change nothing it can express.
+var bindOptions = map[string][]string{
+	"i3": {"--release", "--border", "--whole-window", "--exclude-titlebar"},
+	"sway": {"--release", "--border", "--whole-window", "--exclude-titlebar",
+		"--locked", "--inhibited", "--no-repeat", "--no-warn", "--to-code", "--input-device"},
+}
+
+// unimportable says why b cannot become a GNOME custom shortcut, or "".
+func unimportable(from string, b tilingBind) string {
+	for _, o := range b.opts {
+		name, _, _ := strings.Cut(o, "=")
+		switch {
+		case !slices.Contains(bindOptions[from], name):
+			return name + " is not " + from + " syntax"
+		case name == "--input-device":
+			return "GNOME binds every keyboard, not " + o
+		}
+	}
+	a, _ := parseAccel(b.spec)
+	switch k := strings.ToLower(a.key); {
+	case strings.HasPrefix(k, "keycode_"):
+		return "GNOME binds keysyms, not keycodes"
+	case strings.HasPrefix(k, "button") || strings.HasPrefix(k, "btn_"):
+		return "GNOME binds keys, not mouse buttons"
+	}
+	return ""
+}
+
+func init() {
+	commands["import"] = command{
+		help: "add the exec bindings of an i3/sway config as custom shortcuts",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&importOpt.from, "from", "i3", "config dialect: i3 or sway (--to-code, --input-device, …)")
+			fs.BoolVar(&importOpt.dryRun, "dry-run", false, "print the gsettings commands instead of running them")
+			collectFlags(fs)
+		},
+		run: runImport,
+	}
+}
+
+const customDir = "/org/gnome/settings-daemon/plugins/media-keys/custom-keybindings/"
+
+func runImport(args []string) error {
+	app, ok := importApps[importOpt.from]
+	if !ok {
+		return fmt.Errorf("-from: want i3 or sway")
+	}
+	if len(args) != 1 {
+		return errors.New("usage: import -from i3|sway CONFIG")
+	}
+	p := &tilingParser{configFiles: newConfigFiles()}
+	if err := p.file(args[0]); err != nil {
+		return err
+	}
+
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	taken := map[string]row{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok && r.rank < appRank {
+			taken[a.spec()] = r
+		}
+	}
+
+	parent, key := "org.gnome.settings-daemon.plugins.media-keys", "custom-keybindings"
+	var list []string
+	used := map[string]bool{}
+	for _, v := range gvTextStrings(gsettingsGet(parent, key)) {
+		list = append(list, v)
+		used[v] = true
+	}
+	next := func() string {
+		for i := 0; ; i++ {
+			if p := fmt.Sprintf("%scustom%d/", customDir, i); !used[p] {
+				used[p] = true
+				return p
+			}
+		}
+	}
+
+	run := func(schema, key, val string) error {
+		if importOpt.dryRun {
+			fmt.Printf("gsettings set %s %s %s\n", schema, key, shellArg(val))
+			return nil
+		}
+		return gsettingsSet(schema, key, val)
+	}
+
+	bySrc := map[string]tilingBind{}
+	for _, b := range p.binds {
+		bySrc[b.src] = b
+	}
+	binds := desktopClaim(bindRows(app, p.binds, lbl))
+	sortRows(binds)
+	added := 0
+	for _, r := range binds {
+		w := strings.Fields(r.action)
+		if len(w) == 0 || w[0] != "exec" || r.app != app { // only the default mode maps onto GNOME
+			continue
+		}
+		cmd := strings.TrimSpace(strings.TrimPrefix(r.action, "exec"))
+		cmd = strings.TrimSpace(strings.TrimPrefix(cmd, "--no-startup-id"))
+		if len(cmd) > 1 && cmd[0] == '"' && cmd[len(cmd)-1] == '"' {
+			cmd = cmd[1 : len(cmd)-1]
+		}
+		if len(strings.Fields(cmd)) == 0 {
+			fmt.Fprintf(os.Stderr, "skip %s (%s): exec with no command\n", r.accel, r.src)
+			continue
+		}
+		if why := unimportable(importOpt.from, bySrc[r.src]); why != "" {
+			fmt.Fprintf(os.Stderr, "skip %s (%s): %s\n", r.accel, r.src, why)
+			continue
+		}
+		a, _ := parseAccel(r.spec)
+		if w, ok := taken[a.spec()]; ok {
+			fmt.Fprintf(os.Stderr, "skip %s (%s): taken by %s: %s\n", r.accel, r.src, w.app, w.action)
+			continue
+		}
+		dir := next()
+		schema := customSchema + ":" + dir
+		name := path.Base(strings.Fields(cmd)[0])
+		for _, kv := range [][2]string{{"name", name}, {"command", cmd}, {"binding", a.spec()}} {
+			if err := run(schema, kv[0], gvText("s", kv[1])); err != nil {
+				return err
+			}
+		}
+		list = append(list, dir)
+		taken[a.spec()] = r
+		added++
+	}
+	if added == 0 {
+		fmt.Fprintln(os.Stderr, "nothing to import")
+		return nil
+	}
+	quoted := make([]string, len(list))
+	for i, p := range list {
+		quoted[i] = gvText("s", p)
+	}
+	if err := run(parent, key, "["+strings.Join(quoted, ", ")+"]"); err != nil {
+		return err
+	}
+	if !importOpt.dryRun {
+		fmt.Printf("imported %d shortcut(s)\n", added)
+	}
+	return nil
+}
+
+// shellArg quotes a GVariant for pasting, in double quotes when that
+// needs no escaping (as conflicts prints them).
+func shellArg(v string) string {
+	if !strings.ContainsAny(v, "\"$`\\!") {
+		return `"` + v + `"`
+	}
+	return shellQuote(v)
+}
@@ -0,0 +1,138 @@
+package shortcuts
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// TestImportDryRun imports a small i3 config with -dry-run and checks
+// the gsettings calls it prints and the lines it skips.
+func TestImportDryRun(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config": `set $mod Mod4
+bindsym $mod+Return exec --no-startup-id /usr/bin/alacritty -e tmux
+bindsym $mod+n exec "notify-send 'hi there'"
+bindsym $mod+x exec
+bindsym $mod+h focus left
+mode "resize" {
+    bindsym $mod+t exec xterm
+}
+`})
+	savedOpt, savedDefaults, savedCache := importOpt, defaultsOnly, schemaCache
+	t.Cleanup(func() { importOpt, defaultsOnly, schemaCache = savedOpt, savedDefaults, savedCache })
+	withSchemaDir(t, nil)
+	importOpt.from, importOpt.dryRun = "i3", true
+	defaultsOnly, schemaCache = true, map[string]*schemaInfo{}
+
+	stdout, stderr := capture(t, func() {
+		if err := runImport([]string{filepath.Join(dir, "config")}); err != nil {
+			t.Error(err)
+		}
+	})
+
+	const c0, c1 = customSchema + ":" + customDir + "custom0/", customSchema + ":" + customDir + "custom1/"
+	want := `gsettings set ` + c0 + ` name "'alacritty'"
+gsettings set ` + c0 + ` command "'/usr/bin/alacritty -e tmux'"
+gsettings set ` + c0 + ` binding "'<Super>Return'"
+gsettings set ` + c1 + ` name "'notify-send'"
+gsettings set ` + c1 + ` command ` + shellArg(gvText("s", "notify-send 'hi there'")) + `
+gsettings set ` + c1 + ` binding "'<Super>n'"
+gsettings set org.gnome.settings-daemon.plugins.media-keys custom-keybindings "['` + customDir + `custom0/', '` + customDir + `custom1/']"
+`
+	if stdout != want {
+		t.Errorf("stdout:\n%s\nwant:\n%s", stdout, want)
+	}
+	if !strings.HasSuffix(stderr, "config:4): exec with no command\n") {
+		t.Errorf("stderr = %q, want the bare exec skipped", stderr)
+	}
+}
+
+// capture runs f with os.Stdout and os.Stderr going to pipes.
+func capture(t *testing.T, f func()) (stdout, stderr string) {
+	t.Helper()
+	var out [2]string
+	var wg sync.WaitGroup
+	std := []**os.File{&os.Stdout, &os.Stderr}
+	var saved, pipes [2]*os.File
+	for i, p := range std {
+		r, w, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		saved[i], pipes[i], *p = *p, w, w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b, _ := io.ReadAll(r)
+			out[i] = string(b)
+		}()
+	}
+	f()
+	for i, p := range std {
+		*p = saved[i]
+		pipes[i].Close()
+	}
+	wg.Wait()
+	return out[0], out[1]
+}
+
+// TestImportDialects imports Sway-only syntax with -from sway and
+// -from i3: only Sway knows --to-code, and neither dialect's keycodes,
+// mouse buttons or single-device bindings become GNOME shortcuts.
+func TestImportDialects(t *testing.T) {
+	dir := writeFiles(t, map[string]string{"config": `set $mod Mod4
+bindsym --to-code $mod+Return exec foot
+bindsym --release $mod+p exec grim
+bindsym --input-device=1:1:AT_Translated_Set_2_keyboard $mod+d exec wofi
+bindcode $mod+49 exec swaylock
+bindsym --whole-window button2 exec xkill
+bindsym --locked {
+    XF86AudioMute exec pactl set-sink-mute @DEFAULT_SINK@ toggle
+}
+`})
+	savedOpt, savedDefaults, savedCache := importOpt, defaultsOnly, schemaCache
+	t.Cleanup(func() { importOpt, defaultsOnly, schemaCache = savedOpt, savedDefaults, savedCache })
+	withSchemaDir(t, nil)
+	defaultsOnly, schemaCache = true, map[string]*schemaInfo{}
+
+	for _, c := range []struct {
+		from     string
+		bindings []string
+		skipped  []string
+	}{
+		{"sway", []string{"'<Super>Return'", "'<Super>p'", "'XF86AudioMute'"}, []string{
+			"config:4): GNOME binds every keyboard, not --input-device=1:1:AT_Translated_Set_2_keyboard",
+			"config:5): GNOME binds keysyms, not keycodes",
+		}},
+		{"i3", []string{"'<Super>p'"}, []string{
+			"config:2): --to-code is not i3 syntax",
+			"config:4): --input-device is not i3 syntax",
+			"config:8): --locked is not i3 syntax",
+		}},
+	} {
+		importOpt.from, importOpt.dryRun = c.from, true
+		stdout, stderr := capture(t, func() {
+			if err := runImport([]string{filepath.Join(dir, "config")}); err != nil {
+				t.Error(err)
+			}
+		})
+		var got []string
+		for _, l := range strings.Split(stdout, "\n") {
+			if f := strings.Fields(l); len(f) == 5 && f[3] == "binding" {
+				got = append(got, strings.Trim(f[4], `"`))
+			}
+		}
+		if !slices.Equal(got, c.bindings) {
+			t.Errorf("-from %s: bindings %q, want %q", c.from, got, c.bindings)
+		}
+		for _, s := range c.skipped {
+			if !strings.Contains(stderr, s) {
+				t.Errorf("-from %s: stderr lacks %q:\n%s", c.from, s, stderr)
+			}
+		}
+	}
+}
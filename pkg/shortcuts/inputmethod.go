@@ -0,0 +1,84 @@
+package shortcuts
+
+import (
+	"fmt"
+	"strings"
+)
+
+/*──────────── input-method shortcuts ────────────
+
+Input-source switching (WM keys, XKB grp toggles)
+and IBus panel hotkeys (emoji, unicode entry) eat
+their chords before the focused application sees
+them — Super+Space and Ctrl+Shift+U are the usual
+victims.
+*/
+
+// IBus keys holding accelerators, with the action they trigger;
+// everything else in its schemas is configuration.
+var ibusHotkeys = map[string]string{
+	"triggers":            "Switch Input Method",
+	"hotkey":              "Emoji Picker",
+	"unicode-hotkey":      "Unicode Entry",
+	"next-engine-in-menu": "Next Input Method",
+	"previous-engine":     "Previous Input Method",
+}
+
+func isInputMethod(schema, key string) bool {
+	return strings.HasPrefix(schema, "org.freedesktop.ibus.") && ibusHotkeys[key] != ""
+}
+
+func isInputSwitch(r row) bool {
+	return r.app == "Input Method" || r.key == "xkb-options" ||
+		strings.HasPrefix(r.key, "switch-input-source")
+}
+
+func init() {
+	commands["input-method"] = command{
+		help:  "input-source and IBus chords and what they intercept",
+		flags: collectFlags,
+		run:   runInputMethod,
+	}
+}
+
+func runInputMethod([]string) error {
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+
+	apps := map[string][]appAccel{}
+	for _, aa := range appAccels() {
+		if a, ok := parseAccel(aa.spec); ok {
+			apps[a.spec()] = append(apps[a.spec()], aa)
+		}
+	}
+
+	n := 0
+	for _, r := range rows {
+		a, _ := parseAccel(r.spec)
+		for _, c := range append([]row{r}, r.lost...) {
+			if !isInputSwitch(c) {
+				continue
+			}
+			n++
+			fmt.Printf("%-24s %s: %s\n", c.accel, c.app, c.action)
+			if c.spec != r.spec || c.schema != r.schema || c.key != r.key {
+				fmt.Printf("    never fires: taken by %s: %s\n", r.app, r.action)
+				continue
+			}
+			for _, l := range r.lost {
+				fmt.Printf("    intercepts %s: %s\n", l.app, l.action)
+			}
+			for _, aa := range apps[a.spec()] {
+				fmt.Printf("    intercepts %s: %s\n", aa.app, aa.action)
+			}
+		}
+	}
+	if n == 0 {
+		fmt.Println("No input-method shortcuts configured.")
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package shortcuts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*───────────────────── KDE ──────────────────────
+
+-desktop kde reads kglobalshortcutsrc:
+
+	[kwin]
+	Window Close=Alt+F4,Alt+F4,Close Window
+	[org.kde.konsole.desktop]
+	_launch=Ctrl+Alt+T,none,Konsole
+
+Each value is "active,default,friendly name";
+several active chords are split by \t and
+"none" means unbound.  A row's key is
+"component:action", which wm_actions.tsv uses to
+match KDE actions to GNOME ones.
+*/
+
+func init() {
+	desktops["kde"] = kdeRows
+}
+
+// kdeKeys are Qt key names that differ from X keysyms.
+var kdeKeys = map[string]string{
+	"Esc": "Escape", "Space": "space", "Backspace": "BackSpace",
+	"Del": "Delete", "Ins": "Insert", "PgUp": "Page_Up", "PgDown": "Page_Down",
+	"Enter": "KP_Enter", "Volume Up": "XF86AudioRaiseVolume",
+	"Volume Down": "XF86AudioLowerVolume", "Volume Mute": "XF86AudioMute",
+	"Media Play": "XF86AudioPlay", "Media Next": "XF86AudioNext",
+	"Media Previous": "XF86AudioPrev", "Media Stop": "XF86AudioStop",
+	"Monitor Brightness Up":   "XF86MonBrightnessUp",
+	"Monitor Brightness Down": "XF86MonBrightnessDown",
+	"Microphone Mute":         "XF86AudioMicMute",
+}
+
+var kdeMods = map[string]string{
+	"Meta": "<Super>", "Ctrl": "<Control>", "Alt": "<Alt>", "Shift": "<Shift>",
+}
+
+// kdeSpec turns "Meta+Shift+Volume Up" into "<Super><Shift>XF86AudioRaiseVolume".
+func kdeSpec(s string) (string, bool) {
+	parts := strings.Split(s, "+")
+	if strings.HasSuffix(s, "++") { // the + key itself
+		parts = append(strings.Split(strings.TrimSuffix(s, "++"), "+"), "plus")
+	}
+	var b strings.Builder
+	for _, m := range parts[:len(parts)-1] {
+		t, ok := kdeMods[m]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(t)
+	}
+	key := parts[len(parts)-1]
+	if k, ok := kdeKeys[key]; ok {
+		key = k
+	}
+	if key == "" || strings.Contains(key, " ") {
+		return "", false
+	}
+	b.WriteString(key)
+	return b.String(), true
+}
+
+func kdeRows(lbl map[string]string) []row {
+	path := wmConfig
+	if path == "" {
+		cfg, _ := os.UserConfigDir()
+		path = filepath.Join(cfg, "kglobalshortcutsrc")
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		warn(err)
+		return nil
+	}
+	defer f.Close()
+
+	var rows []row
+	group, app := "", ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		l := strings.TrimSpace(sc.Text())
+		if strings.HasPrefix(l, "[") {
+			group = strings.Trim(l, "[]")
+			app = ""
+			continue
+		}
+		id, val, ok := strings.Cut(l, "=")
+		if !ok || group == "" {
+			continue
+		}
+		if id == "_k_friendly_name" {
+			app = val
+			continue
+		}
+		f := strings.SplitN(val, ",", 3)
+		if len(f) < 3 {
+			continue
+		}
+		name := app
+		if name == "" {
+			name = humanise(strings.TrimSuffix(strings.TrimPrefix(group, "org.kde."), ".desktop"))
+		}
+		for _, c := range strings.Split(strings.ReplaceAll(f[0], `\t`, "\t"), "\t") { // KConfig escapes the tab
+			if c == "" || c == "none" {
+				continue
+			}
+			spec, ok := kdeSpec(c)
+			if !ok {
+				continue
+			}
+			acc, ok := fmtKey(spec, lbl)
+			if !ok {
+				continue
+			}
+			rows = append(rows, row{accel: acc, app: name, action: f[2], order: n,
+				spec: spec, key: group + ":" + id, src: fmt.Sprintf("%s:%d", path, n)})
+		}
+	}
+	return desktopClaim(rows)
+}
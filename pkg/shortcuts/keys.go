@@ -0,0 +1,223 @@
+package shortcuts
+
+import (
+	"cmp"
+	_ "embed"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*────────────────── key names ───────────────────
+
+How non-character keys are printed.  "glyphs" suits
+cheatsheets (Markdown), "words" suits terminals and
+machine-readable output; "auto" picks per format.
+"both" prints the glyph before the word, "⌘ Command
++ ⇥ Tab", for whoever is still learning the symbols.
+-sep joins a chord's parts (" + ", "-", "") and
+-mod-order keeps GNOME's Ctrl, Shift, Alt, Super
+order or the order the schema spelt them in, so a
+cheatsheet can follow a house style.
+The words follow -lang, else LC_MESSAGES, through
+key_labels.tsv (Strg, Maj, Mayús…), which the
+config dir may replace.
+*/
+
+//go:embed key_labels.tsv
+var keyLabelsTSV string
+
+var langOpt string // -lang
+
+var keyGlyphs = map[string]string{
+	"Left": "←", "Right": "→", "Up": "↑", "Down": "↓",
+	"Return": "⏎", "KP_Enter": "⌤", "Escape": "⎋", "space": "␣",
+	"Tab": "⇥", "ISO_Left_Tab": "⇤", "BackSpace": "⌫", "Delete": "⌦",
+	"Home": "⇱", "End": "⇲", "Page_Up": "⇞", "Page_Down": "⇟",
+	"Prior": "⇞", "Next": "⇟", "Caps_Lock": "⇪",
+}
+
+var keyWords = map[string]string{
+	"Return": "Enter", "Escape": "Esc", "space": "Space",
+	"BackSpace": "Backspace", "Prior": "Page Up", "Next": "Page Down",
+	"Caps_Lock": "Caps Lock", "Scroll_Lock": "Scroll Lock",
+	"Alt_L": "Left Alt", "Alt_R": "Right Alt",
+	"Control_L": "Left Ctrl", "Control_R": "Right Ctrl",
+	"Shift_L": "Left Shift", "Shift_R": "Right Shift",
+	"Super_L": "Left Super", "Super_R": "Right Super",
+	"Print": "Print Screen", "Sys_Req": "SysRq", "Num_Lock": "Num Lock",
+	"Multi_key": "Compose", "ISO_Level3_Shift": "AltGr",
+	"Above_Tab": "` (key above Tab)", "Mode_switch": "Mode Switch",
+	"grave": "`", "quoteleft": "`", "asciitilde": "~", "asciicircum": "^",
+	"bracketleft": "[", "bracketright": "]", "braceleft": "{", "braceright": "}",
+	"parenleft": "(", "parenright": ")", "less": "<", "greater": ">",
+	"numbersign": "#", "exclam": "!", "question": "?", "quotedbl": "\"",
+	"underscore": "_",
+}
+
+// kpWords name keypad keys where "Numpad " + the rest would not:
+// KP_Add is Numpad +.
+var kpWords = map[string]string{
+	"Add": "+", "Subtract": "-", "Multiply": "*", "Divide": "/",
+	"Decimal": ".", "Separator": ",", "Equal": "=",
+}
+
+// modGlyphs are the modifier symbols -glyphs prints instead of words,
+// as printed on each keyboard.  Like the keycaps they sit flush
+// against the rest of the chord: ⌃⇧Q.
+var modGlyphs = [...]map[string]string{
+	kbApple:  {"<Control>": "⌃", "<Shift>": "⇧", "<Alt>": "⌥", "<Super>": "⌘"},
+	kbPC:     {"<Super>": "⊞"},
+	kbChrome: {"<Super>": "🔍"},
+}
+
+// isModGlyph reports whether l is one of modGlyphs' symbols.
+func isModGlyph(l string) bool {
+	return modGlyphPrefix(l) == l && l != ""
+}
+
+// modGlyphPrefix is the modGlyphs symbol s starts with, if any.
+func modGlyphPrefix(s string) string {
+	for _, m := range modGlyphs {
+		for _, g := range m {
+			if strings.HasPrefix(s, g) {
+				return g
+			}
+		}
+	}
+	return ""
+}
+
+var keyStyles = []string{"auto", "words", "glyphs", "both"}
+
+var modOrders = []string{"canonical", "schema"}
+
+var (
+	keyStyle    = "auto"
+	glyphsOnMod bool // -glyphs
+	accelSep    = " + "
+	sepSet      bool // -sep or separator given: glyphs keep it too
+	modOrder    = "canonical"
+)
+
+func displayFlags(fs *flag.FlagSet) {
+	fs.StringVar(&keyStyle, "keys", cmp.Or(userPrefs.keys, keyStyle), "how to print special keys and modifiers: auto, words, glyphs or both")
+	fs.BoolVar(&glyphsOnMod, "glyphs", false, "print modifiers as the keyboard's symbols: ⌘⌥⇧⌃ (Apple), ⊞ (PC), 🔍 (Chromebook)")
+	if userPrefs.separator != nil {
+		accelSep, sepSet = *userPrefs.separator, true
+	}
+	fs.Func("sep", `what joins a chord's modifiers and key, e.g. "-" or "" (default " + ", none after -glyphs symbols)`, func(s string) error {
+		accelSep, sepSet = s, true
+		return nil
+	})
+	modOrder = cmp.Or(userPrefs.modOrder, modOrder)
+	fs.Func("mod-order", "modifier order: canonical (Ctrl, Shift, Alt, Super) or schema (as GNOME stores it) (default "+modOrder+")", func(s string) error {
+		if !slices.Contains(modOrders, s) {
+			return fmt.Errorf("want canonical or schema")
+		}
+		modOrder = s
+		return nil
+	})
+	fs.StringVar(&langOpt, "lang", "", "language of key names and actions, e.g. de or fr_CA (default: LC_MESSAGES)")
+}
+
+var keyLabelTable map[string]map[string]string // language → token → label
+
+// localLabels are key_labels.tsv's labels for the user's language.
+func localLabels() map[string]string {
+	if keyLabelTable == nil {
+		file, data := "key_labels.tsv", keyLabelsTSV
+		user := filepath.Join(configDir(), file)
+		if b, err := os.ReadFile(user); err == nil {
+			file, data = user, string(b)
+		}
+		keyLabelTable = map[string]map[string]string{}
+		for n, l := range strings.Split(data, "\n") {
+			if l == "" || strings.HasPrefix(l, "#") {
+				continue
+			}
+			f := strings.Split(l, "\t")
+			if len(f) < 3 {
+				badLine(file, n, "want language<TAB>token<TAB>label, got %q", l)
+				continue
+			}
+			if keyLabelTable[f[0]] == nil {
+				keyLabelTable[f[0]] = map[string]string{}
+			}
+			keyLabelTable[f[0]][f[1]] = f[2]
+		}
+	}
+	out := map[string]string{}
+	langs := messageLangs()
+	for i := len(langs) - 1; i >= 0; i-- {
+		for t, v := range keyLabelTable[langs[i]] {
+			out[t] = v
+		}
+	}
+	return out
+}
+
+// styleFor resolves "auto" for an output format.
+func styleFor(format string) string {
+	if keyStyle != "auto" {
+		return keyStyle
+	}
+	if format == "md" || format == "markdown" {
+		return "glyphs"
+	}
+	return "words"
+}
+
+// labels is the modifier map for the chosen layout plus key names for
+// the given output format, character keys named as the active XKB
+// layout prints them.
+func labels(format string) map[string]string {
+	return labelsOn(layout(), format)
+}
+
+// labelsOn is labels for keyboard k, for browse switching keyboards.
+func labelsOn(k kb, format string) map[string]string {
+	lbl := labelsFor(k, format)
+	if _, km, us, ok := layoutKeys(); ok {
+		layoutCaps(lbl, km, us)
+	}
+	return userLabels(lbl, k)
+}
+
+// userLabels lays labels.toml, its table for keyboard k, then
+// config.toml's [labels] over lbl.
+func userLabels(lbl map[string]string, k kb) map[string]string {
+	for _, m := range []map[string]string{labelFile[""], labelFile[kbKeys[k]], userPrefs.labels} {
+		for t, v := range m {
+			lbl[t] = v
+		}
+	}
+	return lbl
+}
+
+// labelsFor is labels for a known keyboard, without asking.
+func labelsFor(k kb, format string) map[string]string {
+	lbl := modLabels(k)
+	for sym, v := range keyWords {
+		lbl[sym] = v
+	}
+	for t, v := range localLabels() {
+		if !strings.HasPrefix(t, "<") {
+			lbl[t] = v
+		}
+	}
+	switch styleFor(format) { // words remain for keys without a glyph
+	case "glyphs":
+		for sym, v := range keyGlyphs {
+			lbl[sym] = v
+		}
+	case "both":
+		for sym, v := range keyGlyphs {
+			lbl[sym] = v + " " + cmp.Or(lbl[sym], humanise(sym))
+		}
+	}
+	return lbl
+}
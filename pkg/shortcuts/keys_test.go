@@ -0,0 +1,74 @@
+package shortcuts
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGlyphChords(t *testing.T) {
+	t.Setenv("LANGUAGE", "")
+	t.Setenv("LC_ALL", "C")
+	saved, savedSep, savedSet, savedDefaults := glyphsOnMod, accelSep, sepSet, defaultsOnly
+	t.Cleanup(func() { glyphsOnMod, accelSep, sepSet, defaultsOnly = saved, savedSep, savedSet, savedDefaults })
+	withSchemaDir(t, nil)
+	defaultsOnly = true // no remaps or xkb-options from this machine
+
+	for _, c := range []struct {
+		k      kb
+		glyphs bool
+		sep    *string
+		spec   string
+		want   string
+	}{
+		{kbApple, true, nil, "<Control><Shift>q", "⌃⇧Q"},
+		{kbApple, true, nil, "<Super><Alt>Left", "⌥⌘Left"},
+		{kbApple, true, nil, "<Super>space", "⌘Space"},
+		{kbPC, true, nil, "<Super>e", "⊞E"},
+		{kbPC, true, nil, "<Control><Super>e", "Ctrl + ⊞E"},
+		{kbApple, true, ptr(" + "), "<Control><Shift>q", "⌃ + ⇧ + Q"},
+		{kbApple, true, ptr("-"), "<Super>q", "⌘-Q"},
+		{kbApple, false, nil, "<Control><Shift>q", "Ctrl + Shift + Q"},
+		{kbPC, false, nil, "<Super>bracketleft", "Win + ["},
+		{kbPC, false, nil, "<Super>grave", "Win + `"},
+		{kbPC, false, nil, "<Control>minus", "Ctrl + Minus"},
+	} {
+		glyphsOnMod, accelSep, sepSet = c.glyphs, " + ", false
+		if c.sep != nil {
+			accelSep, sepSet = *c.sep, true
+		}
+		got, ok := fmtKey(c.spec, labelsFor(c.k, "text"))
+		if !ok || got != c.want {
+			t.Errorf("%s %s glyphs=%v sep=%v: %q, want %q", kbNames[c.k], c.spec, c.glyphs, c.sep != nil, got, c.want)
+		}
+	}
+}
+
+func ptr[T any](v T) *T { return &v }
+
+func TestSplitAccel(t *testing.T) {
+	saved, savedSep, savedSet := glyphsOnMod, accelSep, sepSet
+	t.Cleanup(func() { glyphsOnMod, accelSep, sepSet = saved, savedSep, savedSet })
+	for _, c := range []struct {
+		glyphs    bool
+		sep       string
+		accel     string
+		spec      string
+		mods, key string
+	}{
+		{false, " + ", "Ctrl + Shift + Q", "<Primary><Shift>q", "Ctrl|Shift", "Q"},
+		{false, "-", "Ctrl--", "<Control>minus", "Ctrl", "-"},
+		{false, " + ", "Win + Numpad +", "<Super>KP_Add", "Win", "Numpad +"},
+		{false, " + ", "Shift + Tab", "ISO_Left_Tab", "Shift", "Tab"},
+		{false, " + ", "Ctrl + Alt", "<Control><Alt>", "Ctrl", "Alt"},
+		{false, "", "CtrlQ", "<Control>q", "", "CtrlQ"},
+		{true, " + ", "⌃⇧Q", "<Control><Shift>q", "⌃|⇧", "Q"},
+		{true, " + ", "Ctrl + ⊞E", "<Control><Super>e", "Ctrl|⊞", "E"},
+		{false, " + ", "Lid closed", "", "", "Lid closed"},
+	} {
+		glyphsOnMod, accelSep, sepSet = c.glyphs, c.sep, false
+		mods, _, key := splitAccel(Binding{Accel: c.accel, Spec: c.spec})
+		if got := strings.Join(mods, "|"); got != c.mods || key != c.key {
+			t.Errorf("splitAccel(%q, %s) = %q, %q, want %q, %q", c.accel, c.spec, got, key, c.mods, c.key)
+		}
+	}
+}
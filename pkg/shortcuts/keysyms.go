@@ -0,0 +1,75 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+/*────────────── characters of keysyms ─────────────
+
+A binding on odiaeresis, ccedilla or Cyrillic_ka
+means the key that types ö, ç or к, so that is what
+prints, capitalised as Latin letters are.  Dead
+keys print the accent they add.  ASCII punctuation
+with a plain name keeps it (Minus, Comma), which
+reads better in a chord than a lone "-"; the rest
+print as the character, [ for bracketleft and `
+for grave (keyWords).
+*/
+
+//go:embed keysyms.tsv
+var keysymsTSV string
+
+var keysymRunes = parseKeysyms(keysymsTSV)
+
+func parseKeysyms(data string) map[string]rune {
+	out := map[string]rune{}
+	for _, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		name, hex, ok := strings.Cut(l, "\t")
+		if n, err := strconv.ParseUint(hex, 16, 32); ok && err == nil {
+			out[name] = rune(n)
+		}
+	}
+	return out
+}
+
+// keysymChar is the character sym types: from keysyms.tsv, or U+hex
+// for "U0431".
+func keysymChar(sym string) (rune, bool) {
+	if r, ok := keysymRunes[sym]; ok {
+		return r, true
+	}
+	if hex, ok := strings.CutPrefix(sym, "U"); ok && len(hex) >= 4 {
+		if n, err := strconv.ParseUint(hex, 16, 32); err == nil && n <= unicode.MaxRune {
+			return rune(n), true
+		}
+	}
+	if r := []rune(sym); len(r) == 1 {
+		return r[0], true
+	}
+	return 0, false
+}
+
+// keyChar names a key by the character it types when that is not
+// ASCII: "Ö" for odiaeresis, "´ (dead)" for dead_acute.
+func keyChar(sym string) (string, bool) {
+	if accent, ok := strings.CutPrefix(sym, "dead_"); ok {
+		if c := heatNames[sym]; c != "" {
+			return c + " (dead)", true
+		}
+		if r, ok := keysymChar(accent); ok && r > unicode.MaxASCII {
+			return string(r) + " (dead)", true
+		}
+		return "", false
+	}
+	r, ok := keysymChar(sym)
+	if !ok || r <= unicode.MaxASCII {
+		return "", false
+	}
+	return string(unicode.ToUpper(r)), true
+}
@@ -0,0 +1,277 @@
+package shortcuts
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+/*────────── AltGr / dead-key reachability ───────
+
+A binding on `<Super>bracketleft` needs a key that
+types "[" without modifiers.  On many European
+layouts that character sits behind AltGr or only
+comes out of a dead key, so the shortcut can never
+be pressed.  We compare each character key against
+the active layout and suggest whatever that layout
+has at the US position instead.  A key the layout
+types only with Shift (1 on AZERTY) is flagged too:
+the binding names a symbol its key does not show.
+*/
+
+var layoutCheckOpt struct{ xkb string }
+
+func init() {
+	commands["layout-check"] = command{
+		help: "bindings unreachable on the active layout (Shift, AltGr, dead keys)",
+		flags: func(fs *flag.FlagSet) {
+			fs.StringVar(&layoutCheckOpt.xkb, "xkb", "", `layout to check, e.g. "de+nodeadkeys" (default: active input source)`)
+			collectFlags(fs)
+		},
+		run: runLayoutCheck,
+	}
+}
+
+// characters that dead keys produce when followed by space
+var deadFor = map[string]string{
+	"asciicircum": "dead_circumflex", "grave": "dead_grave",
+	"asciitilde": "dead_tilde", "apostrophe": "dead_acute",
+	"quotedbl": "dead_diaeresis", "acute": "dead_acute",
+}
+
+type reach struct {
+	problem string
+	instead string // suggested keysym, "" if none
+}
+
+// reachability is zero-valued when key is fine (or not a character key).
+func reachability(key string, us, km keymap) reach {
+	code, uslevel := us.find(key)
+	if uslevel < 0 {
+		return reach{} // named key (F1, Left, …): same everywhere
+	}
+	_, level := km.find(key)
+	var r reach
+	switch {
+	case level == 0:
+		return reach{}
+	case level == 1:
+		r.problem = "needs Shift"
+	case level >= 2:
+		r.problem = "needs AltGr"
+	case km.has(deadFor[key]):
+		r.problem = "only via dead key"
+	default:
+		r.problem = "not on layout"
+	}
+	if syms := km[code]; len(syms) > 0 && !strings.HasPrefix(syms[0], "dead_") && syms[0] != key {
+		r.instead = syms[0]
+	}
+	return r
+}
+
+func (km keymap) has(sym string) bool {
+	_, l := km.find(sym)
+	return sym != "" && l >= 0
+}
+
+func runLayoutCheck([]string) error {
+	name := layoutCheckOpt.xkb
+	if name == "" {
+		name = activeLayout()
+	}
+	km, ok := loadKeymap(name)
+	if !ok {
+		return fmt.Errorf("layout-check: no xkb symbols for %q under %s", name, xkbRoot())
+	}
+	us, _ := loadKeymap("us")
+
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+	n := 0
+	for _, r := range rows {
+		a, ok := parseAccel(r.spec)
+		if !ok || a.key == "" {
+			continue
+		}
+		re := reachability(a.key, us, km)
+		if re.problem == "" {
+			continue
+		}
+		if n == 0 {
+			line := strings.Repeat("─", 100)
+			fmt.Println(line)
+			fmt.Printf("%-24s %-30s %-20s %s\n", "Shortcut", "Action", "On "+name, "Try instead")
+			fmt.Println(line)
+		}
+		n++
+		try := ""
+		if re.instead != "" {
+			a.key = re.instead
+			try, _ = fmtAccel(a.spec(), lbl)
+		}
+		fmt.Printf("%-24s %-30s %-20s %s\n", r.accel, r.action, re.problem, try)
+	}
+	if n == 0 {
+		fmt.Printf("Every binding is reachable on %s.\n", name)
+	}
+	return nil
+}
+
+/*─────────────── keys as printed ────────────────
+
+Off a US layout, a character key is named by what
+its cap shows.  A keysym the layout types only with
+Shift or AltGr prints as that key's legends, "&/1"
+for 1 on French AZERTY, and list warns about it
+below the table.
+*/
+
+// symChar is what a key cap shows for sym.
+func symChar(sym string) string {
+	if c := heatNames[sym]; c != "" {
+		return c
+	}
+	if r, ok := keysymChar(sym); ok {
+		return string(unicode.ToUpper(r))
+	}
+	return humanise(sym)
+}
+
+// layoutKeys is the active layout's keymap and US's, or ok false on
+// US itself or a layout without XKB symbols.
+func layoutKeys() (name string, km, us keymap, ok bool) {
+	name = activeLayout()
+	if name == "us" {
+		return name, nil, nil, false
+	}
+	if _, ok := loadKeymap(name); !ok {
+		return name, nil, nil, false
+	}
+	us, _ = loadKeymap("us")
+	return name, layoutKeymap(name, xkbOptions()), us, true
+}
+
+// layoutCaps names each character key US types unshifted by the
+// legends of the key that types it on km, where that key is not its own.
+func layoutCaps(lbl map[string]string, km, us keymap) {
+	for _, syms := range us {
+		if len(syms) == 0 || lbl[syms[0]] != "" {
+			continue
+		}
+		sym := syms[0]
+		code, level := km.find(sym)
+		if level < 1 {
+			continue
+		}
+		if own := km[code][0]; own != "" && own != "NoSymbol" && !strings.HasPrefix(own, "dead_") {
+			lbl[sym] = symChar(own) + "/" + symChar(sym)
+		} else {
+			lbl[sym] = symChar(sym)
+		}
+	}
+}
+
+// warnLayout reports bindings whose key the active layout types only
+// with Shift or AltGr, or not at all.
+func warnLayout(rows []row) {
+	name, km, us, ok := layoutKeys()
+	if !ok {
+		return
+	}
+	var bad []string
+	for _, r := range rows {
+		a, ok := parseAccel(r.spec)
+		if !ok || a.key == "" {
+			continue
+		}
+		re := reachability(a.key, us, km)
+		if re.problem == "" {
+			continue
+		}
+		line := fmt.Sprintf("  %-24s %-30s %s", r.accel, r.action, re.problem)
+		if re.instead != "" {
+			line += " (try " + symChar(re.instead) + ")"
+		}
+		bad = append(bad, line)
+	}
+	if len(bad) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\nwarning: the %s layout types these bindings' keys only with a modifier, or not at all:\n", name)
+	for _, b := range bad {
+		fmt.Fprintln(os.Stderr, b)
+	}
+}
+
+/*────────────── alternative layouts ──────────────
+
+list -positions dvorak,colemak tags each binding
+with where its key sits on those layouts, named by
+the QWERTY key at that spot: Super+T on Dvorak is
+pressed where QWERTY has K.  It helps whoever is
+learning a layout find shortcuts by hand position.
+*/
+
+// altLayouts are short names for the usual alternative layouts.
+var altLayouts = map[string]string{
+	"dvorak": "us+dvorak", "colemak": "us+colemak", "colemak-dh": "us+colemak_dh",
+	"workman": "us+workman",
+}
+
+// altLayout is the XKB name for a -positions entry.
+func altLayout(name string) string { return cmp.Or(altLayouts[name], name) }
+
+// altKeymaps caches the keymaps of -positions layouts for every
+// caller of positions, whatever lock it holds.
+var (
+	altKeymapsMu sync.Mutex
+	altKeymaps   = map[string]keymap{}
+)
+
+func altKeymap(name string) keymap {
+	altKeymapsMu.Lock()
+	defer altKeymapsMu.Unlock()
+	km, ok := altKeymaps[name]
+	if !ok {
+		km, _ = loadKeymap(altLayout(name))
+		altKeymaps[name] = km
+	}
+	return km
+}
+
+// positions is "Dvorak: K position" for each of layouts on which
+// spec's key sits away from its QWERTY spot.
+func positions(spec string, layouts []string) string {
+	a, ok := parseAccel(spec)
+	if !ok || a.key == "" || len(layouts) == 0 {
+		return ""
+	}
+	us, _ := loadKeymap("us")
+	if _, l := us.find(a.key); l < 0 {
+		return "" // named keys stay put
+	}
+	var out []string
+	for _, name := range layouts {
+		km := altKeymap(name)
+		if _, short := altLayouts[name]; short {
+			name = titleCase(name)
+		}
+		code, level := km.find(a.key)
+		switch {
+		case level < 0:
+			out = append(out, name+": not on layout")
+		case len(us[code]) > 0 && us[code][0] != a.key:
+			out = append(out, name+": "+symChar(us[code][0])+" position")
+		}
+	}
+	return strings.Join(out, ", ")
+}
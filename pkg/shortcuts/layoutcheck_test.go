@@ -0,0 +1,43 @@
+package shortcuts
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPositionsGolden(t *testing.T) {
+	dir := filepath.Join("testdata", "golden", "gnome46-wayland")
+	t.Setenv("XKB_CONFIG_ROOT", filepath.Join(dir, "xkb"))
+	savedDump, savedDir, savedShell, savedCache := dumpOpt, schemaDirOpt, shellVersionOpt, schemaCache
+	t.Cleanup(func() {
+		dumpOpt, schemaDirOpt, shellVersionOpt, schemaCache = savedDump, savedDir, savedShell, savedCache
+		altKeymaps = map[string]keymap{}
+	})
+	d, err := readDump(filepath.Join(dir, "dump.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpOpt, schemaDirOpt, shellVersionOpt = d, filepath.Join(dir, "schemas"), "46.0"
+	schemaCache, altKeymaps = map[string]*schemaInfo{}, map[string]keymap{}
+
+	rows, _, err := collect(labelsFor(kbPC, "text"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, b := range toBindings(rows, []string{"dvorak"}) {
+		got[b.App+": "+b.Action] = b.Position
+	}
+	for action, want := range map[string]string{
+		"Window Manager: Minimize":        "Dvorak: J position",
+		"Screenshot UI: Select Area":      "Dvorak: ; position",
+		"Screenshot UI: Capture Window":   "Dvorak: , position",
+		"Screenshot UI: Show Pointer":     "Dvorak: R position",
+		"Screenshot UI: Capture":          "", // Space does not move
+		"Window Manager: Maximise Window": "",
+	} {
+		if p, ok := got[action]; !ok || p != want {
+			t.Errorf("%s: position %q, want %q", action, p, want)
+		}
+	}
+}
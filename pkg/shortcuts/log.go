@@ -0,0 +1,56 @@
+package shortcuts
+
+import (
+	"flag"
+	"log/slog"
+	"os"
+)
+
+/*──────────────────── logging ────────────────────
+
+-v logs how the table came about: the schema dirs
+indexed, what each collector found, and every
+binding dropped and why (media key, unreadable,
+other session, shadowed).  -vv adds each schema
+file parsed and unbound keys.  Logs go to stderr
+only, so stdout stays the table.
+*/
+
+var (
+	logger    = slog.New(slog.DiscardHandler)
+	logInfo   bool // -v
+	logDetail bool // -vv
+)
+
+func logFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&logInfo, "v", false, "log schema dirs read and bindings dropped or shadowed, to stderr")
+	fs.BoolVar(&logDetail, "vv", false, "like -v, plus every schema file parsed and unbound key")
+}
+
+// startLogging points logger at stderr for -v and -vv.
+func startLogging() {
+	lvl := slog.LevelInfo
+	switch {
+	case logDetail:
+		lvl = slog.LevelDebug
+	case !logInfo:
+		return
+	}
+	logger = slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{
+		Level: lvl,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey && len(groups) == 0 {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	if diagnoseFn == nil { // what -verbose prints, as warnings
+		diagnoseFn = func(err error) { logger.Warn(err.Error()) }
+	}
+}
+
+// dropped logs a binding left out of the table.
+func dropped(spec, reason string, args ...any) {
+	logger.Info("binding dropped", append([]any{"spec", spec, "reason", reason}, args...)...)
+}
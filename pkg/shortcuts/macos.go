@@ -0,0 +1,195 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+/*────────────────── macOS ───────────────────
+
+The closest macOS shortcut for GNOME actions, for
+Apple-keyboard users (compare -with macos).  With
+the Apple layout Command is Super, so a GNOME chord
+equal to the Mac one is pressed the same way.
+
+export -format skhd|karabiner carries the custom
+shortcuts over to a Mac, Super becoming Command.
+The commands are copied as they are; most will
+want editing (xdg-open → open).
+*/
+
+//go:embed macos_shortcuts.tsv
+var macosShortcutsTSV string
+
+func init() {
+	systems["macos"] = system{"macOS", "macos_shortcuts.tsv", macosShortcutsTSV, kbApple}
+	exporters["skhd"] = exporter{"custom shortcuts as an skhd config (macOS)", writeSkhd}
+	exporters["karabiner"] = exporter{"custom shortcuts as a Karabiner-Elements rule (macOS)", writeKarabiner}
+}
+
+// macCustoms are the custom shortcuts among rows, with their commands.
+func macCustoms(rows []row) (out []row, cmds []string) {
+	for _, r := range rows {
+		if !isCustom(r.schema) {
+			continue
+		}
+		if cmd := customCommand(r.schema); cmd != "" {
+			out = append(out, r)
+			cmds = append(cmds, cmd)
+		}
+	}
+	return out, cmds
+}
+
+/*──────── skhd ────────*/
+
+var skhdMods = []struct {
+	bit  int
+	name string
+}{{modSuper, "cmd"}, {modCtrl, "ctrl"}, {modAlt, "alt"}, {modShift, "shift"}}
+
+var skhdKeys = map[string]string{
+	"Return": "return", "KP_Enter": "return", "Tab": "tab", "space": "space",
+	"BackSpace": "backspace", "Escape": "escape", "Delete": "delete",
+	"Home": "home", "End": "end", "Page_Up": "pageup", "Page_Down": "pagedown",
+	"Prior": "pageup", "Next": "pagedown", "Left": "left", "Right": "right",
+	"Up": "up", "Down": "down", "minus": "0x1B", "equal": "0x18",
+	"bracketleft": "0x21", "bracketright": "0x1E", "semicolon": "0x29",
+	"apostrophe": "0x27", "grave": "0x32", "comma": "0x2B", "period": "0x2F",
+	"slash": "0x2C", "backslash": "0x2A",
+}
+
+// macKey names a GTK key for a Mac tool: letters, digits, F-keys or the
+// table's entry.
+func macKey(key string, table map[string]string) (string, bool) {
+	if k, ok := table[key]; ok {
+		return k, true
+	}
+	if len(key) == 1 && (key[0] >= 'a' && key[0] <= 'z' || key[0] >= '0' && key[0] <= '9') {
+		return key, true
+	}
+	if len(key) > 1 && key[0] == 'F' && strings.Trim(key[1:], "0123456789") == "" {
+		return strings.ToLower(key), true
+	}
+	return "", false
+}
+
+// skhdCombo turns "<Super><Shift>t" into "cmd + shift - t".
+func skhdCombo(spec string) (string, bool) {
+	a, ok := parseAccel(spec)
+	if !ok || a.mods&(modHyper|modMeta) != 0 {
+		return "", false
+	}
+	key, ok := macKey(a.key, skhdKeys)
+	if !ok {
+		return "", false
+	}
+	var mods []string
+	for _, m := range skhdMods {
+		if a.mods&m.bit != 0 {
+			mods = append(mods, m.name)
+		}
+	}
+	if len(mods) == 0 {
+		return key, true
+	}
+	return strings.Join(mods, " + ") + " - " + key, true
+}
+
+func writeSkhd(w io.Writer, rows []row, _ map[string]string) error {
+	customs, cmds := macCustoms(rows)
+	fmt.Fprintln(w, "# Generated by `gnome-shortcuts export -format skhd` from the GNOME custom shortcuts.")
+	fmt.Fprintln(w, "# Super is cmd; check each command exists on macOS.")
+	var skipped []string
+	for i, r := range customs {
+		combo, ok := skhdCombo(r.spec)
+		if !ok {
+			skipped = append(skipped, exportComment(r))
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n%s : %s\n", exportComment(r), combo, cmds[i])
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "\n# No skhd key for:\n%s\n", strings.Join(skipped, "\n"))
+	}
+	return nil
+}
+
+/*──────── Karabiner-Elements ────────*/
+
+var karabinerMods = []struct {
+	bit  int
+	name string
+}{{modSuper, "command"}, {modCtrl, "control"}, {modAlt, "option"}, {modShift, "shift"}}
+
+var karabinerKeys = map[string]string{
+	"Return": "return_or_enter", "KP_Enter": "keypad_enter", "Tab": "tab",
+	"space": "spacebar", "BackSpace": "delete_or_backspace", "Escape": "escape",
+	"Delete": "delete_forward", "Home": "home", "End": "end",
+	"Page_Up": "page_up", "Page_Down": "page_down", "Prior": "page_up", "Next": "page_down",
+	"Left": "left_arrow", "Right": "right_arrow", "Up": "up_arrow", "Down": "down_arrow",
+	"minus": "hyphen", "equal": "equal_sign", "bracketleft": "open_bracket",
+	"bracketright": "close_bracket", "semicolon": "semicolon", "apostrophe": "quote",
+	"grave": "grave_accent_and_tilde", "comma": "comma", "period": "period",
+	"slash": "slash", "backslash": "backslash",
+}
+
+type karabinerFrom struct {
+	KeyCode   string             `json:"key_code"`
+	Modifiers *karabinerRequired `json:"modifiers,omitempty"`
+}
+
+type karabinerRequired struct {
+	Mandatory []string `json:"mandatory"`
+}
+
+type karabinerTo struct {
+	Shell string `json:"shell_command"`
+}
+
+type karabinerManipulator struct {
+	Type string        `json:"type"`
+	From karabinerFrom `json:"from"`
+	To   []karabinerTo `json:"to"`
+}
+
+type karabinerRule struct {
+	Description  string                 `json:"description"`
+	Manipulators []karabinerManipulator `json:"manipulators"`
+}
+
+func writeKarabiner(w io.Writer, rows []row, _ map[string]string) error {
+	customs, cmds := macCustoms(rows)
+	rules := []karabinerRule{}
+	for i, r := range customs {
+		a, ok := parseAccel(r.spec)
+		key, ok2 := macKey(a.key, karabinerKeys)
+		if !ok || !ok2 || a.mods&(modHyper|modMeta) != 0 {
+			fmt.Fprintf(os.Stderr, "gnome-shortcuts: no Karabiner key for %s (%s)\n", r.accel, r.action)
+			continue
+		}
+		m := karabinerManipulator{Type: "basic", From: karabinerFrom{KeyCode: key},
+			To: []karabinerTo{{cmds[i]}}}
+		var mods []string
+		for _, km := range karabinerMods {
+			if a.mods&km.bit != 0 {
+				mods = append(mods, km.name)
+			}
+		}
+		if len(mods) > 0 {
+			m.From.Modifiers = &karabinerRequired{mods}
+		}
+		rules = append(rules, karabinerRule{fmt.Sprintf("%s: %s (%s)", r.app, r.action, r.accel), []karabinerManipulator{m}})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(struct {
+		Title string          `json:"title"`
+		Rules []karabinerRule `json:"rules"`
+	}{"GNOME custom shortcuts", rules})
+}
@@ -0,0 +1,25 @@
+package shortcuts
+
+/*───────────────────── MATE ─────────────────────
+
+MATE's keys hold one string each ('disabled'
+when unbound):
+
+	org.mate.Marco.window-keybindings   window manager
+	org.mate.Marco.global-keybindings
+	org.mate.SettingsDaemon.plugins.media-keys
+	org.mate.control-center.keybinding  custom, one per
+	    dconf dir under /org/mate/desktop/keybindings/
+*/
+
+const (
+	marcoPrefix   = "org.mate.Marco."
+	mateMediaKeys = "org.mate.SettingsDaemon.plugins.media-keys"
+	mateCustom    = "org.mate.control-center.keybinding"
+)
+
+func init() {
+	relocatables = append(relocatables, relocatable{child: mateCustom,
+		dir: "/org/mate/desktop/keybindings/"})
+	customSchemas = append(customSchemas, mateCustom)
+}
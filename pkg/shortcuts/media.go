@@ -0,0 +1,90 @@
+package shortcuts
+
+import (
+	"strings"
+	"unicode"
+)
+
+/*──────────────── media / Fn keys ───────────────
+
+XF86 keysyms are what the Fn layer of most
+laptops sends.  They are hidden by default;
+-include-media-keys lists them (and the rest of
+the media-keys schema) under friendly names.
+*/
+
+var includeMedia bool
+
+var mediaKeys = map[string]string{
+	"XF86AudioRaiseVolume":  "Volume Up 🔊",
+	"XF86AudioLowerVolume":  "Volume Down 🔉",
+	"XF86AudioMute":         "Mute 🔇",
+	"XF86AudioMicMute":      "Mic Mute",
+	"XF86AudioPlay":         "Play ⏯",
+	"XF86AudioPause":        "Pause",
+	"XF86AudioStop":         "Stop ⏹",
+	"XF86AudioNext":         "Next Track ⏭",
+	"XF86AudioPrev":         "Previous Track ⏮",
+	"XF86AudioRewind":       "Rewind ⏪",
+	"XF86AudioForward":      "Fast Forward ⏩",
+	"XF86AudioRepeat":       "Repeat",
+	"XF86AudioRandomPlay":   "Shuffle",
+	"XF86AudioMedia":        "Media Player",
+	"XF86MonBrightnessUp":   "Brightness Up",
+	"XF86MonBrightnessDown": "Brightness Down",
+	"XF86KbdBrightnessUp":   "Keyboard Light Up",
+	"XF86KbdBrightnessDown": "Keyboard Light Down",
+	"XF86KbdLightOnOff":     "Keyboard Light",
+	"XF86Display":           "Display Switch",
+	"XF86TouchpadToggle":    "Touchpad Toggle",
+	"XF86TouchpadOn":        "Touchpad On",
+	"XF86TouchpadOff":       "Touchpad Off",
+	"XF86WLAN":              "Wi-Fi",
+	"XF86Bluetooth":         "Bluetooth",
+	"XF86RFKill":            "Airplane Mode ✈",
+	"XF86Calculator":        "Calculator",
+	"XF86Mail":              "Mail",
+	"XF86WWW":               "Browser",
+	"XF86HomePage":          "Home Page",
+	"XF86Search":            "Search",
+	"XF86Explorer":          "Files",
+	"XF86Tools":             "Settings",
+	"XF86ScreenSaver":       "Lock Screen",
+	"XF86Eject":             "Eject ⏏",
+	"XF86PowerOff":          "Power",
+	"XF86Sleep":             "Sleep",
+	"XF86Suspend":           "Suspend",
+	"XF86Hibernate":         "Hibernate",
+	"XF86Battery":           "Battery",
+	"XF86RotateWindows":     "Rotate Screen",
+}
+
+// mediaLabel names an XF86 keysym, falling back to its split name
+// (XF86AudioPreset → "Audio Preset", acronyms such as UWB stay whole).
+func mediaLabel(key string) string {
+	if l, ok := mediaKeys[key]; ok {
+		return l
+	}
+	var b strings.Builder
+	prev := rune(0)
+	for _, r := range strings.TrimPrefix(key, "XF86") {
+		if unicode.IsUpper(r) && unicode.IsLower(prev) {
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return b.String()
+}
+
+// isMediaSchema matches the media-keys schema but not its custom children.
+func isMediaSchema(schema, key string) bool {
+	return (strings.HasSuffix(schema, ".settings-daemon.plugins.media-keys") ||
+		schema == cinnamonKeys+".media-keys" || schema == mateMediaKeys) && key != "custom-keybindings"
+}
+
+// mediaAction drops the "-static" twin suffix (volume-up-static holds
+// the fixed XF86 chords next to the user-editable volume-up).
+func mediaAction(key string) string {
+	return humanise(strings.TrimSuffix(key, "-static"))
+}
@@ -0,0 +1,242 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+/*──────────────── keyboard models ────────────────
+
+KEY_LAYOUT (or config.toml's layout) may name a
+keyboard rather than a label set: thinkpad, 60 or
+surface.  Each prints PC labels but has its own
+keys: a 60% board has no F-row, arrows or
+navigation block and reaches them through Fn; a
+ThinkPad has PrtSc where the Menu key was and no
+Scroll Lock or Pause; a Surface cover has neither
+those nor Insert.  heatmap draws the model, and
+the free chords conflicts suggests keep to keys it
+has, those behind Fn last.  apple, pc and chrome
+are the full tenkeyless board.
+*/
+
+// board is a heatmap keyboard: the main block, then the navigation
+// keys beside it, six rows each.  A gap with a sym is a key no binding
+// can use (Fn), drawn unshaded.
+type board [2][6][]heatKey
+
+type kbModel struct {
+	name  string
+	kb    kb
+	board *board   // nil: heatBoard
+	fn    []string // keysyms typed with Fn held
+}
+
+var models = map[string]kbModel{
+	"thinkpad": {"ThinkPad", kbPC, &thinkpadBoard, []string{"Scroll_Lock", "Pause", "Break", "Sys_Req"}},
+	"60":       {"60%", kbPC, &board60, slices.Concat(fnNav, []string{"grave", "F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12", "Up", "Down", "Left", "Right"})},
+	"surface":  {"Surface", kbPC, &surfaceBoard, []string{"Page_Up", "Page_Down", "Insert", "Print"}},
+}
+
+// fnNav is the navigation block, behind Fn on boards without one.
+var fnNav = []string{"Print", "Scroll_Lock", "Pause", "Insert", "Home", "Page_Up", "Delete", "End", "Page_Down"}
+
+var thinkpadBoard = board{{
+	heatBoard[0][0], heatBoard[0][1], heatBoard[0][2], heatBoard[0][3], heatBoard[0][4],
+	{{w: 2, sym: "Fn"}, {"LCTL", "Control_L", 3}, {"LWIN", "Super_L", 3}, {"LALT", "Alt_L", 3}, {"SPCE", "space", 10},
+		{"RALT", "Alt_R", 3}, {"PRSC", "Print", 3}, {"RCTL", "Control_R", 3}},
+}, {
+	{{"HOME", "Home", 2}, {"END", "End", 2}, {"INS", "Insert", 2}, {"DELE", "Delete", 2}},
+	{}, {}, {},
+	{{"PGUP", "Page_Up", 2}, {"UP", "Up", 2}, {"PGDN", "Page_Down", 2}},
+	heatBoard[1][5],
+}}
+
+var board60 = board{{
+	{},
+	append([]heatKey{{"ESC", "Escape", 2}}, heatBoard[0][1][1:]...),
+	heatBoard[0][2], heatBoard[0][3], heatBoard[0][4],
+	{{"LCTL", "Control_L", 3}, {"LWIN", "Super_L", 3}, {"LALT", "Alt_L", 3}, {"SPCE", "space", 12},
+		{"RALT", "Alt_R", 3}, {w: 3, sym: "Fn"}, {"RCTL", "Control_R", 3}},
+}}
+
+var surfaceBoard = board{{
+	heatBoard[0][0], heatBoard[0][1], heatBoard[0][2], heatBoard[0][3], heatBoard[0][4],
+	{{"LCTL", "Control_L", 3}, {w: 2, sym: "Fn"}, {"LWIN", "Super_L", 3}, {"LALT", "Alt_L", 3}, {"SPCE", "space", 13},
+		{"RALT", "Alt_R", 3}, {"RCTL", "Control_R", 3}},
+}, {
+	{{"HOME", "Home", 2}, {"END", "End", 2}, {"DELE", "Delete", 2}},
+	{}, {}, {},
+	{{w: 2}, {"UP", "Up", 2}},
+	heatBoard[1][5],
+}}
+
+// keyboardModel is the model KEY_LAYOUT or config.toml names; the
+// zero model is the full board.
+func keyboardModel() kbModel {
+	for _, s := range []string{os.Getenv("KEY_LAYOUT"), userPrefs.layout} {
+		if s == "" {
+			continue
+		}
+		return models[modelKey(s)]
+	}
+	return kbModel{}
+}
+
+// modelKey is models' key for a spelling such as "ThinkPad" or "60%".
+func modelKey(s string) string { return strings.TrimSuffix(strings.ToLower(s), "%") }
+
+func (m kbModel) keys() *board {
+	b := &heatBoard
+	if m.board != nil {
+		b = m.board
+	}
+	if boardShape == "" || boardShape == "ansi" {
+		return b
+	}
+	out := *b
+	for i, r := range shapes[boardShape] {
+		if r != nil && (i < 5 || m.board == nil) && len(out[0][i]) > 0 {
+			out[0][i] = r
+		}
+	}
+	return &out
+}
+
+// has reports whether sym (a US keysym) can be typed on m, with Fn if
+// need be.  Keys the full board lacks too, such as the keypad's, are
+// not ours to rule out.
+func (m kbModel) has(sym string) bool {
+	if m.board == nil || slices.Contains(m.fn, sym) {
+		return true
+	}
+	on := func(b *board) bool {
+		for _, block := range b {
+			for _, r := range block {
+				for _, k := range r {
+					if k.code != "" && k.sym == sym {
+						return true
+					}
+				}
+			}
+		}
+		return false
+	}
+	return on(m.board) || !on(&heatBoard)
+}
+
+// behindFn reports whether sym takes Fn on m.
+func (m kbModel) behindFn(sym string) bool { return slices.Contains(m.fn, sym) }
+
+/*──────────────── ANSI, ISO, JIS ─────────────────
+
+The same keys sit on three physical boards.  ISO
+has an extra key left of Z (LSGT) and a tall Enter
+with the backslash key tucked under it; JIS adds
+yen and ro keys and the conversion keys around a
+short space bar.  -shape picks one; otherwise
+localed's keyboard model decides, and when that is
+the generic pc105 a Japanese layout means JIS, US
+means ANSI and anything else ISO.  A key drawn a
+second time is the lower half of a tall Enter.
+JIS Backspace is drawn half a key wider than it
+is, so its name fits like on the other boards.
+*/
+
+var shapeNames = []string{"ansi", "iso", "jis"}
+
+// shapes replace rows of the main block; nil keeps the row.
+var shapes = map[string][6][]heatKey{
+	"iso": {
+		nil, nil,
+		{{"TAB", "Tab", 3}, {"AD01", "q", 2}, {"AD02", "w", 2}, {"AD03", "e", 2}, {"AD04", "r", 2}, {"AD05", "t", 2},
+			{"AD06", "y", 2}, {"AD07", "u", 2}, {"AD08", "i", 2}, {"AD09", "o", 2}, {"AD10", "p", 2},
+			{"AD11", "bracketleft", 2}, {"AD12", "bracketright", 2}, {"RTRN", "Return", 3}},
+		{{"CAPS", "Caps_Lock", 4}, {"AC01", "a", 2}, {"AC02", "s", 2}, {"AC03", "d", 2}, {"AC04", "f", 2}, {"AC05", "g", 2},
+			{"AC06", "h", 2}, {"AC07", "j", 2}, {"AC08", "k", 2}, {"AC09", "l", 2}, {"AC10", "semicolon", 2},
+			{"AC11", "apostrophe", 2}, {"BKSL", "backslash", 2}, {"RTRN", "Return", 2}},
+		{{"LFSH", "Shift_L", 3}, {"LSGT", "less", 2}, {"AB01", "z", 2}, {"AB02", "x", 2}, {"AB03", "c", 2}, {"AB04", "v", 2},
+			{"AB05", "b", 2}, {"AB06", "n", 2}, {"AB07", "m", 2}, {"AB08", "comma", 2}, {"AB09", "period", 2},
+			{"AB10", "slash", 2}, {"RTSH", "Shift_R", 5}},
+		nil,
+	},
+	"jis": {
+		nil,
+		{{"TLDE", "Zenkaku_Hankaku", 2}, {"AE01", "1", 2}, {"AE02", "2", 2}, {"AE03", "3", 2}, {"AE04", "4", 2}, {"AE05", "5", 2},
+			{"AE06", "6", 2}, {"AE07", "7", 2}, {"AE08", "8", 2}, {"AE09", "9", 2}, {"AE10", "0", 2},
+			{"AE11", "minus", 2}, {"AE12", "asciicircum", 2}, {"AE13", "yen", 2}, {"BKSP", "BackSpace", 3}},
+		{{"TAB", "Tab", 3}, {"AD01", "q", 2}, {"AD02", "w", 2}, {"AD03", "e", 2}, {"AD04", "r", 2}, {"AD05", "t", 2},
+			{"AD06", "y", 2}, {"AD07", "u", 2}, {"AD08", "i", 2}, {"AD09", "o", 2}, {"AD10", "p", 2},
+			{"AD11", "at", 2}, {"AD12", "bracketleft", 2}, {"RTRN", "Return", 3}},
+		{{"CAPS", "Caps_Lock", 4}, {"AC01", "a", 2}, {"AC02", "s", 2}, {"AC03", "d", 2}, {"AC04", "f", 2}, {"AC05", "g", 2},
+			{"AC06", "h", 2}, {"AC07", "j", 2}, {"AC08", "k", 2}, {"AC09", "l", 2}, {"AC10", "semicolon", 2},
+			{"AC11", "colon", 2}, {"BKSL", "bracketright", 2}, {"RTRN", "Return", 2}},
+		{{"LFSH", "Shift_L", 4}, {"AB01", "z", 2}, {"AB02", "x", 2}, {"AB03", "c", 2}, {"AB04", "v", 2}, {"AB05", "b", 2},
+			{"AB06", "n", 2}, {"AB07", "m", 2}, {"AB08", "comma", 2}, {"AB09", "period", 2}, {"AB10", "slash", 2},
+			{"AB11", "backslash", 2}, {"RTSH", "Shift_R", 4}},
+		{{"LCTL", "Control_L", 3}, {"LWIN", "Super_L", 3}, {"LALT", "Alt_L", 2}, {"MUHE", "Muhenkan", 2}, {"SPCE", "space", 6},
+			{"HENK", "Henkan_Mode", 2}, {"HKTG", "Hiragana_Katakana", 3}, {"RALT", "Alt_R", 3}, {"MENU", "Menu", 3}, {"RCTL", "Control_R", 3}},
+	},
+}
+
+// keyboardShape is -shape, else what the keyboard model and layout
+// suggest.
+func keyboardShape(layout string) string {
+	if shapeOpt != "" {
+		return shapeOpt
+	}
+	switch xkbModel() {
+	case "jp106":
+		return "jis"
+	case "pc101", "pc104":
+		return "ansi"
+	case "pc102":
+		return "iso"
+	}
+	lay, _, _ := strings.Cut(layout, "+")
+	switch lay {
+	case "jp":
+		return "jis"
+	case "us":
+		return "ansi"
+	}
+	return "iso"
+}
+
+var (
+	shapeOpt   string // -shape
+	boardShape string // what heatmap draws
+)
+
+func shapeFlag(fs *flag.FlagSet) {
+	fs.Func("shape", "physical board: ansi, iso or jis (default: from the keyboard model and layout)", func(s string) error {
+		if !slices.Contains(shapeNames, s) {
+			return fmt.Errorf("want ansi, iso or jis")
+		}
+		shapeOpt = s
+		return nil
+	})
+}
+
+// xkbModel is the XKB keyboard model localed (or Debian's
+// /etc/default/keyboard) configures, or "".
+func xkbModel() string {
+	if b, err := os.ReadFile("/etc/X11/xorg.conf.d/00-keyboard.conf"); err == nil {
+		for _, l := range strings.Split(string(b), "\n") {
+			if f := strings.Fields(l); len(f) == 3 && f[0] == "Option" && f[1] == `"XkbModel"` {
+				return strings.Trim(f[2], `"`)
+			}
+		}
+	}
+	if b, err := os.ReadFile("/etc/default/keyboard"); err == nil {
+		for _, l := range strings.Split(string(b), "\n") {
+			if v, ok := strings.CutPrefix(strings.TrimSpace(l), "XKBMODEL="); ok {
+				return strings.Trim(v, `"'`)
+			}
+		}
+	}
+	return ""
+}
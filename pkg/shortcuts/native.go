@@ -0,0 +1,136 @@
+package shortcuts
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*──────────── native settings reads ────────────
+
+GSettings never asks a process for a value: it
+reads the dconf databases (GVDB files) and the
+compiled schemas itself.  So does this, layering
+the user database over the profile's system ones
+over the schema defaults and overrides, which
+spares a gsettings process per query.  Remote
+hosts and other GSettings backends (memory,
+keyfile) still go through the gsettings tool, as
+-backend gsettings forces; writes always do, since
+only the dconf service writes the user database.
+*/
+
+var backendOpt = "auto"
+
+// native reports whether reads skip the gsettings tool.
+func native() bool {
+	switch backendOpt {
+	case "native":
+		return true
+	case "gsettings":
+		return false
+	}
+	if remote() {
+		return false
+	}
+	if b := os.Getenv("GSETTINGS_BACKEND"); b != "" && b != "dconf" {
+		return false
+	}
+	return len(compiledSchemaIDs()) > 0
+}
+
+// dconfLookup reads path the way dconf does: the user database, then
+// the system ones in profile order, starting at the locking one when
+// path is locked (lockLevel).
+func dconfLookup(path string) (val gvariant, src string, ok bool) {
+	get := func(db string) bool {
+		t := dconfDB(db)
+		if t == nil {
+			return false
+		}
+		val, ok = t.value(path)
+		return ok
+	}
+	level := lockLevel(path)
+	if level == 0 && get(dconfUserDB()) {
+		return val, "user", true
+	}
+	dbs := dconfProfile()
+	for _, db := range dbs[min(max(level-1, 0), len(dbs)):] {
+		if get(filepath.Join(dconfEtc, "db", db)) {
+			return val, "system:" + db, true
+		}
+	}
+	return gvariant{}, "", false
+}
+
+// nativeGet is `gsettings get` without the process.
+func nativeGet(schema, key string) string {
+	if path := dconfPath(schema, key); path != "" {
+		if v, _, ok := dconfLookup(path); ok {
+			if x, err := v.decode(); err == nil {
+				return gvText(v.typ, x)
+			}
+		}
+	}
+	return lookupSchema(schema).defaults[key]
+}
+
+// nativeDump is `gsettings list-recursively`: every schema with a fixed
+// path, or only the ones named (relocatable ones as "id:/path/").
+func nativeDump(schema ...string) []byte {
+	ids := schema
+	if len(ids) == 0 {
+		for _, id := range compiledSchemaIDs() {
+			if lookupSchema(id).path != "" {
+				ids = append(ids, id)
+			}
+		}
+	}
+	var b bytes.Buffer
+	for _, id := range ids {
+		d := lookupSchema(id).defaults
+		for _, k := range sortedKeys(d) {
+			fmt.Fprintf(&b, "%s %s %s\n", id, k, nativeGet(id, k))
+		}
+	}
+	return b.Bytes()
+}
+
+// nativeList is `dconf list dir` over the user and system databases.
+func nativeList(dir string) []string {
+	seen := map[string]bool{}
+	for _, db := range append([]string{dconfUserDB()}, dconfSystemDBs()...) {
+		t := dconfDB(db)
+		if t == nil {
+			continue
+		}
+		for _, n := range t.names() {
+			rest, ok := strings.CutPrefix(n, dir)
+			if !ok || rest == "" {
+				continue
+			}
+			if i := strings.IndexByte(rest, '/'); i >= 0 {
+				rest = rest[:i+1]
+			}
+			seen[rest] = true
+		}
+	}
+	out := make([]string, 0, len(seen))
+	for n := range seen {
+		out = append(out, n)
+	}
+	sort.Strings(out)
+	return out
+}
+
+func dconfSystemDBs() []string {
+	var out []string
+	for _, db := range dconfProfile() {
+		out = append(out, filepath.Join(dconfEtc, "db", db))
+	}
+	return out
+}
@@ -0,0 +1,123 @@
+package shortcuts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+/*───────────────── notifications ─────────────────
+
+tip -notify and conflicts -notify-conflicts go to
+the desktop through notify-send (libnotify), which
+any notification daemon, GNOME Shell's included,
+shows.  -notify-conflicts is meant for a systemd
+user timer: it remembers the conflicts it saw in
+$XDG_STATE_HOME/gnome-shortcuts/conflicts.json and
+speaks up only about new ones, so an extension or
+an app update that takes a chord is noticed the
+same day.  The first run just records.
+*/
+
+// notify shows a desktop notification.
+func notify(summary, body string) error {
+	if _, err := exec.LookPath("notify-send"); err != nil {
+		return fmt.Errorf("no notify-send: install libnotify-bin (Debian, Ubuntu) or libnotify")
+	}
+	ctx, cancel := toolContext()
+	defer cancel()
+	cmd := exec.CommandContext(ctx, "notify-send", "--app-name=gnome-shortcuts", "--icon=input-keyboard", summary, body)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("notify-send: %w: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func conflictsPath() string { return filepath.Join(stateDir(), "conflicts.json") }
+
+// conflictID names one shadowing: the chord, who wins and who loses.
+func conflictID(win, lost claimant) string {
+	id := func(c claimant) string {
+		if c.Schema != "" {
+			return c.Schema + " " + c.Key
+		}
+		return c.App + ": " + c.Action
+	}
+	spec := win.Spec
+	if a, ok := parseAccel(spec); ok {
+		spec = a.spec()
+	}
+	return spec + "\t" + id(win) + "\t" + id(lost)
+}
+
+// notifyConflicts notifies about the shadowings in cs the last run
+// did not see, then remembers cs for the next.
+func notifyConflicts(cs []conflict) error {
+	var before []string
+	data, err := os.ReadFile(conflictsPath())
+	first := os.IsNotExist(err)
+	switch {
+	case first:
+	case err != nil:
+		return err
+	default:
+		if err := json.Unmarshal(data, &before); err != nil {
+			return fmt.Errorf("%s: %w", conflictsPath(), err)
+		}
+	}
+	now := []string{}
+	var fresh []string
+	for _, c := range cs {
+		for _, l := range c.Losers {
+			id := conflictID(c.Winner, l)
+			now = append(now, id)
+			if !slices.Contains(before, id) {
+				fresh = append(fresh, fmt.Sprintf("%s: %s: %s shadows %s: %s",
+					c.Shortcut, c.Winner.App, c.Winner.Action, l.App, l.Action))
+			}
+		}
+	}
+	slices.Sort(now)
+	if first || len(fresh) == 0 {
+		if err := saveConflicts(now); err != nil || !first {
+			return err
+		}
+		fmt.Printf("%d conflict(s) recorded; later runs report new ones\n", len(now))
+		return nil
+	}
+	for _, f := range fresh { // for the journal
+		fmt.Println(f)
+	}
+	body := fresh[:min(len(fresh), 5)]
+	if n := len(fresh) - len(body); n > 0 {
+		body = append(body, fmt.Sprintf("and %d more", n))
+	}
+	body = append(body, "", "gnome-shortcuts conflicts has the details.")
+	if err := notify(fmt.Sprintf("%d new shortcut conflict(s)", len(fresh)), strings.Join(body, "\n")); err != nil {
+		return err // unsaved, so the next run reports them again
+	}
+	return saveConflicts(now)
+}
+
+func saveConflicts(ids []string) error {
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false) // "<Super>h", not "\u003cSuper\u003eh"
+	if err := enc.Encode(ids); err != nil {
+		return err
+	}
+	tmp := conflictsPath() + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, conflictsPath())
+}
@@ -0,0 +1,102 @@
+package shortcuts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*──────────────── numeric keypad ────────────────
+
+Keypad keys send different keysyms depending on
+NumLock: KP_7 with it on, KP_Home with it off.  A
+binding on one of them silently stops working when
+NumLock flips, which is how several move-to-corner
+defaults confuse laptop users.
+*/
+
+// NumLock-off keysym → the NumLock-on keysym on the same key
+var kpNavigation = map[string]string{
+	"KP_End": "KP_1", "KP_Down": "KP_2", "KP_Next": "KP_3", "KP_Page_Down": "KP_3",
+	"KP_Left": "KP_4", "KP_Begin": "KP_5", "KP_Right": "KP_6",
+	"KP_Home": "KP_7", "KP_Up": "KP_8", "KP_Prior": "KP_9", "KP_Page_Up": "KP_9",
+	"KP_Insert": "KP_0", "KP_Delete": "KP_Decimal",
+}
+
+func isNumpad(key string) bool { return strings.HasPrefix(key, "KP_") }
+
+func splitNumpad(rows []row) (rest, pad []row) {
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok && isNumpad(a.key) {
+			pad = append(pad, r)
+		} else {
+			rest = append(rest, r)
+		}
+	}
+	return rest, pad
+}
+
+// numLockOn is NumLock's state now, from the keyboards' LEDs.  A dump,
+// another host or a machine without them falls back to the state
+// GNOME remembers and restores at login.
+func numLockOn() bool {
+	if !remote() && !fromDump() && !defaultsOnly {
+		leds, _ := filepath.Glob("/sys/class/leds/input*::numlock/brightness")
+		read := false
+		for _, f := range leds {
+			b, err := os.ReadFile(f)
+			if err != nil {
+				continue
+			}
+			if strings.TrimSpace(string(b)) != "0" {
+				return true
+			}
+			read = true
+		}
+		if read {
+			return false
+		}
+	}
+	return gsettingsGet("org.gnome.desktop.peripherals.keyboard", "numlock-state") == "true"
+}
+
+// warnNumLock reports keypad bindings that the current NumLock state
+// keeps from firing.
+func warnNumLock(pad []row) {
+	if len(pad) == 0 {
+		return
+	}
+	on := numLockOn()
+	var dead []string
+	for _, r := range pad {
+		a, _ := parseAccel(r.spec)
+		digit, nav := kpNavigation[a.key]
+		switch {
+		case on && nav:
+			dead = append(dead, fmt.Sprintf("  %-24s %-30s needs NumLock off (or bind %s)", r.accel, r.action, digit))
+		case !on && !nav && needsNumLock(a.key):
+			dead = append(dead, fmt.Sprintf("  %-24s %-30s needs NumLock on", r.accel, r.action))
+		}
+	}
+	if len(dead) == 0 {
+		return
+	}
+	state := "off"
+	if on {
+		state = "on"
+	}
+	fmt.Fprintf(os.Stderr, "\nwarning: NumLock is %s, so these keypad bindings will not fire:\n", state)
+	for _, d := range dead {
+		fmt.Fprintln(os.Stderr, d)
+	}
+}
+
+func needsNumLock(key string) bool {
+	for _, v := range kpNavigation {
+		if v == key {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*─────────────── schema overrides ───────────────
+
+Vendors change defaults with key files next to
+the schemas:
+
+	/usr/share/glib-2.0/schemas/10_ubuntu-settings.gschema.override
+	[org.gnome.desktop.wm.keybindings]
+	show-desktop=['<Super>d']
+	[org.gnome.shell:ubuntu]          only where XDG_CURRENT_DESKTOP
+	…                                 names ubuntu
+
+glib-compile-schemas folds the plain groups into
+gschemas.compiled but keeps the desktop ones for
+run time, and a freshly dropped file is not
+compiled at all; reading the files covers both.
+Files apply in name order, desktop groups last.
+*/
+
+type override struct {
+	schema, desktop, key, value string
+}
+
+var overrideCache = map[string][]override{}
+
+func schemaOverrides(dir string) []override {
+	if o, ok := overrideCache[dir]; ok {
+		return o
+	}
+	var out []override
+	files, _ := filepath.Glob(filepath.Join(dir, "*.gschema.override"))
+	sort.Strings(files)
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			continue
+		}
+		var schema, desktop string
+		for n, l := range strings.Split(string(data), "\n") {
+			l = strings.TrimSpace(l)
+			switch {
+			case l == "" || l[0] == '#':
+			case l[0] == '[':
+				schema, desktop, _ = strings.Cut(strings.Trim(l, "[]"), ":")
+			default:
+				k, v, ok := strings.Cut(l, "=")
+				k = strings.TrimSpace(k)
+				if strings.Contains(k, "[") { // k[locale]
+					continue
+				}
+				if !ok || schema == "" {
+					badLine(f, n, "want key=value under a [schema] line, got %q", l)
+					continue
+				}
+				out = append(out, override{schema, desktop, k, strings.TrimSpace(v)})
+			}
+		}
+	}
+	overrideCache[dir] = out
+	return out
+}
+
+// applyOverrides lays dir's overrides for schemaID over defaults.
+func applyOverrides(schemaID, dir string, defaults map[string]string) {
+	var desktops []string
+	for _, d := range strings.Split(os.Getenv("XDG_CURRENT_DESKTOP"), ":") {
+		if d != "" {
+			desktops = append(desktops, strings.ToLower(d))
+		}
+	}
+	all := schemaOverrides(dir)
+	for _, o := range all {
+		if o.schema == schemaID && o.desktop == "" {
+			defaults[o.key] = o.value
+		}
+	}
+	// first desktop in the list wins, so apply in reverse
+	for i := len(desktops) - 1; i >= 0; i-- {
+		for _, o := range all {
+			if o.schema == schemaID && strings.ToLower(o.desktop) == desktops[i] {
+				defaults[o.key] = o.value
+			}
+		}
+	}
+}
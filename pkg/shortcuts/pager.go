@@ -0,0 +1,129 @@
+package shortcuts
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/chzyer/readline"
+)
+
+/*──────────────────── paging ────────────────────
+
+Like git, list sends a table taller than the
+terminal through $PAGER when stdout is one: less
+by default, with LESS=FRX unless LESS is set, so
+colour passes and a short table just prints.  With
+no pager to run it shows a screen at a time itself.
+-no-pager, PAGER=cat or an empty PAGER print it
+straight, as does any pipe or file.
+*/
+
+var noPager bool
+
+func pagerFlag(fs *flag.FlagSet) {
+	fs.BoolVar(&noPager, "no-pager", false, "never page, even when the table is taller than the terminal")
+}
+
+// paged writes what out prints to stdout, through a pager when it
+// would not fit on the terminal.
+func paged(out func(w io.Writer) error) error {
+	if noPager || !readline.IsTerminal(int(os.Stdout.Fd())) {
+		return out(os.Stdout)
+	}
+	var buf bytes.Buffer
+	err := out(&buf)
+	_, h := termSize()
+	if bytes.Count(buf.Bytes(), []byte("\n")) < h {
+		os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	pager, set := os.LookupEnv("PAGER")
+	if !set {
+		pager = "less"
+		if _, lerr := exec.LookPath(pager); lerr != nil {
+			return errors.Join(err, pageSelf(buf.Bytes(), h))
+		}
+	}
+	if pager == "" || pager == "cat" {
+		os.Stdout.Write(buf.Bytes())
+		return err
+	}
+	return errors.Join(err, runPager(pager, buf.Bytes(), h))
+}
+
+// runPager feeds text to the pager command line, falling back to
+// pageSelf when the shell cannot find it.
+func runPager(pager string, text []byte, h int) error {
+	cmd := exec.Command("sh", "-c", pager)
+	cmd.Stdin = bytes.NewReader(text)
+	cmd.Stdout, cmd.Stderr = os.Stdout, os.Stderr
+	cmd.Env = os.Environ()
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	// Ctrl-C belongs to the pager while it runs.
+	signal.Ignore(os.Interrupt)
+	defer signal.Reset(os.Interrupt)
+	err := cmd.Run()
+	var ee *exec.ExitError
+	switch {
+	case errors.As(err, &ee) && ee.ExitCode() == 127: // not found
+		return pageSelf(text, h)
+	case err != nil:
+		return fmt.Errorf("pager %q: %w", pager, err)
+	}
+	return nil
+}
+
+// pageSelf shows text a screen at a time: Space or PgDn for the next
+// screen, Enter or ↓ for one more line, q to stop.
+func pageSelf(text []byte, h int) error {
+	in := int(os.Stdin.Fd())
+	if !readline.IsTerminal(in) {
+		_, err := os.Stdout.Write(text)
+		return err
+	}
+	lines := bytes.SplitAfter(text, []byte("\n"))
+	if len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	st, err := readline.MakeRaw(in)
+	if err != nil {
+		return err
+	}
+	defer readline.Restore(in, st)
+
+	shown, more := 0, h-1
+	buf := make([]byte, 64)
+	for {
+		for ; more > 0 && shown < len(lines); more-- {
+			os.Stdout.Write(bytes.ReplaceAll(lines[shown], []byte("\n"), []byte("\r\n")))
+			shown++
+		}
+		if shown == len(lines) {
+			return nil
+		}
+		fmt.Printf("\x1b[7m-- more (%d%%) --\x1b[m", shown*100/len(lines))
+		n, err := os.Stdin.Read(buf)
+		fmt.Print("\r\x1b[K")
+		if err != nil {
+			return nil
+		}
+		for _, k := range keysIn(buf[:n]) {
+			switch k {
+			case " ", "pgdn":
+				more += h - 1
+			case "enter", "down":
+				more++
+			case "q", "esc", "ctrl-c":
+				return nil
+			}
+		}
+	}
+}
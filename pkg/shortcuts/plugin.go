@@ -0,0 +1,93 @@
+package shortcuts
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+/*──────────────────── plugins ────────────────────
+
+Every executable in $XDG_CONFIG_HOME/gnome-shortcuts/
+plugins is one more collector: it runs with no
+arguments and prints a JSON array of bindings,
+
+	[{"spec": "<Super>e", "action": "Expand snippet",
+	  "app": "Espanso", "rank": 3}]
+
+with Binding's field names.  "app" defaults to the
+plugin's name, "rank" to 3 (custom shortcuts),
+and "shortcut" may replace "spec" for a chord GTK
+cannot spell.  A plugin that fails or prints
+anything else is reported and left out.  Like
+xbindkeys they describe this machine, so -host,
+-defaults and -from-dump skip them.
+*/
+
+const pluginTimeout = 5 * time.Second
+
+type plugin struct{ path string }
+
+func (p plugin) Name() string { return "plugin " + filepath.Base(p.path) }
+
+func (p plugin) Collect(ctx context.Context) ([]Binding, error) {
+	ctx, cancel := context.WithTimeout(ctx, pluginTimeout)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, p.path)
+	cmd.WaitDelay = 100 * time.Millisecond
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	var raw []struct {
+		Binding
+		Rank *int `json:"rank"`
+	}
+	if err := json.Unmarshal(out, &raw); err != nil {
+		return nil, &ParseError{File: p.path + " output", Err: err}
+	}
+	app := humanise(strings.TrimSuffix(filepath.Base(p.path), filepath.Ext(p.path)))
+	bs := make([]Binding, 0, len(raw))
+	for i, r := range raw {
+		b := r.Binding
+		if b.Spec == "" && b.Accel == "" || b.Action == "" {
+			return nil, &ParseError{File: p.path + " output", Err: fmt.Errorf("binding %d: want spec (or shortcut) and action", i+1)}
+		}
+		b.Rank = 3
+		if r.Rank != nil {
+			b.Rank = *r.Rank
+		}
+		if b.App == "" {
+			b.App = app
+		}
+		bs = append(bs, b)
+	}
+	return bs, nil
+}
+
+// plugins lists the executables in the plugins dir, by name.
+func plugins() []Collector {
+	if remote() || defaultsOnly || fromDump() {
+		return nil
+	}
+	dir := filepath.Join(configDir(), "plugins")
+	ents, _ := os.ReadDir(dir) // sorted by name
+	var out []Collector
+	for _, e := range ents {
+		path := filepath.Join(dir, e.Name())
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() && info.Mode()&0o111 != 0 {
+			out = append(out, plugin{path})
+		}
+	}
+	return out
+}
@@ -0,0 +1,432 @@
+package shortcuts
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*──────────────── user preferences ────────────────
+
+$XDG_CONFIG_HOME/gnome-shortcuts/config.toml holds
+what would otherwise be typed on every run:
+
+	layout    = "apple"          # when KEY_LAYOUT is unset
+	format    = "md"             # list -format
+	exclude   = ["Media Keys"]   # Application column values to hide
+	favorites = ["<Super>t"]     # chords list marks with ★
+
+	[labels]                     # how keys and modifiers print
+	"<Super>" = "Cmd"
+	Return    = "↵"
+
+	[theme]                      # colours: theme.go
+	base = "light"
+
+Flags and KEY_LAYOUT still win.  Only as much TOML
+as this needs is read: tables, comments, and
+string, boolean, integer and array values.
+*/
+
+type prefs struct {
+	layout, format     string
+	keys, modOrder     string
+	separator          *string // "" is a separator too
+	exclude, favorites []string
+	labels             map[string]string
+	theme              themePrefs
+}
+
+var userPrefs prefs
+
+// loadPrefs reads config.toml into userPrefs; a missing file is no
+// error, an unreadable one is.
+func loadPrefs() error {
+	file := filepath.Join(configDir(), "config.toml")
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	doc, err := parseTOML(file, string(data))
+	if err != nil {
+		return err
+	}
+	var p prefs
+	for table, kv := range doc {
+		for k, v := range kv {
+			if err := p.set(table, k, v); err != nil {
+				return &ParseError{File: file, Err: err}
+			}
+		}
+	}
+	userPrefs = p
+	return nil
+}
+
+func (p *prefs) set(table, key string, v any) error {
+	name := key
+	if table != "" {
+		name = table + "." + key
+	}
+	want := func(typ string) error { return fmt.Errorf("%s: want %s, got %v", name, typ, v) }
+	switch {
+	case table == "labels":
+		s, ok := v.(string)
+		if !ok {
+			return want("a string")
+		}
+		if p.labels == nil {
+			p.labels = map[string]string{}
+		}
+		p.labels[key] = s
+	case table == "theme":
+		return p.theme.set(key, v)
+	case table != "":
+		return fmt.Errorf("unknown table [%s]", table)
+	case key == "layout", key == "format":
+		s, ok := v.(string)
+		if !ok {
+			return want("a string")
+		}
+		if key == "layout" {
+			if _, ok := layoutNamed(s); !ok {
+				return fmt.Errorf("layout: want apple, pc, chrome, thinkpad, 60 or surface, got %q", s)
+			}
+			p.layout = s
+		} else {
+			p.format = s
+		}
+	case key == "keys":
+		s, ok := v.(string)
+		if !ok || !slices.Contains(keyStyles, s) {
+			return want(strings.Join(keyStyles, ", "))
+		}
+		p.keys = s
+	case key == "separator":
+		s, ok := v.(string)
+		if !ok {
+			return want("a string")
+		}
+		p.separator = &s
+	case key == "mod-order":
+		s, ok := v.(string)
+		if !ok || !slices.Contains(modOrders, s) {
+			return want(strings.Join(modOrders, " or "))
+		}
+		p.modOrder = s
+	case key == "exclude", key == "favorites":
+		items, ok := v.([]any)
+		var ss []string
+		for _, it := range items {
+			s, isStr := it.(string)
+			ok = ok && isStr
+			ss = append(ss, s)
+		}
+		if !ok {
+			return want("an array of strings")
+		}
+		if key == "exclude" {
+			p.exclude = ss
+		} else {
+			p.favorites = ss
+		}
+	default:
+		return fmt.Errorf("unknown setting %q", key)
+	}
+	return nil
+}
+
+// excluded reports whether r's family is hidden by config.toml.
+func excluded(r row) bool {
+	return slices.ContainsFunc(userPrefs.exclude, func(f string) bool { return strings.EqualFold(f, r.app) })
+}
+
+// favorite reports whether spec is one of config.toml's favorites,
+// however either is spelt.
+func favorite(spec string) bool {
+	a, ok := parseAccel(spec)
+	if !ok {
+		return false
+	}
+	for _, f := range userPrefs.favorites {
+		if b, ok := parseAccel(f); ok && b.spec() == a.spec() {
+			return true
+		}
+	}
+	return false
+}
+
+/*────────────────── labels.toml ─────────────────
+
+Labels alone, in a file of their own to share or
+keep in a dotfiles repo:
+
+	"<Super>" = "❖"
+	Return    = "Enter"
+
+	[apple]                 # only on that keyboard
+	"<Alt>" = "Opt"
+
+They go over the built-in names, and config.toml's
+[labels] over them.
+*/
+
+var labelFile map[string]map[string]string // "" or kbKeys → token → label
+
+// kbKeys name labels.toml's tables.
+var kbKeys = [...]string{kbApple: "apple", kbPC: "pc", kbChrome: "chrome"}
+
+// loadLabelFile reads labels.toml into labelFile; a missing file is
+// no error.
+func loadLabelFile() error {
+	file := filepath.Join(configDir(), "labels.toml")
+	data, err := os.ReadFile(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	doc, err := parseTOML(file, string(data))
+	if err != nil {
+		return err
+	}
+	out := map[string]map[string]string{}
+	for table, kv := range doc {
+		if table != "" && !slices.Contains(kbKeys[:], table) {
+			return &ParseError{File: file, Err: fmt.Errorf("unknown table [%s]: want [apple], [pc] or [chrome]", table)}
+		}
+		out[table] = map[string]string{}
+		for k, v := range kv {
+			s, ok := v.(string)
+			if !ok {
+				return &ParseError{File: file, Err: fmt.Errorf("%s: want a string, got %v", k, v)}
+			}
+			out[table][k] = s
+		}
+	}
+	labelFile = out
+	return nil
+}
+
+/*──────── the TOML subset ────────*/
+
+type tomlParser struct {
+	file, s string
+	i, line int
+}
+
+// parseTOML maps table → key → value; the root table is "".  Values
+// are string, bool, int64 or []any.
+func parseTOML(file, data string) (map[string]map[string]any, error) {
+	p := &tomlParser{file: file, s: data, line: 1}
+	doc := map[string]map[string]any{"": {}}
+	table := ""
+	for {
+		p.space(true)
+		if p.i >= len(p.s) {
+			return doc, nil
+		}
+		if p.s[p.i] == '[' {
+			p.i++
+			p.space(false)
+			name, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			p.space(false)
+			if !p.eat(']') {
+				return nil, p.errorf("want ] after [%s", name)
+			}
+			if _, dup := doc[name]; dup {
+				return nil, p.errorf("table [%s] defined twice", name)
+			}
+			table, doc[name] = name, map[string]any{}
+		} else {
+			k, err := p.key()
+			if err != nil {
+				return nil, err
+			}
+			p.space(false)
+			if !p.eat('=') {
+				return nil, p.errorf("want = after %s", k)
+			}
+			p.space(false)
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			if _, dup := doc[table][k]; dup {
+				return nil, p.errorf("%s set twice", k)
+			}
+			doc[table][k] = v
+		}
+		p.space(false)
+		if p.i < len(p.s) && p.s[p.i] != '\n' {
+			return nil, p.errorf("want end of line, got %q", p.rest())
+		}
+	}
+}
+
+func (p *tomlParser) errorf(format string, args ...any) error {
+	return &ParseError{p.file, p.line, fmt.Errorf(format, args...)}
+}
+
+func (p *tomlParser) rest() string {
+	r, _, _ := strings.Cut(p.s[p.i:], "\n")
+	return r
+}
+
+func (p *tomlParser) eat(c byte) bool {
+	if p.i < len(p.s) && p.s[p.i] == c {
+		p.i++
+		return true
+	}
+	return false
+}
+
+// space skips blanks and comments, and newlines when nl is set.
+func (p *tomlParser) space(nl bool) {
+	for p.i < len(p.s) {
+		switch c := p.s[p.i]; {
+		case c == ' ', c == '\t', c == '\r':
+			p.i++
+		case c == '#':
+			for p.i < len(p.s) && p.s[p.i] != '\n' {
+				p.i++
+			}
+		case c == '\n' && nl:
+			p.i++
+			p.line++
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) key() (string, error) {
+	if p.i < len(p.s) && (p.s[p.i] == '"' || p.s[p.i] == '\'') {
+		return p.str()
+	}
+	j := p.i
+	for j < len(p.s) && (isAlnum(p.s[j]) || p.s[j] == '_' || p.s[j] == '-' || p.s[j] == '.') {
+		j++
+	}
+	if j == p.i {
+		return "", p.errorf("want a key, got %q", p.rest())
+	}
+	k := p.s[p.i:j]
+	p.i = j
+	return k, nil
+}
+
+func isAlnum(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
+
+func (p *tomlParser) value() (any, error) {
+	if p.i >= len(p.s) {
+		return nil, p.errorf("want a value")
+	}
+	switch c := p.s[p.i]; {
+	case c == '"' || c == '\'':
+		return p.str()
+	case c == '[':
+		p.i++
+		items := []any{}
+		for {
+			p.space(true)
+			if p.eat(']') {
+				return items, nil
+			}
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, v)
+			p.space(true)
+			if !p.eat(',') {
+				p.space(true)
+				if !p.eat(']') {
+					return nil, p.errorf("want , or ] in array")
+				}
+				return items, nil
+			}
+		}
+	}
+	j := p.i
+	for j < len(p.s) && (isAlnum(p.s[j]) || strings.IndexByte("+-_", p.s[j]) >= 0) {
+		j++
+	}
+	word := p.s[p.i:j]
+	p.i = j
+	switch word {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	n, err := strconv.ParseInt(strings.ReplaceAll(word, "_", ""), 10, 64)
+	if err != nil {
+		return nil, p.errorf("want a string, number, boolean or array, got %q", word+p.rest())
+	}
+	return n, nil
+}
+
+// str reads a "basic" (escaped) or 'literal' string.
+func (p *tomlParser) str() (string, error) {
+	q := p.s[p.i]
+	p.i++
+	var b strings.Builder
+	for p.i < len(p.s) {
+		c := p.s[p.i]
+		p.i++
+		switch {
+		case c == q:
+			return b.String(), nil
+		case c == '\n':
+			return "", p.errorf("unterminated string")
+		case c == '\\' && q == '"':
+			if p.i >= len(p.s) {
+				return "", p.errorf("unterminated string")
+			}
+			e := p.s[p.i]
+			p.i++
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\':
+				b.WriteByte(e)
+			case 'u', 'U':
+				n := 4
+				if e == 'U' {
+					n = 8
+				}
+				if p.i+n > len(p.s) {
+					return "", p.errorf("short \\%c escape", e)
+				}
+				r, err := strconv.ParseUint(p.s[p.i:p.i+n], 16, 32)
+				if err != nil {
+					return "", p.errorf("bad \\%c escape", e)
+				}
+				b.WriteRune(rune(r))
+				p.i += n
+			default:
+				return "", p.errorf("unknown escape \\%c", e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", p.errorf("unterminated string")
+}
@@ -0,0 +1,117 @@
+package shortcuts
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	doc, err := parseTOML("config.toml", `# comment
+layout  = "apple"   # trailing comment
+format  = 'md'
+count   = 1_000
+neg     = -3
+on      = true
+off     = false
+exclude = [
+  "Media Keys",   # per-item comment
+  'Files',
+]
+empty   = []
+esc     = "a\"b\\c\n\u00e9"
+literal = 'C:\path'
+
+[labels]
+"<Super>" = "❖"
+Return    = "↵"
+dotted.key = "x"
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]map[string]any{
+		"": {
+			"layout": "apple", "format": "md", "count": int64(1000), "neg": int64(-3),
+			"on": true, "off": false,
+			"exclude": []any{"Media Keys", "Files"}, "empty": []any{},
+			"esc": "a\"b\\c\né", "literal": `C:\path`,
+		},
+		"labels": {"<Super>": "❖", "Return": "↵", "dotted.key": "x"},
+	}
+	if !reflect.DeepEqual(doc, want) {
+		t.Errorf("parseTOML =\n%#v\nwant\n%#v", doc, want)
+	}
+}
+
+func TestParseTOMLMalformed(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		line int
+	}{
+		{"layout", 1},
+		{"layout = ", 1},
+		{"\n\nlayout = \"open", 3},
+		{"a = \"x\"\na = \"y\"", 2},
+		{"[t]\n[t]", 2},
+		{"[t", 1},
+		{"a = [1, 2", 1},
+		{"a = [1 2]", 1},
+		{"a = 1 b", 1},
+		{"a = maybe", 1},
+		{`a = "\q"`, 1},
+		{`a = "\u12"`, 1},
+		{"= 1", 1},
+	} {
+		_, err := parseTOML("config.toml", c.in)
+		var pe *ParseError
+		if !errors.As(err, &pe) || pe.File != "config.toml" || pe.Line != c.line {
+			t.Errorf("parseTOML(%q): err = %v, want a *ParseError on line %d", c.in, err, c.line)
+		}
+	}
+}
+
+func TestLoadPrefs(t *testing.T) {
+	saved := userPrefs
+	t.Cleanup(func() { userPrefs = saved })
+	home := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", home)
+	dir := filepath.Join(home, "gnome-shortcuts")
+	file := filepath.Join(dir, "config.toml")
+
+	if err := loadPrefs(); err != nil {
+		t.Fatalf("missing config.toml: %v", err)
+	}
+
+	if err := os.MkdirAll(file, 0o755); err != nil { // a dir cannot be read as a file
+		t.Fatal(err)
+	}
+	if err := loadPrefs(); err == nil {
+		t.Error("unreadable config.toml: no error")
+	}
+	if err := os.Remove(file); err != nil {
+		t.Fatal(err)
+	}
+
+	body := "layout = \"apple\"\nfavorites = [\"<Super>t\"]\nseparator = \"\"\n\n[labels]\nReturn = \"Enter\"\n"
+	if err := os.WriteFile(file, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := loadPrefs(); err != nil {
+		t.Fatal(err)
+	}
+	if userPrefs.layout != "apple" || !reflect.DeepEqual(userPrefs.favorites, []string{"<Super>t"}) ||
+		userPrefs.separator == nil || *userPrefs.separator != "" || userPrefs.labels["Return"] != "Enter" {
+		t.Errorf("userPrefs = %+v", userPrefs)
+	}
+
+	if err := os.WriteFile(file, []byte("layout = \"qwerty-ish\"\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var pe *ParseError
+	if err := loadPrefs(); !errors.As(err, &pe) {
+		t.Errorf("bad layout: err = %v, want a *ParseError", err)
+	}
+}
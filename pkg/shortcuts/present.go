@@ -0,0 +1,122 @@
+package shortcuts
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+)
+
+/*──────────────── presentation mode ─────────────
+
+One category per screen, cycling on a timer, for
+trainers demoing shortcuts to a class.  Titles use
+DEC double-height lines and rows double-width lines,
+which VTE (GNOME Terminal, Console) and xterm render
+as large type.
+*/
+
+var presentOpt struct {
+	interval time.Duration
+	once     bool
+}
+
+func init() {
+	commands["present"] = command{
+		help: "cycle through categories full-screen, one per screen",
+		flags: func(fs *flag.FlagSet) {
+			fs.DurationVar(&presentOpt.interval, "interval", 10*time.Second, "time per screen")
+			fs.BoolVar(&presentOpt.once, "once", false, "stop after the last category")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runPresent,
+	}
+}
+
+const (
+	escAltScreen  = "\x1b[?1049h\x1b[?25l"
+	escMainScreen = "\x1b[?25h\x1b[?1049l"
+	escClear      = "\x1b[H\x1b[2J"
+	escMouseOn    = "\x1b[?1000h\x1b[?1006h" // clicks and the wheel, reported as SGR
+	escMouseOff   = "\x1b[?1006l\x1b[?1000l"
+	escDblTop     = "\x1b#3"
+	escDblBottom  = "\x1b#4"
+	escDblWidth   = "\x1b#6"
+)
+
+type category struct {
+	name string
+	rows []row
+}
+
+// categories groups sorted rows by application, keeping priority order.
+func categories(rows []row) []category {
+	var out []category
+	idx := map[string]int{}
+	for _, r := range rows {
+		i, ok := idx[r.app]
+		if !ok {
+			i = len(out)
+			idx[r.app] = i
+			out = append(out, category{name: r.app})
+		}
+		out[i].rows = append(out[i].rows, r)
+	}
+	return out
+}
+
+func runPresent([]string) error {
+	rows, _, err := collect(labels("text"))
+	if err != nil {
+		return err
+	}
+	sortRows(rows)
+	cats := categories(rows)
+	if len(cats) == 0 {
+		return fmt.Errorf("no shortcuts found")
+	}
+
+	fmt.Print(escAltScreen)
+	defer fmt.Print(escMainScreen)
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	_, height := termSize()
+	for {
+		for i, c := range cats {
+			drawSlide(c, i+1, len(cats), height)
+			select {
+			case <-stop:
+				return nil
+			case <-time.After(presentOpt.interval):
+			}
+		}
+		if presentOpt.once {
+			return nil
+		}
+	}
+}
+
+func drawSlide(c category, n, total, height int) {
+	var b strings.Builder
+	b.WriteString(escClear)
+	title := fmt.Sprintf(" %s ", c.name)
+	b.WriteString(escDblTop + title + "\r\n")
+	b.WriteString(escDblBottom + title + "\r\n\r\n")
+
+	// title takes 3 lines, footer 2; anything beyond is cut off
+	room := height - 5
+	for i, r := range c.rows {
+		if i == room {
+			fmt.Fprintf(&b, escDblWidth+" … %d more\r\n", len(c.rows)-room)
+			break
+		}
+		fmt.Fprintf(&b, escDblWidth+" %-18s %s\r\n", r.accel, r.action)
+	}
+	fmt.Fprintf(&b, "\x1b[%d;1H %d/%d · next in %s · Ctrl-C ends", height, n, total, presentOpt.interval)
+	fmt.Print(b.String())
+}
@@ -0,0 +1,225 @@
+package shortcuts
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+/*────────────── learning progress ───────────────
+
+Per-shortcut training state, keyed by "schema key"
+so it survives layout changes and re-binding.  The
+file is plain JSON and can be moved between machines
+with `progress export` / `progress import`.
+*/
+
+type progressEntry struct {
+	Seen    int       `json:"seen"`
+	Correct int       `json:"correct"`
+	Learned bool      `json:"learned,omitempty"`
+	Last    time.Time `json:"last"`
+}
+
+type progress struct {
+	Version int                      `json:"version"`
+	Entries map[string]progressEntry `json:"entries"`
+}
+
+func stateDir() string {
+	if d := os.Getenv("XDG_STATE_HOME"); d != "" {
+		return filepath.Join(d, "gnome-shortcuts")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "gnome-shortcuts")
+}
+
+func progressPath() string { return filepath.Join(stateDir(), "progress.json") }
+
+func loadProgress() (*progress, error) {
+	p := &progress{Version: 1, Entries: map[string]progressEntry{}}
+	f, err := os.Open(progressPath())
+	if os.IsNotExist(err) {
+		return p, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	_, err = readProgress(f, p)
+	return p, err
+}
+
+// readProgress decodes a progress file into p, moving entries of keys
+// GNOME has renamed since to their current names.
+func readProgress(r io.Reader, p *progress) (report []string, err error) {
+	if err := json.NewDecoder(r).Decode(p); err != nil {
+		return nil, fmt.Errorf("progress file: %w", err)
+	}
+	if p.Entries == nil {
+		p.Entries = map[string]progressEntry{}
+	}
+	return p.translate(), nil
+}
+
+func (p *progress) save() error {
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	tmp := progressPath() + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := p.write(f); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, progressPath())
+}
+
+func (p *progress) write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// merge keeps the more recently practised entry per shortcut; once a
+// shortcut is learned on any machine it stays learned.
+func (p *progress) merge(o *progress) (added, updated int) {
+	for k, e := range o.Entries {
+		cur, ok := p.Entries[k]
+		switch {
+		case !ok:
+			added++
+		case e.Last.After(cur.Last):
+			updated++
+			e.Learned = e.Learned || cur.Learned
+		default:
+			cur.Learned = cur.Learned || e.Learned
+			e = cur
+		}
+		p.Entries[k] = e
+	}
+	return added, updated
+}
+
+// translate moves entries recorded under renamed keys to their current
+// names; entries of removed keys are kept as they are.
+func (p *progress) translate() (report []string) {
+	out := &progress{Version: p.Version, Entries: map[string]progressEntry{}}
+	for _, k := range sortedKeys(p.Entries) {
+		nk, since, ok := translateKey(k)
+		if ok {
+			report = append(report, describeRename(k, nk, since))
+		}
+		if nk == "" {
+			nk = k
+		}
+		out.merge(&progress{Entries: map[string]progressEntry{nk: p.Entries[k]}})
+	}
+	*p = *out
+	return report
+}
+
+/*─────────────── progress command ───────────────*/
+
+func init() {
+	commands["progress"] = command{
+		help: "show, export (FILE|-) or import FILE training progress",
+		run:  runProgress,
+	}
+}
+
+func runProgress(args []string) error {
+	p, err := loadProgress()
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return printProgress(p)
+	}
+	switch args[0] {
+	case "export":
+		if len(args) < 2 || args[1] == "-" {
+			return p.write(os.Stdout)
+		}
+		f, err := os.Create(args[1])
+		if err != nil {
+			return err
+		}
+		if err := p.write(f); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	case "import":
+		if len(args) < 2 {
+			return fmt.Errorf("progress import: missing FILE")
+		}
+		data, err := os.ReadFile(args[1])
+		if err != nil {
+			return err
+		}
+		return importProgress(data)
+	}
+	return fmt.Errorf("progress: unknown action %q (want export or import)", args[0])
+}
+
+// importProgress merges an exported progress file into ours,
+// translating keys GNOME has renamed since.
+func importProgress(data []byte) error {
+	p, err := loadProgress()
+	if err != nil {
+		return err
+	}
+	in := &progress{}
+	report, err := readProgress(bytes.NewReader(data), in)
+	if err != nil {
+		return err
+	}
+	added, updated := p.merge(in)
+	if err := p.save(); err != nil {
+		return err
+	}
+	fmt.Printf("imported %d new, %d updated progress entries\n", added, updated)
+	if len(report) > 0 {
+		fmt.Printf("%d renamed or removed key(s):\n%s\n", len(report), strings.Join(report, "\n"))
+	}
+	return nil
+}
+
+func printProgress(p *progress) error {
+	if len(p.Entries) == 0 {
+		fmt.Println("No training progress recorded yet.")
+		return nil
+	}
+	keys := make([]string, 0, len(p.Entries))
+	learned := 0
+	for k, e := range p.Entries {
+		keys = append(keys, k)
+		if e.Learned {
+			learned++
+		}
+	}
+	sort.Strings(keys)
+	fmt.Printf("%d shortcuts practised, %d learned\n\n", len(keys), learned)
+	for _, k := range keys {
+		e := p.Entries[k]
+		mark := " "
+		if e.Learned {
+			mark = "✓"
+		}
+		fmt.Printf("%s %-60s %3d/%-3d %s\n", mark, k, e.Correct, e.Seen, e.Last.Format("2006-01-02"))
+	}
+	return nil
+}
@@ -0,0 +1,48 @@
+package shortcuts
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestReadProgressTranslates(t *testing.T) {
+	const old = `{"version": 1, "entries": {
+  "org.gnome.settings-daemon.plugins.media-keys screenshot": {"seen": 2, "correct": 1, "last": "2024-01-02T00:00:00Z"},
+  "org.gnome.shell.keybindings screenshot": {"seen": 5, "correct": 5, "learned": true, "last": "2023-01-01T00:00:00Z"},
+  "org.gnome.shell.keybindings open-application-menu": {"seen": 1, "correct": 0, "last": "2022-01-01T00:00:00Z"},
+  "org.gnome.desktop.wm.keybindings close": {"seen": 3, "correct": 3, "last": "2024-01-01T00:00:00Z"}
+}}`
+	p := &progress{}
+	report, err := readProgress(strings.NewReader(old), p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"org.gnome.desktop.wm.keybindings close",
+		"org.gnome.shell.keybindings open-application-menu",
+		"org.gnome.shell.keybindings screenshot",
+	}
+	if got := sortedKeys(p.Entries); !reflect.DeepEqual(got, want) {
+		t.Errorf("keys = %q, want %q", got, want)
+	}
+	// the newer entry wins, but learned stays learned
+	if e := p.Entries["org.gnome.shell.keybindings screenshot"]; e.Seen != 2 || !e.Learned {
+		t.Errorf("merged screenshot entry = %+v", e)
+	}
+	if len(report) != 2 {
+		t.Errorf("report = %q, want a rename and a removal", report)
+	}
+}
+
+func TestCurrentKey(t *testing.T) {
+	for in, want := range map[string]string{
+		"org.gnome.settings-daemon.plugins.media-keys area-screenshot": "org.gnome.shell.keybindings show-screenshot-ui",
+		"org.gnome.shell.keybindings open-application-menu":            "org.gnome.shell.keybindings open-application-menu",
+		"org.gnome.desktop.wm.keybindings close":                       "org.gnome.desktop.wm.keybindings close",
+	} {
+		if got := currentKey(in); got != want {
+			t.Errorf("currentKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,292 @@
+package shortcuts
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+/*────────────────────── quiz ──────────────────────
+
+Names an action and asks for its shortcut, typed
+the way the table prints it ("Super + 3"), the way
+GTK spells it ("<Super>3") or loosely ("ctrl alt
+t"); -press reads the chord off the keyboard as
+identify does, with the keyboards grabbed so it
+does not fire.  Answers go into the training
+progress (progress.go), and the shortcuts answered
+worst come first.
+*/
+
+var quizOpt struct {
+	n     int
+	press bool
+}
+
+// quizLearned right answers, with three in four right, mark a shortcut
+// learned; a wrong answer takes that back.
+const quizLearned = 3
+
+func init() {
+	commands["quiz"] = command{
+		help: "name the shortcut for an action; keeps score in the training progress",
+		flags: func(fs *flag.FlagSet) {
+			fs.IntVar(&quizOpt.n, "n", 10, "questions to ask")
+			fs.BoolVar(&quizOpt.press, "press", false, "press the chord instead of typing it (reads /dev/input)")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runQuiz,
+	}
+}
+
+// question is one action and every chord bound to it.
+type question struct {
+	id     string // progress key, "schema key" where there is one
+	prompt string
+	rows   []row
+}
+
+// progressID names r's action in the progress file.
+func progressID(r row) string {
+	if r.schema != "" {
+		return r.schema + " " + r.key
+	}
+	return r.app + ": " + r.action
+}
+
+func (q question) answers() string {
+	var out []string
+	for _, r := range q.rows {
+		if !slices.Contains(out, r.accel) {
+			out = append(out, r.accel)
+		}
+	}
+	return strings.Join(out, " or ")
+}
+
+// quizMods are modifier names people type that GTK does not know.
+var quizMods = map[string]int{
+	"win": modSuper, "windows": modSuper, "cmd": modSuper, "command": modSuper,
+	"opt": modAlt, "option": modAlt, "⌘": modSuper, "⌥": modAlt, "⇧": modShift, "⌃": modCtrl,
+}
+
+// typedAccel reads an answer: GTK's spelling, or modifiers then a key
+// name separated by "+" or spaces.
+func typedAccel(s string, lbl map[string]string) (mods int, key string) {
+	s = strings.TrimSpace(s)
+	if strings.HasPrefix(s, "<") {
+		a, _ := parseAccel(s)
+		return a.mods, a.key
+	}
+	plus := strings.HasSuffix(s, "++") // the + key itself
+	sep := func(r rune) bool { return r == '+' || unicode.IsSpace(r) }
+	for {
+		s = strings.TrimLeftFunc(s, sep)
+		word, rest := s, ""
+		if i := strings.IndexFunc(s, sep); i >= 0 {
+			word, rest = s[:i], s[i:]
+		}
+		bit, ok := 0, false
+		for i, t := range modTokens { // labels such as "Win (Caps)"
+			l := lbl[t]
+			if ok || l == "" || len(s) < len(l) || !strings.EqualFold(s[:len(l)], l) {
+				continue
+			}
+			if after := s[len(l):]; after == "" || strings.IndexFunc(after, sep) == 0 {
+				bit, ok, word, rest = 1<<i, true, l, after
+			}
+		}
+		if !ok {
+			bit, ok = modNames[strings.ToLower(word)]
+		}
+		if !ok {
+			bit, ok = quizMods[strings.ToLower(word)]
+		}
+		if !ok || word == "" {
+			break
+		}
+		mods |= bit
+		s = rest
+	}
+	key = strings.Join(strings.FieldsFunc(s, sep), " ")
+	if plus {
+		key = "plus"
+	}
+	return mods, key
+}
+
+// keyMatches reports whether name is one of the ways to write keysym.
+func keyMatches(name, keysym string, lbl map[string]string) bool {
+	if keysym == "" || name == "" {
+		return keysym == name
+	}
+	names := []string{keysym, humanise(keysym), keyWords[keysym], keyGlyphs[keysym], heatNames[keysym]}
+	if l, ok := fmtKey(keysym, lbl); ok {
+		names = append(names, l)
+	}
+	squash := func(s string) string { return strings.ReplaceAll(strings.ToLower(s), " ", "") }
+	for _, n := range names {
+		if n != "" && squash(n) == squash(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// right reports whether the chord mods+key, as typed, is one of q's.
+func (q question) right(mods int, key string, lbl map[string]string) bool {
+	for _, r := range q.rows {
+		if a, ok := parseAccel(r.spec); ok && a.mods == mods && keyMatches(key, a.key, lbl) {
+			return true
+		}
+	}
+	return false
+}
+
+// pressed reports whether the chord pressed is one of q's.
+func (q question) pressed(c accel) bool {
+	for _, r := range q.rows {
+		if a, ok := parseAccel(r.spec); ok && a.spec() == c.spec() {
+			return true
+		}
+	}
+	return false
+}
+
+// quizQuestions groups rows by action, the least known first.
+func quizQuestions(rows []row, p *progress) []question {
+	var qs []question
+	at := map[string]int{} // app and action → question
+	ids := map[string]int{}
+	for _, r := range rows {
+		a, ok := parseAccel(r.spec)
+		if !ok || r.action == "" || strings.HasPrefix(a.key, "XF86") { // a key of its own is no chord to learn
+			continue
+		}
+		what := r.app + ": " + r.action
+		if i, ok := at[what]; ok {
+			qs[i].rows = append(qs[i].rows, r)
+			continue
+		}
+		at[what] = len(qs)
+		ids[progressID(r)]++
+		qs = append(qs, question{id: progressID(r), prompt: what, rows: []row{r}})
+	}
+	for i, q := range qs {
+		if ids[q.id] > 1 { // xkb-options holds several actions
+			qs[i].id += " " + q.rows[0].action
+		}
+	}
+	rand.Shuffle(len(qs), func(i, j int) { qs[i], qs[j] = qs[j], qs[i] })
+	slices.SortStableFunc(qs, func(a, b question) int {
+		ea, eb := p.Entries[a.id], p.Entries[b.id]
+		if ea.Learned != eb.Learned {
+			if ea.Learned {
+				return 1
+			}
+			return -1
+		}
+		return (2*ea.Correct - ea.Seen) - (2*eb.Correct - eb.Seen)
+	})
+	return qs
+}
+
+func runQuiz(args []string) error {
+	p, err := loadProgress()
+	if err != nil {
+		return err
+	}
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	qs := quizQuestions(rows, p)
+	if len(qs) == 0 {
+		return fmt.Errorf("quiz: no bound shortcuts to ask about")
+	}
+	qs = qs[:min(max(quizOpt.n, 1), len(qs))]
+
+	var kb *keyboard
+	in := bufio.NewScanner(os.Stdin)
+	if quizOpt.press {
+		won := map[string]bool{}
+		for _, r := range rows {
+			if a, ok := parseAccel(r.spec); ok {
+				won[a.spec()] = true
+			}
+		}
+		if kb, err = openKeyboard(args, true, func(sym string) bool { return won[sym] }); err != nil {
+			return fmt.Errorf("quiz: %w", err)
+		}
+		defer kb.close()
+		fmt.Println("Press each shortcut; Esc or Ctrl+C ends.")
+	} else {
+		fmt.Println("Type each shortcut, e.g. Super+3; Enter alone skips, Ctrl+D ends.")
+	}
+
+	score, asked := 0, 0
+	for i, q := range qs {
+		fmt.Printf("\n[%d/%d] %s\n", i+1, len(qs), q.prompt)
+		var ok bool
+		if kb != nil {
+			c, err := kb.next(runCtx)
+			for err == nil && c.key == "" && c.code != 0 {
+				fmt.Printf("key code %d is not on the layout; again\n", c.code)
+				c, err = kb.next(runCtx)
+			}
+			if err == io.EOF || err == nil && c.quit() {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			shown, _ := fmtKey(c.spec(), lbl)
+			fmt.Println("> " + shown)
+			ok = q.pressed(c.accel)
+		} else {
+			fmt.Print("> ")
+			if !in.Scan() {
+				fmt.Println()
+				break
+			}
+			mods, key := typedAccel(in.Text(), lbl)
+			ok = q.right(mods, key, lbl)
+		}
+		asked++
+		e := p.Entries[q.id]
+		e.Seen++
+		e.Last = time.Now()
+		if ok {
+			score++
+			e.Correct++
+			e.Learned = e.Correct >= quizLearned && 4*e.Correct >= 3*e.Seen
+			fmt.Println(sgrIf(palette().winner, "✔ right"))
+		} else {
+			e.Learned = false
+			fmt.Println(sgrIf(palette().conflict, "✘ it is ") + q.answers())
+		}
+		p.Entries[q.id] = e
+	}
+	if asked == 0 {
+		return nil
+	}
+	fmt.Printf("\nScore: %d/%d.\n", score, asked)
+	return p.save()
+}
+
+// sgrIf is sgr when stdout takes colour.
+func sgrIf(code, s string) string {
+	if colorOn() {
+		return sgr(code, s)
+	}
+	return s
+}
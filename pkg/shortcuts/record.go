@@ -0,0 +1,111 @@
+package shortcuts
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*──────────────── golden fixtures ────────────────
+
+gnome-shortcuts --record DIR (hidden) saves what
+this machine's table is built from, as a case for
+the golden tests in testdata/golden:
+
+	dump.txt      every setting, relocatables too
+	schemas/      the XML of the schemas in it,
+	              with their dirs' enums/overrides
+	xkb/symbols/  the active layout's files
+	args          the list command to replay
+
+The expected output is not taken from here:
+go test -run Golden -update writes want from the
+fixture, so the case pins down resolution order.
+*/
+
+func init() {
+	commands["__record"] = command{run: runRecord}
+}
+
+// xkbSeen, when set, collects the symbols files includeSymbols reads.
+var xkbSeen map[string]bool
+
+func runRecord(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("--record: want a fixture DIR")
+	}
+	dir := args[0]
+	if ls, _ := os.ReadDir(dir); len(ls) > 0 {
+		return fmt.Errorf("--record: %s is not empty", dir)
+	}
+	entries := dumpAll()
+	if len(entries) == 0 {
+		return fmt.Errorf("--record: no settings read; is gsettings available?")
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "schemas"), 0o755); err != nil {
+		return err
+	}
+	var dump strings.Builder
+	files := map[string]bool{}
+	for _, e := range entries {
+		fmt.Fprintf(&dump, "%s %s %s\n", e.schema, e.key, e.val)
+		id, _, _ := strings.Cut(e.schema, ":")
+		for xs := schemaXML(id); xs != nil && !files[xs.file]; xs = schemaXML(xs.Extends) {
+			files[xs.file] = true
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "dump.txt"), []byte(dump.String()), 0o644); err != nil {
+		return err
+	}
+
+	for f := range files {
+		for _, pat := range []string{"*.enums.xml", "*.gschema.override"} {
+			m, _ := filepath.Glob(filepath.Join(filepath.Dir(f), pat))
+			for _, g := range m {
+				files[g] = true
+			}
+		}
+	}
+	for _, f := range sortedKeys(files) {
+		if err := copyNew(f, filepath.Join(dir, "schemas", filepath.Base(f))); err != nil {
+			return err
+		}
+	}
+
+	xkbSeen = map[string]bool{}
+	loadKeymap(activeLayout())
+	for f := range xkbSeen {
+		if err := copyNew(filepath.Join(xkbRoot(), "symbols", f), filepath.Join(dir, "xkb", "symbols", f)); err != nil {
+			return err
+		}
+	}
+	xkbSeen = nil
+
+	cmd := []string{"list", "-session", sessionType()}
+	if v, ok := shellVersion(); ok {
+		cmd = append(cmd, "-shell-version", fmt.Sprintf("%d.%d", v[0], v[1]))
+	}
+	if err := os.WriteFile(filepath.Join(dir, "args"), []byte(strings.Join(cmd, " ")+"\n"), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "recorded %d settings and %d schema files in %s\n", len(entries), len(files), dir)
+	fmt.Fprintln(os.Stderr, "now run: go test ./pkg/shortcuts -run Golden -update")
+	return nil
+}
+
+// copyNew copies src to dst unless dst exists: the first schema dir
+// to define a file wins, as in the search.
+func copyNew(src, dst string) error {
+	if _, err := os.Stat(dst); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0o644)
+}
@@ -0,0 +1,129 @@
+package shortcuts
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+/*────────────── relocatable schemas ─────────────
+
+`gsettings list-recursively` never descends into
+relocatable schemas; their instances are named
+by a list key elsewhere.  Each entry below says
+where that list lives and how an item becomes a
+path, so collect can read every instance the
+same way (custom keybindings are just one).
+
+Shell extensions keep per-monitor keybindings in
+relocatable schemas with no list key: every
+dconf subdir of the path GLib derives from the
+id is an instance.
+*/
+
+type relocatable struct {
+	child       string // relocatable schema id
+	parent, key string // "schema key" holding the instance list
+	path        string // fmt template for bare ids (Cinnamon's custom-list); "" when items are paths
+	dir         string // or: every dconf subdir of dir is an instance
+}
+
+var relocatables = []relocatable{
+	{child: customSchema,
+		parent: "org.gnome.settings-daemon.plugins.media-keys", key: "custom-keybindings"},
+}
+
+const extensionSchemas = "org.gnome.shell.extensions."
+
+// extensionRelocatables lists the installed relocatable keybinding
+// schemas of shell extensions.
+func extensionRelocatables() []relocatable {
+	var out []relocatable
+	seen := map[string]bool{}
+	for _, id := range slices.Concat(compiledSchemaIDs(), xmlSchemaIDs()) {
+		if seen[id] || !strings.HasPrefix(id, extensionSchemas) || !strings.Contains(id, "keybinding") {
+			continue
+		}
+		seen[id] = true
+		if lookupSchema(id).path == "" {
+			out = append(out, relocatable{child: id, dir: "/" + strings.ReplaceAll(id, ".", "/") + "/"})
+		}
+	}
+	return out
+}
+
+// customSchemas hold one custom shortcut per relocatable instance.
+var customSchemas = []string{customSchema}
+
+func isCustom(schema string) bool {
+	for _, c := range customSchemas {
+		if strings.HasPrefix(schema, c+":") {
+			return true
+		}
+	}
+	return false
+}
+
+// isInstanceList reports whether schema key names relocatable
+// instances rather than holding a binding.
+func isInstanceList(schema, key string) bool {
+	for _, r := range relocatables {
+		if r.parent == schema && r.key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// relocatableInstances lists "id:/path/" for every known instance;
+// vals is the "schema key" → value map of the main dump.
+func relocatableInstances(vals map[string]string) []string {
+	var out []string
+	for _, r := range slices.Concat(relocatables, extensionRelocatables()) {
+		if r.dir != "" {
+			for _, sub := range dconfList(r.dir) {
+				if strings.HasSuffix(sub, "/") {
+					out = append(out, r.child+":"+r.dir+sub)
+				}
+			}
+			continue
+		}
+		v, ok := vals[r.parent+" "+r.key]
+		if !ok {
+			v = gsettingsGet(r.parent, r.key)
+		}
+		for _, item := range gvTextStrings(v) {
+			if p, ok := r.instancePath(item); ok {
+				out = append(out, r.child+":"+p)
+			}
+		}
+	}
+	return out
+}
+
+// instancePath is the dconf path of list item: the item itself, or
+// the path template filled with it.
+func (r relocatable) instancePath(item string) (string, bool) {
+	p := item
+	if r.path != "" {
+		p = fmt.Sprintf(r.path, item)
+	}
+	return p, strings.HasPrefix(p, "/") && strings.HasSuffix(p, "/")
+}
+
+// dumpAll is the main dump plus every relocatable instance not in it.
+func dumpAll() []entry {
+	entries := parseDump(gsettingsDump())
+	vals := map[string]string{}
+	have := map[string]bool{}
+	for _, e := range entries {
+		vals[e.schema+" "+e.key] = e.val
+		have[e.schema] = true
+	}
+	for _, id := range relocatableInstances(vals) {
+		if !have[id] {
+			entries = append(entries, parseDump(gsettingsDump(id))...)
+		}
+	}
+	return entries
+}
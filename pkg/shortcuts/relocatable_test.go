@@ -0,0 +1,46 @@
+package shortcuts
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExtensionRelocatables(t *testing.T) {
+	withSchemaDir(t, map[string]string{"ext.gschema.xml": `<schemalist>
+  <schema id="org.gnome.shell.extensions.tiler.keybindings">
+    <key name="tile-left" type="as"><default>[]</default></key>
+  </schema>
+  <schema id="org.gnome.shell.extensions.tiler.fixed-keybindings" path="/org/gnome/shell/extensions/tiler/">
+    <key name="tile-right" type="as"><default>[]</default></key>
+  </schema>
+  <schema id="org.gnome.shell.extensions.tiler.monitor">
+    <key name="gap" type="u"><default>0</default></key>
+  </schema>
+</schemalist>
+`})
+	want := []relocatable{{child: "org.gnome.shell.extensions.tiler.keybindings",
+		dir: "/org/gnome/shell/extensions/tiler/keybindings/"}}
+	if got := extensionRelocatables(); !reflect.DeepEqual(got, want) {
+		t.Errorf("extensionRelocatables = %+v, want %+v", got, want)
+	}
+}
+
+func TestRelocatableInstances(t *testing.T) {
+	saved := defaultsOnly
+	t.Cleanup(func() { defaultsOnly = saved })
+	withSchemaDir(t, nil)
+	defaultsOnly = true // no dconf subdirs or list keys from this machine
+
+	vals := map[string]string{
+		"org.gnome.settings-daemon.plugins.media-keys custom-keybindings": "['" + customDir + "custom0/', 'custom1']",
+		cinnamonKeys + " custom-list": "['custom0', '__dummy__']",
+	}
+	want := []string{
+		customSchema + ":" + customDir + "custom0/",
+		cinnamonCustom + ":/org/cinnamon/desktop/keybindings/custom-keybindings/custom0/",
+		cinnamonCustom + ":/org/cinnamon/desktop/keybindings/custom-keybindings/__dummy__/",
+	}
+	if got := relocatableInstances(vals); !reflect.DeepEqual(got, want) {
+		t.Errorf("relocatableInstances =\n%q\nwant\n%q", got, want)
+	}
+}
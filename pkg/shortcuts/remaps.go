@@ -0,0 +1,234 @@
+package shortcuts
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*──────────────── keyd and kanata ────────────────
+
+Remapping daemons sit below XKB: with keyd's
+"capslock = leftmeta" the Caps key sends Super and
+GNOME never knows.  Their configs are read so the
+modifier labels name the key actually pressed, the
+way the XKB options do.  Only the base layer
+counts; tap-hold keys count as their hold action.
+*/
+
+// The daemons' system configs; kanata's user config is read first.
+var (
+	keydGlob   = "/etc/keyd/*.conf"
+	kanataDirs = []string{"/etc/kanata"}
+)
+
+type remap struct{ from, mod string } // physical key name → modifier token
+
+// remapMods maps the daemons' modifier names to GTK tokens.
+var remapMods = map[string]string{
+	"meta": "<Super>", "leftmeta": "<Super>", "rightmeta": "<Super>", "lmet": "<Super>", "rmet": "<Super>",
+	"control": "<Control>", "leftcontrol": "<Control>", "rightcontrol": "<Control>", "lctl": "<Control>", "rctl": "<Control>",
+	"alt": "<Alt>", "leftalt": "<Alt>", "lalt": "<Alt>", "ralt": "<Alt>",
+	"shift": "<Shift>", "leftshift": "<Shift>", "rightshift": "<Shift>", "lsft": "<Shift>", "rsft": "<Shift>",
+}
+
+var remapKeyNames = map[string]string{
+	"capslock": "Caps", "caps": "Caps", "esc": "Esc", "tab": "Tab", "enter": "Enter", "ret": "Enter",
+	"space": "Space", "spc": "Space", "leftalt": "Left Alt", "lalt": "Left Alt",
+	"rightalt": "Right Alt", "ralt": "Right Alt", "leftmeta": "Left Win", "lmet": "Left Win",
+	"rightmeta": "Right Win", "rmet": "Right Win", "leftcontrol": "Left Ctrl", "lctl": "Left Ctrl",
+	"rightcontrol": "Right Ctrl", "rctl": "Right Ctrl", "compose": "Menu", "menu": "Menu",
+}
+
+func remapKeyName(k string) string {
+	if n, ok := remapKeyNames[k]; ok {
+		return n
+	}
+	return humanise(k)
+}
+
+// keyRemaps lists the modifier remaps of every keyd and kanata config.
+func keyRemaps() []remap {
+	if remote() || defaultsOnly || fromDump() {
+		return nil
+	}
+	var out []remap
+	files, _ := filepath.Glob(keydGlob)
+	for _, f := range files {
+		out = append(out, keydRemaps(f)...)
+	}
+	dirs := kanataDirs
+	if cfg, err := os.UserConfigDir(); err == nil {
+		dirs = append([]string{filepath.Join(cfg, "kanata")}, dirs...)
+	}
+	for _, d := range dirs {
+		files, _ := filepath.Glob(filepath.Join(d, "*.kbd"))
+		for _, f := range files {
+			out = append(out, kanataRemaps(f)...)
+		}
+	}
+	return out
+}
+
+// applyRemaps adds "(Caps)" and the like to the modifiers keys now send.
+func applyRemaps(lbl map[string]string, rs []remap) {
+	for _, r := range rs {
+		name := remapKeyName(r.from)
+		if remapMods[r.from] == r.mod || strings.Contains(lbl[r.mod], name) {
+			continue
+		}
+		lbl[r.mod] += " (" + name + ")"
+	}
+}
+
+/*──────── keyd ────────*/
+
+// keydAction reduces "overload(control, esc)" or "layer(meta)" to the
+// modifier it holds.
+func keydAction(v string) (string, bool) {
+	v = strings.TrimSpace(v)
+	if fn, args, ok := strings.Cut(v, "("); ok {
+		switch fn {
+		case "layer", "oneshot", "overload", "overloadt", "overloadt2", "overloadi", "lettermod":
+			v = strings.TrimSpace(strings.Split(strings.TrimSuffix(args, ")"), ",")[0])
+		default:
+			return "", false
+		}
+	}
+	mod, ok := remapMods[v]
+	return mod, ok
+}
+
+func keydRemaps(path string) []remap {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+	var out []remap
+	section := ""
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		l := strings.TrimSpace(sc.Text())
+		if l == "" || l[0] == '#' {
+			continue
+		}
+		if strings.HasPrefix(l, "[") {
+			section = strings.Trim(l, "[]")
+			continue
+		}
+		if section != "main" {
+			continue
+		}
+		k, v, ok := strings.Cut(l, "=")
+		if !ok {
+			continue
+		}
+		if mod, ok := keydAction(v); ok {
+			out = append(out, remap{strings.TrimSpace(k), mod})
+		}
+	}
+	return out
+}
+
+/*──────── kanata ────────*/
+
+// sexpr is a kanata s-expression: an atom or a list.
+type sexpr struct {
+	atom string
+	list []sexpr
+}
+
+func parseSexprs(s string) []sexpr {
+	var stack [][]sexpr
+	cur := []sexpr{}
+	tok := func(t string) {
+		if t != "" {
+			cur = append(cur, sexpr{atom: t})
+		}
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == ';' && i+1 < len(s) && s[i+1] == ';': // comment to end of line
+			for i < len(s) && s[i] != '\n' {
+				i++
+			}
+		case c == '(':
+			tok(b.String())
+			b.Reset()
+			stack = append(stack, cur)
+			cur = []sexpr{}
+		case c == ')':
+			tok(b.String())
+			b.Reset()
+			if len(stack) == 0 {
+				continue
+			}
+			l := sexpr{list: cur}
+			cur = append(stack[len(stack)-1], l)
+			stack = stack[:len(stack)-1]
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			tok(b.String())
+			b.Reset()
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return cur
+}
+
+// kanataAction is the modifier an action holds: lmet, @alias or the
+// hold half of tap-hold.
+func kanataAction(e sexpr, aliases map[string]sexpr, depth int) (string, bool) {
+	if depth > 8 {
+		return "", false
+	}
+	if e.atom != "" {
+		if a, ok := aliases[strings.TrimPrefix(e.atom, "@")]; ok && strings.HasPrefix(e.atom, "@") {
+			return kanataAction(a, aliases, depth+1)
+		}
+		mod, ok := remapMods[e.atom]
+		return mod, ok
+	}
+	if len(e.list) > 1 && strings.HasPrefix(e.list[0].atom, "tap-hold") {
+		return kanataAction(e.list[len(e.list)-1], aliases, depth+1)
+	}
+	return "", false
+}
+
+// kanataRemaps pairs defsrc with the first deflayer, key by key.
+func kanataRemaps(path string) []remap {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+	var src, layer []sexpr
+	aliases := map[string]sexpr{}
+	for _, e := range parseSexprs(string(data)) {
+		if len(e.list) == 0 {
+			continue
+		}
+		switch e.list[0].atom {
+		case "defsrc":
+			src = e.list[1:]
+		case "deflayer":
+			if layer == nil && len(e.list) > 1 {
+				layer = e.list[2:]
+			}
+		case "defalias":
+			for i := 1; i+1 < len(e.list); i += 2 {
+				aliases[e.list[i].atom] = e.list[i+1]
+			}
+		}
+	}
+	var out []remap
+	for i := 0; i < len(src) && i < len(layer); i++ {
+		if mod, ok := kanataAction(layer[i], aliases, 0); ok {
+			out = append(out, remap{src[i].atom, mod})
+		}
+	}
+	return out
+}
@@ -0,0 +1,101 @@
+package shortcuts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+/*──────────────────── -host ─────────────────────
+
+-host user@machine runs gsettings (and
+gnome-shell --version) over ssh and renders here,
+to audit machines without logging into their
+desktops.  Reading works without a session;
+`set` falls back to the user's bus socket under
+/run/user.
+
+Schemas are still read from this machine, and
+local-only sources (dconf locks and provenance,
+GTK accels files, logind) are skipped rather
+than describing the wrong host.
+*/
+
+var hostOpt string
+
+func remote() bool { return hostOpt != "" }
+
+// checkHost refuses a host ssh would read as an option
+// (-oProxyCommand=… runs a local command).
+func checkHost(h string) error {
+	if strings.HasPrefix(h, "-") {
+		return fmt.Errorf("host %q: want user@machine, not an ssh option", h)
+	}
+	return nil
+}
+
+// runCtx bounds every external call: -timeout on the command line,
+// or the context a library caller passed to Collect.
+var runCtx = context.Background()
+
+// withRunCtx makes ctx the run context until restore is called.
+func withRunCtx(ctx context.Context) (restore func()) {
+	saved := runCtx
+	runCtx = ctx
+	return func() { runCtx = saved }
+}
+
+// toolContext is runCtx limited to one call's toolTimeout.
+func toolContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(runCtx, toolTimeout())
+}
+
+// toolTimeout leaves room for the ssh handshake.
+func toolTimeout() time.Duration {
+	if remote() {
+		return 20 * time.Second
+	}
+	return 3 * time.Second
+}
+
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// toolCmd runs name here or, with -host, on the remote machine.  Once
+// ctx is done the call returns promptly, even if the killed tool left
+// children holding its output open.
+func toolCmd(ctx context.Context, name string, args ...string) *exec.Cmd {
+	var cmd *exec.Cmd
+	if !remote() {
+		cmd = exec.CommandContext(ctx, name, args...)
+	} else {
+		q := []string{`DBUS_SESSION_BUS_ADDRESS=${DBUS_SESSION_BUS_ADDRESS:-unix:path=/run/user/$(id -u)/bus}`, name}
+		for _, a := range args {
+			q = append(q, shellQuote(a))
+		}
+		cmd = exec.CommandContext(ctx, "ssh", "-o", "BatchMode=yes", "--", hostOpt, strings.Join(q, " "))
+	}
+	cmd.WaitDelay = 100 * time.Millisecond
+	return cmd
+}
+
+var remoteWarned bool
+
+// warnRemote reports the first failed remote call; locally a missing
+// gsettings just yields an empty table, as before.
+func warnRemote(err error) {
+	if !remote() || remoteWarned {
+		return
+	}
+	remoteWarned = true
+	msg := err.Error()
+	var ee *exec.ExitError
+	if errors.As(err, &ee) && len(ee.Stderr) > 0 {
+		msg = strings.TrimSpace(string(ee.Stderr))
+	}
+	fmt.Fprintf(warnOut, "gnome-shortcuts: %s: %s\n", hostOpt, msg)
+}
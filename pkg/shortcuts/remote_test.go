@@ -0,0 +1,20 @@
+package shortcuts
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestRemoteHost(t *testing.T) {
+	if _, err := (Options{Host: "-oProxyCommand=touch /tmp/x"}).apply(); err == nil {
+		t.Error("Options.Host accepted an ssh option")
+	}
+	saved := hostOpt
+	t.Cleanup(func() { hostOpt = saved })
+	hostOpt = "me@box"
+	args := toolCmd(context.Background(), "gsettings", "list-recursively").Args
+	if i := slices.Index(args, "me@box"); i < 1 || args[i-1] != "--" {
+		t.Errorf("ssh argv %q: want -- before the host", args)
+	}
+}
@@ -0,0 +1,65 @@
+package shortcuts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*─────────────────── renderers ───────────────────
+
+list -format and Render pick one by name.  A
+renderer gets the table as sections: the system
+shortcuts, then the app-local ones and the numpad
+layer under their titles.  Each format lives in
+its own render_*.go and registers in init().
+*/
+
+// Section is one titled part of the table; the first has no title.
+type Section struct {
+	Title    string
+	Bindings []Binding
+}
+
+// Renderer writes the table in one output format, stopping with
+// ctx's error once it is done.
+type Renderer interface {
+	Render(ctx context.Context, w io.Writer, sections []Section) error
+}
+
+var (
+	renderers     = map[string]Renderer{}
+	formatAliases = map[string]string{"": "text", "table": "text", "markdown": "md"}
+)
+
+// RegisterRenderer makes r available to list -format and Render.
+func RegisterRenderer(format string, r Renderer) { renderers[format] = r }
+
+func rendererFor(format string) (Renderer, error) {
+	if a, ok := formatAliases[format]; ok {
+		format = a
+	}
+	if r, ok := renderers[format]; ok {
+		return r, nil
+	}
+	return nil, fmt.Errorf("unknown format %q: want one of %s", format, strings.Join(sortedKeys(renderers), ", "))
+}
+
+// tagged is b's action with its favorite, lock and -source tags.
+func tagged(b Binding) string {
+	act := b.Action
+	if b.Favorite {
+		act = "★ " + act
+	}
+	if b.Locked {
+		act += " (locked)"
+	}
+	if b.Source != "" {
+		act += " [" + sourceLabel(b.Source) + "]"
+	}
+	if b.Position != "" {
+		act += " (" + b.Position + ")"
+	}
+	return act
+}
@@ -0,0 +1,27 @@
+package shortcuts
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+)
+
+func init() { RegisterRenderer("json", jsonRenderer{}) }
+
+// jsonRenderer writes one array of every section's bindings; Rank
+// tells the app-local ones apart.
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(ctx context.Context, w io.Writer, sections []Section) error {
+	bs := []Binding{}
+	for _, s := range sections {
+		bs = append(bs, s.Bindings...)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(bs)
+}
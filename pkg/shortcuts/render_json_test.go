@@ -0,0 +1,36 @@
+package shortcuts
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestJSONRenderer(t *testing.T) {
+	var b strings.Builder
+	if err := (jsonRenderer{}).Render(context.Background(), &b, testSections); err != nil {
+		t.Fatal(err)
+	}
+	var got []Binding
+	if err := json.Unmarshal([]byte(b.String()), &got); err != nil {
+		t.Fatalf("not JSON: %v\n%s", err, b.String())
+	}
+	if len(got) != 3 {
+		t.Fatalf("got %d bindings, want every section's 3", len(got))
+	}
+	if got[1].Source != "system:site" || !got[0].Locked || got[2].Spec != "<Super>KP_7" {
+		t.Errorf("fields lost: %+v", got)
+	}
+	if strings.Contains(b.String(), `\u003c`) {
+		t.Error("accelerators are HTML-escaped")
+	}
+}
+
+func TestJSONRendererEmpty(t *testing.T) {
+	var b strings.Builder
+	(jsonRenderer{}).Render(context.Background(), &b, nil)
+	if got := strings.TrimSpace(b.String()); got != "[]" {
+		t.Errorf("got %s, want []", got)
+	}
+}
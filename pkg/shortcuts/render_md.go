@@ -0,0 +1,35 @@
+package shortcuts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() { RegisterRenderer("md", mdRenderer{}) }
+
+// mdRenderer writes a Markdown table per section, titles as headings.
+type mdRenderer struct{}
+
+func (mdRenderer) Render(ctx context.Context, w io.Writer, sections []Section) error {
+	cell := strings.NewReplacer("|", `\|`).Replace
+	for i, s := range sections {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if s.Title != "" {
+			fmt.Fprintf(w, "## %s\n\n", s.Title)
+		}
+		if _, err := fmt.Fprintln(w, "| Shortcut | Application | Action |\n|---|---|---|"); err != nil {
+			return err
+		}
+		for _, b := range s.Bindings {
+			fmt.Fprintf(w, "| %s | %s | %s |\n", cell(b.Accel), cell(b.App), cell(tagged(b)))
+		}
+	}
+	return nil
+}
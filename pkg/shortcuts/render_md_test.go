@@ -0,0 +1,28 @@
+package shortcuts
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestMDRenderer(t *testing.T) {
+	var b strings.Builder
+	if err := (mdRenderer{}).Render(context.Background(), &b, testSections); err != nil {
+		t.Fatal(err)
+	}
+	want := `| Shortcut | Application | Action |
+|---|---|---|
+| Win + Q | Window Manager | Close (locked) |
+| Win + T | Custom | Term \| tmux [system (site)] |
+
+## Numpad layer
+
+| Shortcut | Application | Action |
+|---|---|---|
+| Win + Numpad 7 | Window Manager | Move To Corner Nw |
+`
+	if b.String() != want {
+		t.Errorf("got:\n%s\nwant:\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,33 @@
+package shortcuts
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRendererFor(t *testing.T) {
+	for format, want := range map[string]Renderer{
+		"": textRenderer{}, "table": textRenderer{}, "text": textRenderer{},
+		"json": jsonRenderer{}, "md": mdRenderer{}, "markdown": mdRenderer{},
+	} {
+		if r, err := rendererFor(format); err != nil || r != want {
+			t.Errorf("rendererFor(%q) = %T, %v", format, r, err)
+		}
+	}
+	if _, err := rendererFor("xml"); err == nil {
+		t.Error("unknown format accepted")
+	}
+}
+
+func TestRenderersStopWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for name, r := range renderers {
+		var b strings.Builder
+		if err := r.Render(ctx, &b, testSections); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: got %v, want context.Canceled", name, err)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package shortcuts
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+func init() { RegisterRenderer("text", textRenderer{}) }
+
+const rowFmt = "%s %-28s %-*s\n" // first column padded by padTo
+
+// Below tableWidth columns the table shrinks to fit, cutting cells
+// with an ellipsis; below narrowWidth each binding takes two lines.
+const (
+	tableWidth  = 100
+	narrowWidth = 60
+)
+
+// padding is the blanks padTo would add to s.
+func padding(s string, n int) string { return strings.Repeat(" ", max(n-dispWidth(s), 0)) }
+
+// textRenderer is the terminal table, one ruled table per section;
+// color adds the ANSI colours of color.go.  width is the terminal's:
+// cells are cut to fit it, while 0 (a pipe, the library) cuts nothing.
+// mark, in colour, highlights where list -grep matched.  The action
+// column is 40 wide, or as wide as its longest tagged action.
+type textRenderer struct {
+	color bool
+	width int
+	mark  string
+	act   int
+}
+
+func (t textRenderer) Render(ctx context.Context, w io.Writer, sections []Section) error {
+	t.act = 40
+	for _, s := range sections {
+		for _, b := range s.Bindings {
+			t.act = max(t.act, dispWidth(tagged(b)))
+		}
+	}
+	rule := max(tableWidth, 58+t.act)
+	if t.width > 0 {
+		rule = min(rule, t.width)
+	}
+	line := strings.Repeat("─", rule)
+	for i, s := range sections {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		switch {
+		case s.Title != "" && i > 0:
+			fmt.Fprintf(w, "\n%s\n", t.fit(s.Title))
+		case s.Title != "":
+			fmt.Fprintln(w, t.fit(s.Title))
+		}
+		fmt.Fprintln(w, line)
+		if t.narrow() {
+			fmt.Fprintf(w, "Shortcut\n    %s\n", t.fit("Application · Action"))
+		} else {
+			fmt.Fprint(w, t.row([3]string{"Shortcut", "Application", "Action"}, [3]string{}))
+		}
+		if _, err := fmt.Fprintln(w, line); err != nil {
+			return err
+		}
+		for _, b := range s.Bindings {
+			cells := [3]string{b.Accel, b.App, tagged(b)}
+			var painted [3]string
+			if t.color {
+				painted = [3]string{paintAccel(b), sgr(familyColor(b.App), b.App), ""}
+				for i, c := range cells {
+					if m, ok := markMatches(c, t.mark, ""); ok {
+						painted[i] = m
+					}
+				}
+			}
+			if t.narrow() {
+				t.record(w, b, painted)
+			} else {
+				fmt.Fprint(w, t.row(cells, painted))
+			}
+		}
+	}
+	return nil
+}
+
+func (t textRenderer) narrow() bool { return t.width > 0 && t.width < narrowWidth }
+
+// fit cuts s to the terminal's width.
+func (t textRenderer) fit(s string) string {
+	if t.width == 0 {
+		return s
+	}
+	return fitTo(s, t.width)
+}
+
+// row is one line of three columns: 28, 28 and the rest at full
+// width, the first two sharing what is left on a smaller terminal.
+// A painted cell, coloured or marked, is used unless it must be cut.
+func (t textRenderer) row(cells, painted [3]string) string {
+	if t.width == 0 {
+		action := cells[2]
+		if painted[2] != "" {
+			action = painted[2] + padding(action, t.act)
+		}
+		return fmt.Sprintf(rowFmt, cmp.Or(painted[0], cells[0])+padding(cells[0], 28),
+			cmp.Or(painted[1], cells[1])+padding(cells[1], 28), t.act, action)
+	}
+	a, p := 28, 28
+	if t.width < tableWidth {
+		a = min(28, (t.width-26)/2) // leave the action 24 columns
+		p = a
+	}
+	act := t.width - a - p - 2
+	cell := func(i, n int) string {
+		s, out := cells[i], painted[i]
+		if out == "" || dispWidth(s) > n {
+			out, _ = markMatches(fitTo(s, n), t.markIf(), "")
+		}
+		return out + padding(fitTo(s, n), n)
+	}
+	return cell(0, a) + " " + cell(1, p) + " " + cell(2, min(act, t.act)) + "\n"
+}
+
+// markIf is what to highlight: nothing without colour.
+func (t textRenderer) markIf() string {
+	if !t.color {
+		return ""
+	}
+	return t.mark
+}
+
+// record is a binding on two lines, for a narrow terminal.
+func (t textRenderer) record(w io.Writer, b Binding, painted [3]string) {
+	accel := fitTo(b.Accel, t.width)
+	if painted[0] != "" && accel == b.Accel {
+		accel = painted[0]
+	} else {
+		accel, _ = markMatches(accel, t.markIf(), "")
+	}
+	rest := fitTo(b.App+" · "+tagged(b), t.width-4)
+	if m, ok := markMatches(rest, t.markIf(), ""); ok {
+		rest = m
+	} else if painted[1] != "" && strings.HasPrefix(rest, b.App+" ·") {
+		rest = painted[1] + strings.TrimPrefix(rest, b.App)
+	}
+	fmt.Fprintf(w, "%s\n    %s\n", accel, rest)
+}
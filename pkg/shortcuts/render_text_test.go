@@ -0,0 +1,120 @@
+package shortcuts
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+var testSections = []Section{
+	{Bindings: []Binding{
+		{Accel: "Win + Q", Spec: "<Super>q", App: "Window Manager", Action: "Close", Rank: 0, Locked: true},
+		{Accel: "Win + T", Spec: "<Super>t", App: "Custom", Action: "Term | tmux", Rank: 3, Source: "system:site"},
+	}},
+	{Title: "Numpad layer", Bindings: []Binding{
+		{Accel: "Win + Numpad 7", Spec: "<Super>KP_7", App: "Window Manager", Action: "Move To Corner Nw"},
+	}},
+}
+
+func TestTextRenderer(t *testing.T) {
+	var b strings.Builder
+	if err := (textRenderer{}).Render(context.Background(), &b, testSections); err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(b.String(), "\n")
+	want := []string{
+		strings.Repeat("─", 100),
+		"Shortcut                     Application                  Action                                  ",
+		strings.Repeat("─", 100),
+		"Win + Q                      Window Manager               Close (locked)                          ",
+		"Win + T                      Custom                       Term | tmux [system (site)]             ",
+		"",
+		"Numpad layer",
+		strings.Repeat("─", 100),
+	}
+	for i, w := range want {
+		got := "(no such line)"
+		if i < len(lines) {
+			got = strconv.Quote(lines[i])
+		}
+		if i >= len(lines) || lines[i] != w {
+			t.Fatalf("line %d:\n got %s\nwant %q", i, got, w)
+		}
+	}
+	if !strings.Contains(b.String(), "Win + Numpad 7") {
+		t.Error("second section missing")
+	}
+}
+
+func TestTextRendererPadsWideLabels(t *testing.T) {
+	var b strings.Builder
+	(textRenderer{}).Render(context.Background(), &b, []Section{{Bindings: []Binding{{Accel: "Volume Up 🔊", App: "Media Keys", Action: "Volume Up"}}}})
+	row := strings.Split(b.String(), "\n")[3]
+	if i := strings.Index(row, "Media Keys"); dispWidth(row[:i]) != 29 {
+		t.Errorf("app column starts at width %d, want 29: %q", dispWidth(row[:i]), row)
+	}
+}
+
+func TestTextRendererColorKeepsColumns(t *testing.T) {
+	var plain, color strings.Builder
+	(textRenderer{}).Render(context.Background(), &plain, testSections)
+	(textRenderer{color: true}).Render(context.Background(), &color, testSections)
+	if !strings.Contains(color.String(), "\x1b[") {
+		t.Fatal("no colour")
+	}
+	if got := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(color.String(), ""); got != plain.String() {
+		t.Errorf("without its colour:\n%s\nwant\n%s", got, plain.String())
+	}
+}
+
+func TestMarkMatches(t *testing.T) {
+	m := func(s string) string { return "\x1b[" + palette().match + "m" + s + "\x1b[m" }
+	for _, c := range []struct {
+		s, f, style string
+		want        string
+		ok          bool
+	}{
+		{"Move To Corner", "corner", "", "Move To " + m("Corner"), true},
+		{"Ctrl + Alt + T", "+", "", "Ctrl " + m("+") + " Alt " + m("+") + " T", true},
+		{"Minimize", "MINI", "1", m("Mini") + "\x1b[1mmize", true},
+		{"Größe ändern", "ÄND", "", "Größe " + m("änd") + "ern", true},
+		{"Close Window", "tab", "", "Close Window", false},
+		{"Close Window", "", "", "Close Window", false},
+	} {
+		if got, ok := markMatches(c.s, c.f, c.style); got != c.want || ok != c.ok {
+			t.Errorf("markMatches(%q, %q, %q) = %q, %v, want %q, %v", c.s, c.f, c.style, got, ok, c.want, c.ok)
+		}
+	}
+}
+
+func TestTextRendererMarksMatches(t *testing.T) {
+	var plain, marked strings.Builder
+	(textRenderer{}).Render(context.Background(), &plain, testSections)
+	(textRenderer{color: true, mark: "corner"}).Render(context.Background(), &marked, testSections)
+	if !strings.Contains(marked.String(), sgr(palette().match, "Corner")) {
+		t.Errorf("match not marked:\n%q", marked.String())
+	}
+	if got := regexp.MustCompile("\x1b\\[[0-9;]*m").ReplaceAllString(marked.String(), ""); got != plain.String() {
+		t.Errorf("marked, without its colour:\n%s\nwant\n%s", got, plain.String())
+	}
+}
+
+func TestTextRendererFitsWidth(t *testing.T) {
+	long := []Section{{Bindings: []Binding{
+		{Accel: "Ctrl + Alt + Shift + Numpad Page Down", App: "Window Manager", Action: "Move Window One Monitor To The Left And Maximise It"},
+	}}}
+	for _, width := range []int{40, 60, 80, 100, 140} {
+		var b strings.Builder
+		(textRenderer{width: width}).Render(context.Background(), &b, long)
+		for _, l := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+			if dispWidth(l) > width {
+				t.Errorf("width %d: line is %d wide: %q", width, dispWidth(l), l)
+			}
+		}
+		if width < narrowWidth && !strings.Contains(b.String(), "\n    Window Manager · Move") {
+			t.Errorf("width %d: no two-line record:\n%s", width, b.String())
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package shortcuts
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+/*───────────────────── repl ────────────────────
+
+One warmed-up model for a cleanup session:
+
+	list [text]          the table, optionally filtered
+	find text            same, matching any column
+	explain accel        who wins the chord and why
+	set schema key value like `gsettings set`
+	undo                 restore the value before the last set
+	reload               re-read gsettings
+	quit
+
+The model is rebuilt after set and undo only.
+*/
+
+func init() {
+	commands["repl"] = command{
+		help: "interactive session: list, find, explain, set, undo",
+		flags: func(fs *flag.FlagSet) {
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runRepl,
+	}
+}
+
+type change struct{ schema, key, old string }
+
+type model struct {
+	lbl  map[string]string
+	rows []row
+	won  map[string]row // canonical spec → winner
+	undo []change
+}
+
+func (m *model) load() error {
+	var err error
+	if m.rows, _, err = collect(m.lbl); err != nil {
+		return err
+	}
+	sortRows(m.rows)
+	m.won = map[string]row{}
+	for _, r := range m.rows {
+		if a, ok := parseAccel(r.spec); ok {
+			m.won[a.spec()] = r
+		}
+	}
+	return nil
+}
+
+func (m *model) specs() []string {
+	return sortedKeys(m.won)
+}
+
+func (m *model) schemas() []string {
+	seen := map[string]bool{}
+	for _, r := range m.rows {
+		for _, c := range append([]row{r}, r.lost...) {
+			if c.schema != "" {
+				seen[c.schema] = true
+			}
+		}
+	}
+	return sortedKeys(seen)
+}
+
+func (m *model) list(text string) {
+	text = strings.ToLower(text)
+	var out []row
+	for _, r := range m.rows {
+		hay := strings.ToLower(strings.Join([]string{r.accel, r.app, r.action, r.spec, r.schema, r.key}, " "))
+		if strings.Contains(hay, text) {
+			out = append(out, r)
+		}
+	}
+	if len(out) == 0 {
+		fmt.Println("no match")
+		return
+	}
+	printTable(out)
+}
+
+// rankNames describes the families collect assigns.
+var rankNames = map[int]string{
+	-2: "XKB", -1: "core", 0: "window manager", 1: "shell / media keys",
+	2: "other schema", 3: "custom", appRank: "applications",
+}
+
+func (m *model) explain(q string) error {
+	a, ok := parseAccel(q)
+	if !ok {
+		return fmt.Errorf("not an accelerator: %q", q)
+	}
+	w, ok := m.won[a.spec()]
+	if !ok {
+		fmt.Printf("%s is free\n", a.spec())
+		return nil
+	}
+	describe := func(r row) string {
+		s := fmt.Sprintf("%s: %s  [%s", r.app, r.action, rankNames[r.rank])
+		if r.order < 1<<20 {
+			s += fmt.Sprintf(", #%d in schema", r.order)
+		}
+		s += "]"
+		if r.schema != "" {
+			s += fmt.Sprintf("  %s %s", r.schema, r.key)
+		}
+		return s
+	}
+	fmt.Printf("%s  (%s)\n  fires: %s\n", w.accel, a.spec(), describe(w))
+	if m := lookupSchema(w.schema).meta[w.key]; m.summary != "" {
+		fmt.Printf("         %s\n", m.summary)
+	}
+	for _, l := range w.lost {
+		why := "resolver: " + resolverName
+		switch {
+		case resolverName != "gschema":
+		case l.rank != w.rank:
+			why = "lower family"
+		case l.order != w.order:
+			why = "later in schema"
+		}
+		fmt.Printf("  shadowed (%s): %s\n", why, describe(l))
+	}
+	return nil
+}
+
+func (m *model) set(schema, key, val string) error {
+	if isLocked(dconfPath(schema, key)) {
+		return fmt.Errorf("%s %s is locked by the administrator", schema, key)
+	}
+	old := gsettingsGet(schema, key)
+	if old == "" {
+		return fmt.Errorf("no key %s %s", schema, key)
+	}
+	if cs := lookupSchema(schema).meta[key].choices; len(cs) > 0 {
+		for _, v := range gvTextStrings(val) {
+			if !slices.Contains(cs, v) {
+				return fmt.Errorf("%s %s: %q is not one of %s", schema, key, v, strings.Join(cs, ", "))
+			}
+		}
+	}
+	if err := gsettingsSet(schema, key, val); err != nil {
+		return err
+	}
+	m.undo = append(m.undo, change{schema, key, old})
+	if err := m.load(); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s: %s → %s\n", schema, key, old, val)
+	return nil
+}
+
+func (m *model) revert() error {
+	if len(m.undo) == 0 {
+		return errors.New("nothing to undo")
+	}
+	c := m.undo[len(m.undo)-1]
+	if err := gsettingsSet(c.schema, c.key, c.old); err != nil {
+		return err
+	}
+	m.undo = m.undo[:len(m.undo)-1]
+	if err := m.load(); err != nil {
+		return err
+	}
+	fmt.Printf("%s %s restored to %s\n", c.schema, c.key, c.old)
+	return nil
+}
+
+// cutField splits the first whitespace-separated word off s.
+func cutField(s string) (string, string) {
+	s = strings.TrimSpace(s)
+	if i := strings.IndexAny(s, " \t"); i >= 0 {
+		return s[:i], strings.TrimSpace(s[i:])
+	}
+	return s, ""
+}
+
+// exec runs one line; done reports quit.
+func (m *model) exec(line string) (done bool, err error) {
+	cmd, rest := cutField(line)
+	if cmd == "gsettings" { // pasted from `conflicts`
+		cmd, rest = cutField(rest)
+	}
+	switch cmd {
+	case "":
+	case "list", "ls":
+		m.list(rest)
+	case "find":
+		if rest == "" {
+			return false, errors.New("usage: find text")
+		}
+		m.list(rest)
+	case "explain":
+		if rest == "" {
+			return false, errors.New("usage: explain accel")
+		}
+		return false, m.explain(rest)
+	case "set":
+		schema, rest := cutField(rest)
+		key, val := cutField(rest)
+		if val == "" {
+			return false, errors.New("usage: set schema key value")
+		}
+		if len(val) >= 2 && val[0] == '"' && val[len(val)-1] == '"' { // shell quoting
+			val = val[1 : len(val)-1]
+		}
+		return false, m.set(schema, key, val)
+	case "undo":
+		return false, m.revert()
+	case "reload":
+		if err := m.load(); err != nil {
+			return false, err
+		}
+		fmt.Printf("%d shortcuts\n", len(m.rows))
+	case "help", "?":
+		fmt.Println("list [text] · find text · explain accel · set schema key value · undo · reload · quit")
+	case "quit", "exit", "q":
+		return true, nil
+	default:
+		return false, fmt.Errorf("unknown command %q (try help)", cmd)
+	}
+	return false, nil
+}
+
+func runRepl([]string) error {
+	m := &model{lbl: labels("text")}
+	if err := m.load(); err != nil {
+		return err
+	}
+
+	hist := ""
+	if err := os.MkdirAll(stateDir(), 0o755); err == nil {
+		hist = filepath.Join(stateDir(), "repl_history")
+	}
+	dyn := func(items func() []string) readline.PrefixCompleterInterface {
+		return readline.PcItemDynamic(func(string) []string { return items() })
+	}
+	rl, err := readline.NewEx(&readline.Config{
+		Prompt:      "shortcuts> ",
+		HistoryFile: hist,
+		AutoComplete: readline.NewPrefixCompleter(
+			readline.PcItem("list"),
+			readline.PcItem("find"),
+			readline.PcItem("explain", dyn(m.specs)),
+			readline.PcItem("set", dyn(m.schemas)),
+			readline.PcItem("undo"),
+			readline.PcItem("reload"),
+			readline.PcItem("help"),
+			readline.PcItem("quit"),
+		),
+	})
+	if err != nil {
+		return err
+	}
+	defer rl.Close()
+
+	fmt.Printf("%d shortcuts loaded; type help\n", len(m.rows))
+	for {
+		line, err := rl.Readline()
+		if errors.Is(err, readline.ErrInterrupt) {
+			continue
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		done, err := m.exec(line)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		if done {
+			return nil
+		}
+	}
+}
+
+/*──────────── explain, set and disable ───────────
+
+The repl's explain and set as one-shot commands,
+and disable, which drops whatever fires on a chord
+the way conflicts suggests.  set takes a bare
+accelerator for a key holding bindings.
+*/
+
+var disableOpt struct{ dryRun bool }
+
+func init() {
+	modelFlags := func(fs *flag.FlagSet) {
+		collectFlags(fs)
+		displayFlags(fs)
+	}
+	commands["explain"] = command{
+		help:  "who fires on each accelerator given, who is shadowed and why",
+		flags: modelFlags,
+		run:   runExplain,
+		args:  accelArgs,
+	}
+	commands["set"] = command{
+		help:  "set SCHEMA KEY VALUE like gsettings set; VALUE may be one accelerator",
+		flags: modelFlags,
+		run:   runSet,
+		args:  setArgs,
+	}
+	commands["disable"] = command{
+		help: "unbind whatever fires on each accelerator given",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&disableOpt.dryRun, "dry-run", false, "print the gsettings commands instead of running them")
+			modelFlags(fs)
+		},
+		run:  runDisable,
+		args: accelArgs,
+	}
+}
+
+func loadModel() (*model, error) {
+	m := &model{lbl: labels("text")}
+	return m, m.load()
+}
+
+func runExplain(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: explain ACCEL…")
+	}
+	m, err := loadModel()
+	if err != nil {
+		return err
+	}
+	for _, q := range args {
+		if err := m.explain(q); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func runSet(args []string) error {
+	if len(args) != 3 {
+		return errors.New("usage: set SCHEMA KEY VALUE")
+	}
+	schema, key, val := args[0], args[1], args[2]
+	m, err := loadModel()
+	if err != nil {
+		return err
+	}
+	if _, ok := parseAccel(val); ok && !strings.ContainsAny(val[:1], "['@") {
+		switch old := gsettingsGet(schema, key); {
+		case strings.HasPrefix(old, "[") || strings.HasPrefix(old, "@as"):
+			val = gvText("as", []any{val})
+		case strings.HasPrefix(old, "'"):
+			val = gvText("s", val)
+		}
+	}
+	return m.set(schema, key, val)
+}
+
+func runDisable(args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: disable ACCEL…")
+	}
+	m, err := loadModel()
+	if err != nil {
+		return err
+	}
+	for _, q := range args {
+		a, ok := parseAccel(q)
+		if !ok {
+			return fmt.Errorf("not an accelerator: %q", q)
+		}
+		w, ok := m.won[a.spec()]
+		switch {
+		case !ok:
+			fmt.Printf("%s is free\n", a.spec())
+			continue
+		case w.schema == "":
+			return fmt.Errorf("%s: %s: %s is not held by a gsettings key", q, w.app, w.action)
+		case disableOpt.dryRun:
+			fmt.Println(unbindCmd(w))
+			continue
+		}
+		if err := m.set(w.schema, w.key, unbindValue(w)); err != nil {
+			return err
+		}
+		if n, ok := m.won[a.spec()]; ok {
+			fmt.Printf("  %s now fires %s: %s\n", n.accel, n.app, n.action)
+		}
+	}
+	return nil
+}
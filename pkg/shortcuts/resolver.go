@@ -0,0 +1,167 @@
+package shortcuts
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+/*──────────────── resolver strategies ───────────
+
+A resolver decides which of two claimants of the
+same chord fires.
+
+  gschema   Mutter's theory: family rank, then the
+            key's position in its gschema file
+  runtime   what was seen to fire, read from
+            observed.json in the state dir, which
+            `observe` writes; unobserved chords, or
+            all of them without the file, fall back
+            to gschema
+
+observed.json is a list of
+  {"accel": "<Super>h", "schema": "…", "key": "minimize"}
+one entry per chord, last observation wins.
+*/
+
+type resolver interface {
+	wins(a, b row) bool // a fires instead of b
+}
+
+type gschemaResolver struct{}
+
+func (gschemaResolver) wins(a, b row) bool {
+	return a.rank < b.rank || (a.rank == b.rank && a.order < b.order)
+}
+
+type runtimeResolver struct {
+	seen map[string]string // canonical spec → "schema key"
+}
+
+type observation struct {
+	Accel  string `json:"accel"`
+	Schema string `json:"schema"`
+	Key    string `json:"key"`
+}
+
+func observedPath() string { return filepath.Join(stateDir(), "observed.json") }
+
+func loadObservations() (map[string]string, error) {
+	data, err := os.ReadFile(observedPath())
+	if err != nil {
+		return nil, err
+	}
+	var obs []observation
+	if err := json.Unmarshal(data, &obs); err != nil {
+		return nil, fmt.Errorf("%s: %w", observedPath(), err)
+	}
+	seen := map[string]string{}
+	for _, o := range obs {
+		if a, ok := parseAccel(o.Accel); ok {
+			seen[a.spec()] = currentKey(o.Schema + " " + o.Key)
+		}
+	}
+	return seen, nil
+}
+
+func (r *runtimeResolver) wins(a, b row) bool {
+	if r.seen == nil {
+		seen, err := loadObservations()
+		if err != nil {
+			warn(fmt.Errorf("runtime resolver: %w; using gschema order", err))
+			seen = map[string]string{}
+		}
+		r.seen = seen
+	}
+	if acc, ok := parseAccel(a.spec); ok {
+		if fired, ok := r.seen[acc.spec()]; ok {
+			switch fired {
+			case a.schema + " " + a.key:
+				return true
+			case b.schema + " " + b.key:
+				return false
+			}
+		}
+	}
+	return gschemaResolver{}.wins(a, b)
+}
+
+var resolvers = map[string]func() resolver{
+	"gschema": func() resolver { return gschemaResolver{} },
+	"runtime": func() resolver { return &runtimeResolver{} },
+}
+
+var resolverName = "gschema"
+
+func resolverNames() string {
+	var n []string
+	for k := range resolvers {
+		n = append(n, k)
+	}
+	sort.Strings(n)
+	return strings.Join(n, ", ")
+}
+
+// collectFlags registers the options that change how rows are collected
+// and resolved, shared by every command that calls collect.
+func collectFlags(fs *flag.FlagSet) {
+	fs.StringVar(&resolverName, "resolver", resolverName, "conflict resolution strategy: "+resolverNames())
+	fs.BoolFunc("no-core-override", "do not force the built-in core shortcuts over gsettings", func(string) error {
+		coreOverride = false
+		return nil
+	})
+	fs.BoolVar(&defaultsOnly, "defaults", false, "use schema defaults only; no gsettings calls (chroots, CI)")
+	fs.BoolVar(&includeMedia, "include-media-keys", false, "also list XF86 media/Fn keys and the media-keys schema")
+	fs.BoolVar(&includeTerminals, "terminals", includeTerminals, "collect GNOME Terminal and Console shortcuts")
+	fs.BoolVar(&includeApps, "include-apps", false, "list app-local shortcuts: Files, Settings, Text Editor and GTK accels files")
+	fs.Func("desktop", "where bindings come from: "+strings.Join(desktopNames(), ", "), func(v string) error {
+		if _, ok := desktops[v]; !ok && v != "gnome" {
+			return fmt.Errorf("want one of %s", strings.Join(desktopNames(), ", "))
+		}
+		desktopOpt = v
+		desktopList = append(desktopList, v)
+		return nil
+	})
+	fs.StringVar(&wmConfig, "wm-config", "", "config file for a -desktop other than gnome (default: its usual place)")
+	fs.Func("host", "collect from user@machine over ssh", func(v string) error {
+		if err := checkHost(v); err != nil {
+			return err
+		}
+		hostOpt = v
+		return nil
+	})
+	fs.Func("backend", "how settings are read: auto (default), native (dconf files) or gsettings (the tool)", func(v string) error {
+		switch v {
+		case "auto", "native", "gsettings":
+			backendOpt = v
+			return nil
+		}
+		return fmt.Errorf("want auto, native or gsettings")
+	})
+	fs.Func("from-dump", "read settings from a saved `gsettings list-recursively` FILE, nothing from this machine", func(v string) (err error) {
+		dumpOpt, err = readDump(v)
+		return err
+	})
+	fs.StringVar(&schemaDirOpt, "schema-dir", "", "look for schemas only in these dirs (colon-separated), not the usual ones")
+	fs.StringVar(&shellVersionOpt, "shell-version", shellVersionOpt, "GNOME Shell version for its built-in screen keys: auto or e.g. 46")
+	fs.Func("session", "display backend: auto (XDG_SESSION_TYPE, default), wayland, x11 or all", func(v string) error {
+		switch v {
+		case "auto", "wayland", "x11", "all":
+			sessionOpt = v
+			return nil
+		}
+		return fmt.Errorf("want auto, wayland, x11 or all")
+	})
+}
+
+func activeResolver() (resolver, error) {
+	mk, ok := resolvers[resolverName]
+	if !ok {
+		return nil, fmt.Errorf("resolver %q: want one of %s", resolverName, resolverNames())
+	}
+	return mk(), nil
+}
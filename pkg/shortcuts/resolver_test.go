@@ -0,0 +1,72 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestObserveRuntime records that Toggle Overview fires on the
+// fixture's contested Ctrl+Shift+Q: the runtime resolver follows it,
+// gschema order does not, and without observed.json runtime is gschema.
+func TestObserveRuntime(t *testing.T) {
+	dir := filepath.Join("testdata", "golden", "gnome46-wayland")
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+	savedDump, savedDir, savedCache, savedWarn := dumpOpt, schemaDirOpt, schemaCache, warnOut
+	t.Cleanup(func() { dumpOpt, schemaDirOpt, schemaCache, warnOut = savedDump, savedDir, savedCache, savedWarn })
+	d, err := readDump(filepath.Join(dir, "dump.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpOpt, schemaDirOpt, schemaCache = d, filepath.Join(dir, "schemas"), map[string]*schemaInfo{}
+	var warnings strings.Builder
+	warnOut = &warnings
+	lbl := labelsFor(kbPC, "text")
+	const chord = "<Control><Shift>q"
+
+	winner := func(resolver string) string {
+		t.Helper()
+		won, err := resolveWith(resolver, lbl)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return won[chord].key
+	}
+	if got := winner("runtime"); got != "panel-run-dialog" {
+		t.Errorf("runtime without observed.json: %s fires, want gschema's panel-run-dialog", got)
+	}
+	if !strings.Contains(warnings.String(), "using gschema order") {
+		t.Errorf("warnings = %q, want the missing file reported", warnings.String())
+	}
+
+	stdout, _ := capture(t, func() {
+		if err := runObserve([]string{"<Shift><Primary>q", "2"}); err != nil {
+			t.Error(err)
+		}
+	})
+	if !strings.Contains(stdout, "fires GNOME Shell: Toggle Overview") {
+		t.Errorf("observe printed %q", stdout)
+	}
+	data, err := os.ReadFile(observedPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `[
+  {
+    "accel": "<Control><Shift>q",
+    "schema": "org.gnome.shell.keybindings",
+    "key": "toggle-overview"
+  }
+]
+`
+	if string(data) != want {
+		t.Errorf("observed.json =\n%s\nwant\n%s", data, want)
+	}
+	if got := winner("runtime"); got != "toggle-overview" {
+		t.Errorf("runtime: %s fires, want the observed toggle-overview", got)
+	}
+	if got := winner("gschema"); got != "panel-run-dialog" {
+		t.Errorf("gschema: %s fires, want panel-run-dialog", got)
+	}
+}
@@ -0,0 +1,67 @@
+package shortcuts
+
+import (
+	"os"
+	"strings"
+)
+
+/*──────────────── Wayland / X11 ─────────────────
+
+Some bindings are only grabbed by one display
+backend.  In a known session the others cannot
+fire, so they are not collected (and shadow
+nothing); with -session all, or when the type is
+unknown, they are kept and tagged instead.
+*/
+
+var sessionOpt = "auto"
+
+// backendOnly maps a schema, or "schema key", to the backend that
+// honours it.
+var backendOnly = map[string]string{
+	"org.gnome.mutter.wayland.keybindings": "wayland",
+}
+
+var backendNames = map[string]string{"wayland": "Wayland", "x11": "X11"}
+
+func backendFor(schema, key string) string {
+	if i := strings.IndexByte(schema, ':'); i >= 0 {
+		schema = schema[:i]
+	}
+	if b, ok := backendOnly[schema+" "+key]; ok {
+		return b
+	}
+	return backendOnly[schema]
+}
+
+// sessionType is "wayland", "x11" or "all" (unknown / show everything).
+func sessionType() string {
+	if sessionOpt != "auto" {
+		return sessionOpt
+	}
+	switch t := strings.ToLower(os.Getenv("XDG_SESSION_TYPE")); t {
+	case "wayland", "x11":
+		return t
+	}
+	return "all"
+}
+
+// sessionFilter reports whether a binding can fire here and, when the
+// session is not narrowed down, the tag to append to its action.
+func sessionFilter(schema, key string) (keep bool, tag string) {
+	return backendFilter(backendFor(schema, key))
+}
+
+// backendFilter is sessionFilter for a binding only backend b grabs.
+func backendFilter(b string) (keep bool, tag string) {
+	if b == "" {
+		return true, ""
+	}
+	switch sessionType() {
+	case "all":
+		return true, " [" + backendNames[b] + " only]"
+	case b:
+		return true, ""
+	}
+	return false, ""
+}
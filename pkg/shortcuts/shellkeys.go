@@ -0,0 +1,137 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/*──────────── gnome-shell's own keys ────────────
+
+Keys handled inside shell screens (screenshot UI,
+app switcher, overview) per shell version, from
+shell_shortcuts.tsv.  The version is taken from
+`gnome-shell --version` unless -shell-version
+names one.  Without a shell (Cinnamon, MATE)
+there are none; with -defaults the entries still
+current (no upper bound) are used.
+*/
+
+//go:embed shell_shortcuts.tsv
+var shellShortcutsTSV string
+
+var shellVersionOpt = "auto"
+
+// version is major.minor; GNOME 40+ has no minor that matters here.
+type version [2]int
+
+// parseVersion reads "46.2", "3.38.4" or a pre-release such as "47.rc"
+// or "47.beta", which counts as 47.0.
+func parseVersion(s string) (version, bool) {
+	var v version
+	for i, f := range strings.SplitN(strings.TrimSpace(s), ".", 3) {
+		if i > 1 {
+			break
+		}
+		digits := strings.TrimRightFunc(f, func(r rune) bool { return r < '0' || r > '9' }) // "47.rc", "46.0beta"
+		n, err := strconv.Atoi(digits)
+		if err != nil {
+			return v, i > 0 // a pre-release keeps the major already read
+		}
+		v[i] = n
+	}
+	return v, s != ""
+}
+
+func (v version) less(w version) bool {
+	return v[0] < w[0] || v[0] == w[0] && v[1] < w[1]
+}
+
+type shellBind struct {
+	from, to             version
+	open                 bool // no upper bound
+	screen, spec, action string
+}
+
+func parseShellBinds(file, data string) []shellBind {
+	var out []shellBind
+	for n, l := range strings.Split(data, "\n") {
+		if l == "" || strings.HasPrefix(l, "#") {
+			continue
+		}
+		f := strings.Split(l, "\t")
+		if len(f) < 4 {
+			badLine(file, n, "want versions<TAB>screen<TAB>spec<TAB>action, got %q", l)
+			continue
+		}
+		lo, hi, _ := strings.Cut(f[0], "-")
+		b := shellBind{screen: f[1], spec: f[2], action: f[3], open: hi == ""}
+		var ok bool
+		if b.from, ok = parseVersion(lo); !ok {
+			badLine(file, n, "bad version range %q", f[0])
+			continue
+		}
+		if !b.open {
+			if b.to, ok = parseVersion(hi); !ok {
+				badLine(file, n, "bad version range %q", f[0])
+				continue
+			}
+		}
+		out = append(out, b)
+	}
+	return out
+}
+
+func loadShellShortcuts() []shellBind {
+	cfg, _ := os.UserConfigDir()
+	file := filepath.Join(cfg, "gnome-shortcuts", "shell_shortcuts.tsv")
+	if data, err := os.ReadFile(file); err == nil {
+		return parseShellBinds(file, string(data))
+	}
+	return parseShellBinds("shell_shortcuts.tsv", shellShortcutsTSV)
+}
+
+// shellVersion parses "GNOME Shell 46.2".
+func shellVersion() (version, bool) {
+	s := shellVersionOpt
+	if s == "auto" {
+		if defaultsOnly || fromDump() {
+			return version{}, false
+		}
+		ctx, cancel := toolContext()
+		defer cancel()
+		out, err := toolCmd(ctx, "gnome-shell", "--version").Output()
+		if err != nil {
+			diagnose(fmt.Errorf("gnome-shell --version: %w; screen-local keys left out", err))
+			return version{}, false
+		}
+		f := strings.Fields(string(out))
+		if len(f) == 0 {
+			return version{}, false
+		}
+		s = f[len(f)-1]
+	}
+	return parseVersion(s)
+}
+
+// shellRows lists the screen-local keys; they claim no chord.
+func shellRows(lbl map[string]string) []row {
+	v, known := shellVersion()
+	if !known && !defaultsOnly && !fromDump() {
+		return nil
+	}
+	var out []row
+	for i, b := range loadShellShortcuts() {
+		if known && (v.less(b.from) || !b.open && b.to.less(v)) || !known && !b.open {
+			continue
+		}
+		if acc, ok := fmtKey(b.spec, lbl); ok {
+			out = append(out, row{accel: acc, app: b.screen, action: b.action,
+				rank: 1, order: 1<<20 + i, keySpecs: []string{b.spec}})
+		}
+	}
+	return out
+}
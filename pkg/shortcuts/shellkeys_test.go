@@ -0,0 +1,26 @@
+package shortcuts
+
+import "testing"
+
+func TestParseVersion(t *testing.T) {
+	for _, c := range []struct {
+		in   string
+		want version
+		ok   bool
+	}{
+		{"46", version{46, 0}, true},
+		{"46.2", version{46, 2}, true},
+		{"3.38.4", version{3, 38}, true},
+		{"47.rc", version{47, 0}, true},
+		{"47.beta", version{47, 0}, true},
+		{"47.alpha.1", version{47, 0}, true},
+		{"46.0beta", version{46, 0}, true},
+		{" 45.1\n", version{45, 1}, true},
+		{"", version{}, false},
+		{"rc", version{}, false},
+	} {
+		if got, ok := parseVersion(c.in); got != c.want || ok != c.ok {
+			t.Errorf("parseVersion(%q) = %v, %v; want %v, %v", c.in, got, ok, c.want, c.ok)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package shortcuts
+
+/*─────────── free alternatives for losers ───────
+
+For a shadowed chord we try, in order: the same key
+with one more (or one different) modifier, then the
+same modifiers on a neighbouring key.  Anything not
+claimed by some binding is free; keys the keyboard
+model lacks are left out and those behind its Fn
+key come last (models.go).
+*/
+
+// US QWERTY rows as keysym names; neighbours are left/right in a row.
+var qwertyRows = [][]string{
+	{"F1", "F2", "F3", "F4", "F5", "F6", "F7", "F8", "F9", "F10", "F11", "F12"},
+	{"grave", "1", "2", "3", "4", "5", "6", "7", "8", "9", "0", "minus", "equal"},
+	{"q", "w", "e", "r", "t", "y", "u", "i", "o", "p", "bracketleft", "bracketright", "backslash"},
+	{"a", "s", "d", "f", "g", "h", "j", "k", "l", "semicolon", "apostrophe"},
+	{"z", "x", "c", "v", "b", "n", "m", "comma", "period", "slash"},
+}
+
+func neighbours(key string) []string {
+	for _, r := range qwertyRows {
+		for i, k := range r {
+			if k != key {
+				continue
+			}
+			var out []string
+			if i+1 < len(r) {
+				out = append(out, r[i+1])
+			}
+			if i > 0 {
+				out = append(out, r[i-1])
+			}
+			return out
+		}
+	}
+	return nil
+}
+
+const grabMods = modCtrl | modAlt | modSuper // one of these makes a global chord
+
+func suggestFree(a accel, taken map[string]bool, n int) []accel {
+	var cands []accel
+	for _, m := range []int{modShift, modCtrl, modAlt, modSuper} {
+		if a.mods&m == 0 {
+			cands = append(cands, accel{a.mods | m, a.key})
+		}
+	}
+	for _, m := range []int{modCtrl, modAlt, modSuper} {
+		if a.mods&m != 0 {
+			for _, o := range []int{modSuper, modAlt, modCtrl} {
+				if a.mods&o == 0 {
+					cands = append(cands, accel{a.mods&^m | o, a.key})
+				}
+			}
+		}
+	}
+	for _, k := range neighbours(a.key) {
+		cands = append(cands, accel{a.mods, k})
+	}
+
+	m := keyboardModel()
+	var out, fn []accel
+	seen := map[string]bool{}
+	for _, c := range cands {
+		s := c.spec()
+		if c.mods&grabMods == 0 || c.key == "" || taken[s] || seen[s] || !m.has(c.key) {
+			continue
+		}
+		seen[s] = true
+		if m.behindFn(c.key) {
+			fn = append(fn, c)
+		} else {
+			out = append(out, c)
+		}
+	}
+	out = append(out, fn...)
+	return out[:min(n, len(out))]
+}
@@ -0,0 +1,49 @@
+package shortcuts
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSuggestFree(t *testing.T) {
+	for _, c := range []struct {
+		name, layout, accel string
+		taken               []string
+		n                   int
+		want                []string
+	}{
+		{"extra modifier first", "pc", "<Super>q", []string{"<Shift><Super>q"}, 3,
+			[]string{"<Control><Super>q", "<Alt><Super>q", "<Alt>q"}},
+		{"then swapped modifiers and neighbours", "pc", "<Super>q", nil, 10,
+			[]string{"<Shift><Super>q", "<Control><Super>q", "<Alt><Super>q", "<Alt>q", "<Control>q", "<Super>w"}},
+		{"no chord without Ctrl, Alt or Super", "pc", "<Shift>q", nil, 10,
+			[]string{"<Control><Shift>q", "<Alt><Shift>q", "<Shift><Super>q"}},
+		{"Fn keys last", "60", "<Super>1", nil, 10,
+			[]string{"<Shift><Super>1", "<Control><Super>1", "<Alt><Super>1", "<Alt>1", "<Control>1", "<Super>2", "<Super>grave"}},
+		{"keys the model lacks left out", "thinkpad", "<Super>Menu", nil, 10, nil},
+	} {
+		t.Run(c.name, func(t *testing.T) {
+			t.Setenv("KEY_LAYOUT", c.layout)
+			a, ok := parseAccel(c.accel)
+			if !ok {
+				t.Fatalf("parseAccel(%q) failed", c.accel)
+			}
+			taken := map[string]bool{}
+			for _, s := range c.taken {
+				b, _ := parseAccel(s)
+				taken[b.spec()] = true
+			}
+			var want, got []string
+			for _, s := range c.want {
+				b, _ := parseAccel(s)
+				want = append(want, b.spec())
+			}
+			for _, f := range suggestFree(a, taken, c.n) {
+				got = append(got, f.spec())
+			}
+			if !slices.Equal(got, want) {
+				t.Errorf("suggestFree(%s) = %q, want %q", c.accel, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package shortcuts
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+/*────────────────── Sway / i3 ───────────────────
+
+-desktop sway|i3 reads the WM's config instead of
+gsettings:
+
+	set $mod Mod4
+	bindsym $mod+Shift+q kill
+	bindcode --release 107 exec grim
+	mode "resize" {
+		bindsym Left resize shrink width 10px
+	}
+	include ~/.config/sway/config.d/*
+
+Variables are expanded, includes followed and
+options set aside (import reads them).  A chord bound twice keeps the
+later line, as Sway does; each mode is separate.
+*/
+
+func init() {
+	desktops["sway"] = func(lbl map[string]string) []row { return tilingRows("Sway", "sway", lbl) }
+	desktops["i3"] = func(lbl map[string]string) []row { return tilingRows("i3", "i3", lbl) }
+	exporters["sway"] = exporter{"sway/i3 bindsym lines", writeSway}
+}
+
+var tilingMods = map[string]string{
+	"mod4": "<Super>", "super": "<Super>",
+	"mod1": "<Alt>", "alt": "<Alt>",
+	"shift":   "<Shift>",
+	"control": "<Control>", "ctrl": "<Control>",
+}
+
+// tilingConfig is ~/.config/<wm>/config, else ~/.<wm>/config.
+func tilingConfig(wm string) string {
+	if wmConfig != "" {
+		return wmConfig
+	}
+	cfg, _ := os.UserConfigDir()
+	home, _ := os.UserHomeDir()
+	def := filepath.Join(cfg, wm, "config")
+	for _, p := range []string{def, filepath.Join(home, "."+wm, "config")} {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return def
+}
+
+type tilingParser struct {
+	configFiles
+	binds []tilingBind
+}
+
+func (p *tilingParser) file(path string) error {
+	f, err := p.open(path)
+	if f == nil {
+		return err
+	}
+	defer f.Close()
+
+	var blocks []string // "mode NAME", "bind bindsym" or "other"
+	mode := func() string {
+		for i := len(blocks) - 1; i >= 0; i-- {
+			if m, ok := strings.CutPrefix(blocks[i], "mode "); ok {
+				return m
+			}
+		}
+		return ""
+	}
+	sc := bufio.NewScanner(f)
+	n, cont := 0, ""
+	for sc.Scan() {
+		n++
+		l := strings.TrimSpace(sc.Text())
+		if strings.HasSuffix(l, "\\") {
+			cont += strings.TrimSuffix(l, "\\") + " "
+			continue
+		}
+		l, cont = strings.TrimSpace(cont+l), ""
+		if l == "" || l[0] == '#' {
+			continue
+		}
+		if l == "}" {
+			if len(blocks) > 0 {
+				blocks = blocks[:len(blocks)-1]
+			}
+			continue
+		}
+		if w := strings.Fields(l); w[0] == "set" && len(w) >= 3 && strings.HasPrefix(w[1], "$") {
+			p.vars[w[1]] = p.expand(strings.Join(w[2:], " ")) // not the name: $mod would eat $mod2
+			continue
+		}
+		w := strings.Fields(p.expand(l))
+		if len(blocks) > 0 && strings.HasPrefix(blocks[len(blocks)-1], "bind ") {
+			w = append(strings.Fields(strings.TrimPrefix(blocks[len(blocks)-1], "bind ")), w...)
+		}
+		src := fmt.Sprintf("%s:%d", path, n)
+		switch {
+		case w[len(w)-1] == "{":
+			switch w[0] {
+			case "mode":
+				args := w[1 : len(w)-1]
+				for len(args) > 0 && strings.HasPrefix(args[0], "--") { // --pango_markup
+					args = args[1:]
+				}
+				blocks = append(blocks, "mode "+strings.Trim(strings.Join(args, " "), `"'`))
+			case "bindsym", "bindcode": // with its options
+				blocks = append(blocks, "bind "+strings.Join(w[:len(w)-1], " "))
+			default:
+				blocks = append(blocks, "other")
+			}
+		case w[0] == "include" && len(w) >= 2:
+			p.include(path, strings.Join(w[1:], " "), p.file)
+		case w[0] == "bindsym" || w[0] == "bindcode":
+			var args, opts []string
+			for _, x := range w[1:] {
+				if strings.HasPrefix(x, "--") && len(args) == 0 {
+					opts = append(opts, x)
+				} else {
+					args = append(args, x)
+				}
+			}
+			if len(args) < 2 {
+				continue
+			}
+			if spec, ok := tilingSpec(args[0], w[0] == "bindcode"); ok {
+				p.binds = append(p.binds, tilingBind{mode(), spec, strings.Join(args[1:], " "), src, opts})
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// tilingSpec turns "Mod4+Shift+q" into "<Super><Shift>q".
+func tilingSpec(combo string, code bool) (string, bool) {
+	parts := strings.Split(combo, "+")
+	var b strings.Builder
+	for _, m := range parts[:len(parts)-1] {
+		t, ok := tilingMods[strings.ToLower(m)]
+		if !ok {
+			return "", false
+		}
+		b.WriteString(t)
+	}
+	key := parts[len(parts)-1]
+	if code {
+		key = "Keycode_" + key
+	}
+	if key == "" {
+		return "", false
+	}
+	b.WriteString(key)
+	return b.String(), true
+}
+
+func tilingRows(app, wm string, lbl map[string]string) []row {
+	p := &tilingParser{configFiles: newConfigFiles()}
+	if err := p.file(tilingConfig(wm)); err != nil {
+		warn(err)
+	}
+	return desktopClaim(bindRows(app, p.binds, lbl))
+}
+
+/*──────── export -format sway ────────*/
+
+var swayMods = []struct {
+	bit  int
+	name string
+}{{modSuper, "$mod"}, {modCtrl, "Ctrl"}, {modAlt, "Mod1"}, {modShift, "Shift"}}
+
+// swayCombo turns "<Super><Shift>q" into bindsym "$mod+Shift+q".
+func swayCombo(spec string) (bind, combo string, ok bool) {
+	a, ok := parseAccel(spec)
+	if !ok || a.key == "" || a.mods&(modHyper|modMeta) != 0 {
+		return "", "", false
+	}
+	var parts []string
+	for _, m := range swayMods {
+		if a.mods&m.bit != 0 {
+			parts = append(parts, m.name)
+		}
+	}
+	bind, key := "bindsym", a.key
+	if n, ok := strings.CutPrefix(key, "Keycode_"); ok {
+		bind, key = "bindcode", n
+	}
+	return bind, strings.Join(append(parts, key), "+"), true
+}
+
+func writeSway(w io.Writer, rows []row, _ map[string]string) error {
+	actions := wmActions()
+	fmt.Fprint(w, "# Generated by `gnome-shortcuts export -format sway` from the resolved GNOME shortcuts.\n"+
+		"set $mod Mod4\nset $term foot\nset $menu wofi --show drun\n")
+	var skipped []string
+	for _, r := range rows {
+		cmd, ok := wmCommand(r, 0, "exec %s", actions)
+		bind, combo, ok2 := swayCombo(r.spec)
+		if !ok || !ok2 {
+			skipped = append(skipped, exportComment(r))
+			continue
+		}
+		fmt.Fprintf(w, "\n%s\n%s %s %s\n", exportComment(r), bind, combo, cmd)
+	}
+	if len(skipped) > 0 {
+		fmt.Fprintf(w, "\n# No sway equivalent:\n%s\n", strings.Join(skipped, "\n"))
+	}
+	return nil
+}
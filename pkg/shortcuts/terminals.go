@@ -0,0 +1,59 @@
+package shortcuts
+
+import _ "embed"
+
+/*────────────── terminal emulators ──────────────
+
+Copy, paste and tab chords of the terminals are
+the usual victims of system shortcuts, so they
+are collected as rank 4: any system binding on
+the same chord shadows them.
+
+  gnome-terminal  relocatable Legacy.Keybindings
+                  schema ('disabled' = unset)
+  Console (kgx)   compiled in; console_shortcuts.tsv
+*/
+
+const appRank = 4
+
+var includeTerminals = true
+
+//go:embed console_shortcuts.tsv
+var consoleShortcutsTSV string
+
+var consoleShortcuts = parseStaticBinds("console_shortcuts.tsv", consoleShortcutsTSV)
+
+func terminalRows(lbl map[string]string) []row {
+	if !includeTerminals {
+		return nil
+	}
+	var out []row
+	si := lookupSchema(terminalKeys)
+	for _, e := range parseDump(gsettingsDump(terminalKeys)) {
+		spec := gvTextString(e.val)
+		if spec == "" || spec == "disabled" {
+			continue
+		}
+		acc, ok := fmtAccel(spec, lbl)
+		if !ok {
+			continue
+		}
+		ord, ok := si.order[e.key]
+		if !ok {
+			ord = 1 << 20
+		}
+		out = append(out, row{accel: acc, app: "Terminal", action: humanise(e.key),
+			rank: appRank, order: ord, spec: spec, schema: terminalKeys, key: e.key,
+			keySpecs: []string{spec}})
+	}
+	if lookupSchema("org.gnome.Console").file == "" {
+		return out
+	}
+	for i, b := range consoleShortcuts {
+		if acc, ok := fmtAccel(b.spec, lbl); ok {
+			out = append(out, row{accel: acc, app: "Console", action: b.action,
+				rank: appRank, order: i, spec: b.spec})
+		}
+	}
+	return out
+}
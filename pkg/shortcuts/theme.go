@@ -0,0 +1,223 @@
+package shortcuts
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+/*────────────────────── themes ──────────────────────
+
+Which colours the text table, the conflict report
+and browse use.  dark is the default and suits a
+dark terminal; light keeps off the yellows a white
+background swallows; high-contrast is bold and
+bright throughout.  config.toml picks one and may
+repaint any part of it:
+
+	[theme]
+	base     = "light"
+	families = ["blue", "green", "magenta", "#aa5500"]
+	selected = "bold white on-blue"
+
+A colour is words: black red green yellow blue
+magenta cyan white, each with bright- for the
+light shade and on- for the background, a 256
+palette number, #rrggbb, or bold dim italic
+underline reverse.  -theme overrides base.
+*/
+
+// theme holds SGR parameters for each thing painted.
+type theme struct {
+	families  []string // one per application family, picked by hash
+	modifiers string   // Ctrl, Shift… in a chord
+	key       string   // the chord's key
+	conflict  string   // the key of a chord that shadows others, and ✘
+	winner    string   // ✔ in the conflict report
+	selected  string   // browse's selected row
+	header    string   // browse's column heads
+	match     string   // what a filter matched
+}
+
+var themes = map[string]theme{
+	"dark": {
+		families:  []string{sgrBlue, sgrGreen, sgrMagenta, sgrYellow, sgrRed}, // cyan is for modifiers
+		modifiers: sgrCyan,
+		key:       sgrBold,
+		conflict:  sgrBold + ";" + sgrRed,
+		winner:    sgrGreen,
+		selected:  "7",
+		header:    "7",
+		match:     "30;43",
+	},
+	"light": {
+		families:  []string{"38;5;25", "38;5;28", "38;5;90", "38;5;130", "38;5;124"},
+		modifiers: "38;5;30",
+		key:       sgrBold,
+		conflict:  "1;38;5;160",
+		winner:    "38;5;28",
+		selected:  "7",
+		header:    "1;7",
+		match:     "30;48;5;153",
+	},
+	"high-contrast": {
+		families:  []string{"1;94", "1;92", "1;95", "1;93", "1;91"},
+		modifiers: "1;96",
+		key:       "1;4",
+		conflict:  "1;97;41",
+		winner:    "1;92",
+		selected:  "1;30;107",
+		header:    "1;4;7",
+		match:     "1;30;103",
+	},
+}
+
+var themeName string // -theme, else config.toml's base
+
+func themeFlag(fs *flag.FlagSet) {
+	fs.Func("theme", "colours: "+strings.Join(sortedKeys(themes), ", ")+" (default dark, or config.toml's)", func(s string) error {
+		if _, ok := themes[s]; !ok {
+			return fmt.Errorf("want %s", strings.Join(sortedKeys(themes), ", "))
+		}
+		themeName = s
+		return nil
+	})
+}
+
+// palette is the theme in use: -theme or config.toml's base, with
+// config.toml's [theme] laid over it.
+func palette() theme {
+	t := themes["dark"]
+	if b, ok := themes[cmp.Or(themeName, userPrefs.theme.base)]; ok {
+		t = b
+	}
+	if fs := userPrefs.theme.families; len(fs) > 0 {
+		t.families = fs
+	}
+	for k, v := range userPrefs.theme.parts {
+		*t.part(k) = v
+	}
+	return t
+}
+
+// part is the field config.toml calls name, or nil.
+func (t *theme) part(name string) *string {
+	switch name {
+	case "modifiers":
+		return &t.modifiers
+	case "key":
+		return &t.key
+	case "conflict":
+		return &t.conflict
+	case "winner":
+		return &t.winner
+	case "selected":
+		return &t.selected
+	case "header":
+		return &t.header
+	case "match":
+		return &t.match
+	}
+	return nil
+}
+
+// themePrefs is config.toml's [theme], its colours already SGR.
+type themePrefs struct {
+	base     string
+	families []string
+	parts    map[string]string
+}
+
+func (tp *themePrefs) set(key string, v any) error {
+	want := func(typ string) error { return fmt.Errorf("theme.%s: want %s, got %v", key, typ, v) }
+	switch key {
+	case "base":
+		s, ok := v.(string)
+		if _, known := themes[s]; !ok || !known {
+			return want(strings.Join(sortedKeys(themes), ", "))
+		}
+		tp.base = s
+	case "families":
+		items, _ := v.([]any)
+		if len(items) == 0 {
+			return want("an array of colours")
+		}
+		tp.families = nil
+		for _, it := range items {
+			s, _ := it.(string)
+			code, err := sgrOf(s)
+			if err != nil {
+				return fmt.Errorf("theme.families: %w", err)
+			}
+			tp.families = append(tp.families, code)
+		}
+	default:
+		if (&theme{}).part(key) == nil {
+			return fmt.Errorf("unknown setting theme.%s", key)
+		}
+		s, ok := v.(string)
+		if !ok {
+			return want("a colour")
+		}
+		code, err := sgrOf(s)
+		if err != nil {
+			return fmt.Errorf("theme.%s: %w", key, err)
+		}
+		if tp.parts == nil {
+			tp.parts = map[string]string{}
+		}
+		tp.parts[key] = code
+	}
+	return nil
+}
+
+var colorNames = []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+
+var sgrAttrs = map[string]string{"bold": "1", "dim": "2", "italic": "3", "underline": "4", "reverse": "7"}
+
+// sgrOf turns "bold bright-white on-blue" into SGR parameters.
+func sgrOf(s string) (string, error) {
+	var out []string
+	for _, w := range strings.Fields(strings.ToLower(s)) {
+		layer, word := "38", w // foreground
+		if c, ok := strings.CutPrefix(word, "on-"); ok {
+			layer, word = "48", c
+		}
+		bright := false
+		if c, ok := strings.CutPrefix(word, "bright-"); ok {
+			bright, word = true, c
+		}
+		switch i := slices.Index(colorNames, word); {
+		case sgrAttrs[word] != "" && layer == "38" && !bright:
+			out = append(out, sgrAttrs[word])
+		case i >= 0:
+			base := 30
+			if layer == "48" {
+				base = 40
+			}
+			if bright {
+				base += 60
+			}
+			out = append(out, strconv.Itoa(base+i))
+		case !bright && strings.HasPrefix(word, "#") && len(word) == 7:
+			rgb, err := strconv.ParseUint(word[1:], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("bad colour %q", w)
+			}
+			out = append(out, fmt.Sprintf("%s;2;%d;%d;%d", layer, rgb>>16, rgb>>8&0xff, rgb&0xff))
+		default:
+			n, err := strconv.Atoi(word)
+			if bright || err != nil || n < 0 || n > 255 {
+				return "", fmt.Errorf("unknown colour %q", w)
+			}
+			out = append(out, fmt.Sprintf("%s;5;%d", layer, n))
+		}
+	}
+	if len(out) == 0 {
+		return "", fmt.Errorf("empty colour")
+	}
+	return strings.Join(out, ";"), nil
+}
@@ -0,0 +1,99 @@
+package shortcuts
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"time"
+)
+
+/*─────────────── shortcut of the day ──────────────
+
+One binding worth knowing, picked at random but
+the same all day, so a line in ~/.profile or a
+login notification does not change on every
+shell.  Left out are the essentials (audit.go)
+that everyone meets, shortcuts the quiz has
+marked learned, and keys of their own (media,
+power).  Bindings whose schema says what they do
+come first.
+*/
+
+var tipNotify bool
+
+func init() {
+	commands["tip"] = command{
+		help: "one lesser-known shortcut, a new one each day",
+		flags: func(fs *flag.FlagSet) {
+			fs.BoolVar(&tipNotify, "notify", false, "show it as a desktop notification (notify-send) instead of printing it")
+			collectFlags(fs)
+			displayFlags(fs)
+		},
+		run: runTip,
+	}
+}
+
+// tipOf picks the day's binding from rows.
+func tipOf(rows []row, p *progress, day time.Time) (row, string, bool) {
+	known := map[string]bool{}
+	for _, e := range essentials {
+		known[e.schema+" "+e.key] = true
+	}
+	var described, rest []row
+	var about []string
+	for _, r := range rows {
+		a, ok := parseAccel(r.spec)
+		if !ok || a.key == "" || strings.HasPrefix(a.key, "XF86") ||
+			known[r.schema+" "+r.key] || p.Entries[progressID(r)].Learned {
+			continue
+		}
+		d := ""
+		if r.schema != "" {
+			m := lookupSchema(r.schema).meta[r.key]
+			d = cmp.Or(m.description, m.summary)
+		}
+		if d == "" {
+			rest = append(rest, r)
+		} else {
+			described, about = append(described, r), append(about, d)
+		}
+	}
+	y, m, d := day.Date()
+	rnd := rand.New(rand.NewPCG(uint64(y*10000+int(m)*100+d), 0))
+	switch {
+	case len(described) > 0:
+		i := rnd.IntN(len(described))
+		return described[i], about[i], true
+	case len(rest) > 0:
+		return rest[rnd.IntN(len(rest))], "", true
+	}
+	return row{}, "", false
+}
+
+func runTip([]string) error {
+	p, err := loadProgress()
+	if err != nil {
+		return err
+	}
+	lbl := labels("text")
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return err
+	}
+	sortRows(rows) // the same draw for the same day
+	r, about, ok := tipOf(rows, p, time.Now())
+	if !ok {
+		return fmt.Errorf("tip: no shortcut left to suggest")
+	}
+	what := r.app + ": " + r.action
+	if tipNotify {
+		return notify("Shortcut of the day: "+r.accel, strings.TrimSpace(what+"\n"+about))
+	}
+	fmt.Printf("%s  %s\n", r.accel, what)
+	for _, l := range wrapTo(about, 72) {
+		fmt.Println("  " + l)
+	}
+	return nil
+}
@@ -0,0 +1,206 @@
+package shortcuts
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+/*───────────── resolver verification ────────────
+
+Resolve every chord with each strategy and list
+where they disagree.  The score is the share of
+observed contested chords (two or more claimants)
+on which the gschema prediction matches what was
+observed; chords never observed are counted apart,
+as there is nothing to check them against.
+*/
+
+func init() {
+	commands["verify-resolver"] = command{
+		help:  "compare gschema predictions with observed runtime winners",
+		flags: collectFlags,
+		run:   runVerifyResolver,
+	}
+	commands["observe"] = command{
+		help:  "record which claimant fires on a chord, for -resolver runtime",
+		flags: collectFlags,
+		run:   runObserve,
+		args:  accelArgs,
+	}
+}
+
+/*───────────────── observations ─────────────────
+
+observe ACCEL lists the chord's claimants; the
+user presses it and says which one happened (or
+gives its number as a second argument).  That goes
+to observed.json, replacing an earlier observation
+of the chord.
+*/
+
+func readObservations() ([]observation, error) {
+	data, err := os.ReadFile(observedPath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var obs []observation
+	if err := json.Unmarshal(data, &obs); err != nil {
+		return nil, fmt.Errorf("%s: %w", observedPath(), err)
+	}
+	return obs, nil
+}
+
+func runObserve(args []string) error {
+	if len(args) < 1 || len(args) > 2 {
+		return errors.New("usage: observe ACCEL [N]")
+	}
+	a, ok := parseAccel(args[0])
+	if !ok {
+		return fmt.Errorf("not an accelerator: %q", args[0])
+	}
+	won, err := resolveWith("gschema", labels("text"))
+	if err != nil {
+		return err
+	}
+	w, ok := won[a.spec()]
+	if !ok {
+		return fmt.Errorf("%s: nothing is bound to it", a.spec())
+	}
+	var cands []row
+	for _, c := range append([]row{w}, w.lost...) {
+		if c.schema != "" {
+			cands = append(cands, c)
+		}
+	}
+	if len(cands) == 0 {
+		return fmt.Errorf("%s: no gsettings key claims it", a.spec())
+	}
+
+	answer := ""
+	if len(args) == 2 {
+		answer = args[1]
+	} else {
+		fmt.Printf("Press %s, then say which of these happened:\n", w.accel)
+		for i, c := range cands {
+			fmt.Printf("  %d  %s: %s  (%s %s)\n", i+1, c.app, c.action, c.schema, c.key)
+		}
+		fmt.Print("> ")
+		sc := bufio.NewScanner(os.Stdin)
+		sc.Scan()
+		answer = strings.TrimSpace(sc.Text())
+	}
+	n, err := strconv.Atoi(answer)
+	if err != nil || n < 1 || n > len(cands) {
+		return fmt.Errorf("want a number from 1 to %d, got %q", len(cands), answer)
+	}
+	fired := cands[n-1]
+
+	obs, err := readObservations()
+	if err != nil {
+		return err
+	}
+	obs = slices.DeleteFunc(obs, func(o observation) bool {
+		b, ok := parseAccel(o.Accel)
+		return ok && b.spec() == a.spec()
+	})
+	obs = append(obs, observation{a.spec(), fired.schema, fired.key})
+	var data bytes.Buffer
+	enc := json.NewEncoder(&data)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(obs); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(stateDir(), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(observedPath(), data.Bytes(), 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("%s fires %s: %s (%d observed)\n", w.accel, fired.app, fired.action, len(obs))
+	return nil
+}
+
+func resolveWith(name string, lbl map[string]string) (map[string]row, error) {
+	saved := resolverName
+	resolverName = name
+	defer func() { resolverName = saved }()
+	rows, _, err := collect(lbl)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]row{}
+	for _, r := range rows {
+		if a, ok := parseAccel(r.spec); ok {
+			out[a.spec()] = r
+		}
+	}
+	return out, nil
+}
+
+func runVerifyResolver([]string) error {
+	lbl := labels("text")
+	theory, err := resolveWith("gschema", lbl)
+	if err != nil {
+		return err
+	}
+	practice, err := resolveWith("runtime", lbl)
+	if err != nil {
+		return err
+	}
+	seen, _ := loadObservations()
+
+	var differ []string
+	contested, observed := 0, 0
+	for k, t := range theory {
+		if len(t.lost) == 0 {
+			continue
+		}
+		contested++
+		if _, ok := seen[k]; !ok {
+			continue
+		}
+		observed++
+		p := practice[k]
+		if p.schema != t.schema || p.key != t.key || p.action != t.action {
+			differ = append(differ, k)
+		}
+	}
+	sort.Strings(differ)
+
+	if len(differ) > 0 {
+		line := strings.Repeat("─", 100)
+		fmt.Println(line)
+		fmt.Printf("%-24s %-37s %s\n", "Shortcut", "gschema predicts", "runtime observed")
+		fmt.Println(line)
+		for _, k := range differ {
+			t, p := theory[k], practice[k]
+			fmt.Printf("%-24s %-37s %s\n", t.accel, t.app+": "+t.action, p.app+": "+p.action)
+		}
+		fmt.Println()
+	}
+	switch {
+	case contested == 0:
+		fmt.Println("No contested chords; nothing to verify.")
+		return nil
+	case observed == 0:
+		fmt.Printf("None of %d contested chords has been observed yet (see observed.json).\n", contested)
+		return nil
+	}
+	agree := observed - len(differ)
+	fmt.Printf("confidence: %d/%d observed contested chords agree (%.0f%%); %d never observed\n",
+		agree, observed, 100*float64(agree)/float64(observed), contested-observed)
+	return nil
+}
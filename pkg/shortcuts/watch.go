@@ -0,0 +1,128 @@
+package shortcuts
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+/*──────────────────── list -watch ────────────────────
+
+Keeps the table up while shortcuts are changed
+elsewhere (Settings, gsettings, dconf-editor).
+`dconf watch /` says when anything changes; without
+dconf, or with -host, the settings are read again
+every watchInterval.  The table is collected anew
+on each change and redrawn only if it differs.
+*/
+
+const (
+	watchInterval = 2 * time.Second
+	watchSettle   = 150 * time.Millisecond // one change often writes several keys
+)
+
+func watchList(rd Renderer) error {
+	if fromDump() || defaultsOnly {
+		return fmt.Errorf("-watch needs live settings, not -from-dump or -defaults")
+	}
+	ctx, cancel := context.WithCancel(runCtx)
+	defer cancel()
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, os.Interrupt)
+	defer signal.Stop(stop)
+
+	tty := readline.IsTerminal(int(os.Stdout.Fd()))
+	changed := settingsChanges(ctx)
+	var last []byte
+	for {
+		forgetSettings()
+		l, err := buildListing()
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := rd.Render(ctx, &buf, l.sections); err != nil {
+			return err
+		}
+		if !bytes.Equal(buf.Bytes(), last) {
+			last = buf.Bytes()
+			if tty {
+				fmt.Print(escClear)
+			} else if last != nil {
+				fmt.Println()
+			}
+			os.Stdout.Write(last)
+			if tty {
+				fmt.Printf("\nwatching, updated %s · Ctrl-C stops", time.Now().Format("15:04:05"))
+			}
+		}
+		select {
+		case <-stop:
+			if tty {
+				fmt.Println()
+			}
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+// settingsChanges signals, at most once per burst, that settings may
+// have changed.
+func settingsChanges(ctx context.Context) <-chan struct{} {
+	out := make(chan struct{}, 1)
+	notify := func() {
+		select {
+		case out <- struct{}{}:
+		default:
+		}
+	}
+	poll := func() {
+		t := time.NewTicker(watchInterval)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+				notify()
+			}
+		}
+	}
+	if _, err := exec.LookPath("dconf"); err != nil || remote() {
+		go poll()
+		return out
+	}
+	go func() {
+		cmd := exec.CommandContext(ctx, "dconf", "watch", "/")
+		if stdout, err := cmd.StdoutPipe(); err == nil && cmd.Start() == nil {
+			sc := bufio.NewScanner(stdout) // "/path/key", then its value
+			for sc.Scan() {
+				if strings.HasPrefix(sc.Text(), "/") {
+					time.Sleep(watchSettle)
+					notify()
+				}
+			}
+			cmd.Wait()
+		}
+		poll() // dconf watch failed or quit
+	}()
+	return out
+}
+
+// forgetSettings drops what earlier collections read of dconf, so the
+// next reads it again; schemas do not change under a running session.
+func forgetSettings() {
+	clear(dconfCache)
+	lockCache = nil
+}
@@ -0,0 +1,102 @@
+package shortcuts
+
+import (
+	_ "embed"
+	"fmt"
+	"io"
+	"strings"
+)
+
+/*──────── export -format ahk-doc ────────
+
+A reference sheet for people coming from Windows:
+each GNOME binding next to the closest Windows
+shortcut (windows_shortcuts.tsv), written in both
+words and AutoHotkey hotkey syntax so it can seed a
+remapping script.
+*/
+
+//go:embed windows_shortcuts.tsv
+var windowsShortcutsTSV string
+
+func init() {
+	systems["windows"] = system{"Windows", "windows_shortcuts.tsv", windowsShortcutsTSV, kbPC}
+	exporters["ahk-doc"] = exporter{"GNOME next to the closest Windows shortcut", writeAHKDoc}
+}
+
+var ahkMods = []struct {
+	bit  int
+	name string
+}{{modSuper, "#"}, {modCtrl, "^"}, {modAlt, "!"}, {modShift, "+"}}
+
+// ahkKeys are AutoHotkey's key names; ` escapes a character that
+// would otherwise read as a modifier (+) or a comment (;).
+var ahkKeys = map[string]string{
+	"Return": "Enter", "KP_Enter": "NumpadEnter", "Escape": "Esc", "space": "Space",
+	"BackSpace": "Backspace", "Page_Up": "PgUp", "Page_Down": "PgDn",
+	"Prior": "PgUp", "Next": "PgDn", "Print": "PrintScreen", "grave": "``",
+	"plus": "`+", "KP_Add": "NumpadAdd", "minus": "-", "equal": "=", "period": ".", "comma": ",",
+	"slash": "/", "backslash": "\\", "semicolon": "`;", "apostrophe": "'",
+	"bracketleft": "[", "bracketright": "]",
+	"XF86AudioRaiseVolume": "Volume_Up", "XF86AudioLowerVolume": "Volume_Down",
+	"XF86AudioMute": "Volume_Mute", "XF86AudioPlay": "Media_Play_Pause",
+	"XF86AudioNext": "Media_Next", "XF86AudioPrev": "Media_Prev", "XF86AudioStop": "Media_Stop",
+}
+
+// ahkHotkey turns "<Super><Shift>s" into "#+s"; a bare <Super> is LWin.
+func ahkHotkey(spec string) (string, bool) {
+	a, ok := parseAccel(spec)
+	if !ok || a.mods&(modHyper|modMeta) != 0 || strings.HasPrefix(a.key, "Keycode_") {
+		return "", false
+	}
+	if a.key == "" {
+		return "LWin", a.mods == modSuper
+	}
+	var b strings.Builder
+	for _, m := range ahkMods {
+		if a.mods&m.bit != 0 {
+			b.WriteString(m.name)
+		}
+	}
+	key := a.key
+	if k, ok := ahkKeys[key]; ok {
+		key = k
+	}
+	b.WriteString(key)
+	return b.String(), true
+}
+
+func writeAHKDoc(w io.Writer, rows []row, lbl map[string]string) error {
+	table := systems["windows"].table()
+	winLbl := systemLabels(lbl, kbPC)
+	var lines [][5]string // GNOME chord, action; Windows chord, action; hotkey
+	var none []string
+	width := [4]int{len("GNOME"), 0, len("Windows"), 0}
+	for _, r := range rows {
+		e, ok := lookupEquivalent(table, r)
+		win, ok2 := fmtKey(e.spec, winLbl)
+		hk, ok3 := ahkHotkey(e.spec)
+		if !ok || !ok2 || !ok3 {
+			none = append(none, fmt.Sprintf(";   %s  %s: %s", r.accel, r.app, r.action))
+			continue
+		}
+		l := [5]string{r.accel, r.action, win, e.action, hk}
+		for i := range width {
+			width[i] = max(width[i], dispWidth(l[i]))
+		}
+		lines = append(lines, l)
+	}
+	pad := func(s string, n int) string { return s + strings.Repeat(" ", n-dispWidth(s)) }
+	fmt.Fprintln(w, "; Generated by `gnome-shortcuts export -format ahk-doc`: each GNOME")
+	fmt.Fprintln(w, "; shortcut next to the closest Windows one and its AutoHotkey hotkey.")
+	fmt.Fprintln(w, ";")
+	fmt.Fprintf(w, "; %s  %s  AutoHotkey\n", pad("GNOME", width[0]+width[1]+2), pad("Windows", width[2]+width[3]+2))
+	for _, l := range lines {
+		fmt.Fprintf(w, "; %s  %s  %s  %s  %s\n", pad(l[0], width[0]), pad(l[1], width[1]),
+			pad(l[2], width[2]), pad(l[3], width[3]), l[4])
+	}
+	if len(none) > 0 {
+		fmt.Fprintf(w, ";\n; No Windows equivalent:\n%s\n", strings.Join(none, "\n"))
+	}
+	return nil
+}
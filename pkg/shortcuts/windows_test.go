@@ -0,0 +1,24 @@
+package shortcuts
+
+import "testing"
+
+func TestAHKHotkey(t *testing.T) {
+	for _, c := range []struct{ spec, want string }{
+		{"<Super><Shift>s", "#+s"},
+		{"<Control>plus", "^`+"},
+		{"<Control>KP_Add", "^NumpadAdd"},
+		{"<Alt>semicolon", "!`;"},
+		{"<Super>grave", "#``"},
+		{"<Super>Return", "#Enter"},
+		{"<Super>", "LWin"},
+	} {
+		if got, ok := ahkHotkey(c.spec); !ok || got != c.want {
+			t.Errorf("ahkHotkey(%s) = %q, %v, want %q", c.spec, got, ok, c.want)
+		}
+	}
+	for _, spec := range []string{"<Hyper>a", "<Super><Shift>", "Keycode_42"} {
+		if got, ok := ahkHotkey(spec); ok {
+			t.Errorf("ahkHotkey(%s) = %q, want none", spec, got)
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package shortcuts
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+/*─────────────────── xbindkeys ───────────────────
+
+~/.xbindkeysrc pairs a quoted command with the key
+line below it:
+
+	"amixer set Master 5%+"
+	  control+shift + Up
+	"xterm"
+	  m:0x40 + c:28
+
+On X11 it grabs keys beside GNOME, and a chord both
+grab only reaches the first, so its bindings are
+claimed like custom shortcuts.  Wayland sessions
+never give it the keys.  The Guile .scm form is
+not read.
+*/
+
+// xbindkeysMods are its modifier names, lower-cased.
+var xbindkeysMods = map[string]string{
+	"control": "<Control>", "shift": "<Shift>", "alt": "<Alt>", "mod1": "<Alt>",
+	"mod4": "<Super>", "super": "<Super>", "mod5": "", "mod2": "", "release": "",
+}
+
+// xbindkeysMask decodes m:0x… (Shift 0x1, Control 0x4, Mod1 0x8, Mod4 0x40).
+func xbindkeysMask(s string) string {
+	n, err := strconv.ParseUint(strings.TrimPrefix(s, "0x"), 16, 16)
+	if err != nil {
+		return ""
+	}
+	var b strings.Builder
+	for _, m := range []struct {
+		bit uint64
+		tok string
+	}{{0x4, "<Control>"}, {0x1, "<Shift>"}, {0x8, "<Alt>"}, {0x40, "<Super>"}} {
+		if n&m.bit != 0 {
+			b.WriteString(m.tok)
+		}
+	}
+	return b.String()
+}
+
+// xbindkeysSpec turns "control+shift + q" or "m:0x4 + c:24" into a GTK spec.
+func xbindkeysSpec(line string) (string, bool) {
+	var mods, key string
+	for _, t := range strings.Split(line, "+") {
+		t = strings.TrimSpace(t)
+		lt := strings.ToLower(t)
+		switch {
+		case t == "":
+		case strings.HasPrefix(lt, "m:"):
+			mods += xbindkeysMask(t[2:])
+		case strings.HasPrefix(lt, "c:"):
+			key = "Keycode_" + t[2:]
+		case strings.HasPrefix(lt, "b:"): // mouse buttons are not chords
+			return "", false
+		default:
+			if m, ok := xbindkeysMods[lt]; ok {
+				mods += m
+			} else {
+				key = t
+			}
+		}
+	}
+	return mods + key, key != ""
+}
+
+func xbindkeysRows(lbl map[string]string) []row {
+	keep, tag := backendFilter("x11")
+	if !keep || remote() || defaultsOnly || fromDump() {
+		return nil
+	}
+	home, _ := os.UserHomeDir()
+	path := filepath.Join(home, ".xbindkeysrc")
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var rows []row
+	cmd := ""
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		l := strings.TrimSpace(sc.Text())
+		switch {
+		case l == "" || l[0] == '#':
+		case l[0] == '"':
+			cmd = strings.Trim(l, `"`)
+		case cmd != "":
+			if spec, ok := xbindkeysSpec(l); ok {
+				if acc, ok := fmtAccel(spec, lbl); ok {
+					rows = append(rows, row{accel: acc, app: "xbindkeys", action: cmd + tag,
+						rank: 3, order: n, spec: spec, src: fmt.Sprintf("%s:%d", path, n)})
+				}
+			}
+			cmd = "" // a second key line is xbindkeys -k's readable echo
+		}
+	}
+	return rows
+}
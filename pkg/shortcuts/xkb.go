@@ -0,0 +1,145 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+/*──────────────── XKB symbol maps ───────────────
+
+Just enough of the xkb_symbols language to know
+which keysyms each physical key produces on a layout:
+sections, nested includes and `key <XXXX> { [ … ] }`.
+Level 0/1 are plain/Shift, 2/3 need AltGr.
+*/
+
+type keymap map[string][]string // key code (AD11) → keysym per level
+
+func xkbRoot() string {
+	if d := os.Getenv("XKB_CONFIG_ROOT"); d != "" {
+		return d
+	}
+	return "/usr/share/X11/xkb"
+}
+
+var (
+	xkbSectionRE = regexp.MustCompile(`(?m)^((?:[a-z_]+\s+)*)xkb_symbols\s+"([^"]+)"\s*\{`)
+	xkbStmtRE    = regexp.MustCompile(`(?s)(?:include|augment|replace|override)\s+"([^"]+)"|key\s+<(\w+)>\s*\{([^}]*)\}`)
+	xkbGroupRE   = regexp.MustCompile(`\w+\[Group\d\]`)
+	xkbLevelsRE  = regexp.MustCompile(`\[([^\]]*)\]`)
+	xkbCommentRE = regexp.MustCompile(`//[^\n]*`)
+)
+
+// loadKeymap resolves "de", "de(nodeadkeys)" or GNOME's "de+nodeadkeys".
+func loadKeymap(layout string) (keymap, bool) {
+	if i := strings.IndexByte(layout, '+'); i >= 0 {
+		layout = layout[:i] + "(" + layout[i+1:] + ")"
+	}
+	km := keymap{}
+	return km, includeSymbols(km, layout, 0)
+}
+
+func includeSymbols(km keymap, ref string, depth int) bool {
+	if depth > 8 {
+		return false
+	}
+	ok := false
+	for _, part := range strings.FieldsFunc(ref, func(r rune) bool { return r == '+' || r == '|' }) {
+		file, section := part, ""
+		if i := strings.IndexByte(part, '('); i >= 0 {
+			file, section = part[:i], strings.TrimSuffix(part[i+1:], ")")
+		}
+		if i := strings.IndexByte(file, ':'); i >= 0 { // "us:2" group suffix
+			file = file[:i]
+		}
+		data, err := os.ReadFile(filepath.Join(xkbRoot(), "symbols", file))
+		if err != nil {
+			continue
+		}
+		if xkbSeen != nil {
+			xkbSeen[file] = true
+		}
+		body, found := xkbSection(xkbCommentRE.ReplaceAllString(string(data), ""), section)
+		if !found {
+			continue
+		}
+		ok = true
+		for _, m := range xkbStmtRE.FindAllStringSubmatch(body, -1) {
+			if m[1] != "" {
+				includeSymbols(km, m[1], depth+1)
+				continue
+			}
+			inner := xkbGroupRE.ReplaceAllString(m[3], "")
+			lv := xkbLevelsRE.FindStringSubmatch(inner)
+			if lv == nil {
+				continue
+			}
+			var syms []string
+			for _, s := range strings.Split(lv[1], ",") {
+				syms = append(syms, strings.TrimSpace(s))
+			}
+			km[m[2]] = syms
+		}
+	}
+	return ok
+}
+
+// xkbSection returns the named section, or the one marked default
+// (falling back to the first) when name is empty.
+func xkbSection(data, name string) (string, bool) {
+	secs := xkbSectionRE.FindAllStringSubmatchIndex(data, -1)
+	for _, s := range secs {
+		flags, sec := data[s[2]:s[3]], data[s[4]:s[5]]
+		if name == "" && strings.Contains(flags, "default") || name != "" && sec == name {
+			return xkbBody(data[s[1]:]), true
+		}
+	}
+	if name == "" && len(secs) > 0 {
+		return xkbBody(data[secs[0][1]:]), true
+	}
+	return "", false
+}
+
+// xkbBody cuts a section at the brace closing it; a key spread over
+// several lines ends in "};" too.
+func xkbBody(data string) string {
+	depth := 1
+	for i, c := range data {
+		switch c {
+		case '{':
+			depth++
+		case '}':
+			if depth--; depth == 0 {
+				return data[:i]
+			}
+		}
+	}
+	return data
+}
+
+// find returns the first key and level producing sym, level -1 if none.
+func (km keymap) find(sym string) (code string, level int) {
+	level = -1
+	for c, syms := range km {
+		for l, s := range syms {
+			if s == sym && (level < 0 || l < level || l == level && c < code) {
+				code, level = c, l
+			}
+		}
+	}
+	return code, level
+}
+
+// activeLayout is the first XKB input source configured in GNOME.
+func activeLayout() string {
+	v, _ := parseGVText(gsettingsGet("org.gnome.desktop.input-sources", "sources"))
+	srcs, _ := v.([]any)
+	for _, src := range srcs {
+		if t := gvStrings(src); len(t) == 2 && t[0] == "xkb" {
+			return t[1]
+		}
+	}
+	return "us"
+}
@@ -0,0 +1,46 @@
+package shortcuts
+
+import "testing"
+
+func TestXkbSection(t *testing.T) {
+	data := `default partial alphanumeric_keys
+xkb_symbols "basic" {
+    key <AD01> { [ q, Q ] };
+    key <AC10> {
+        type[Group1] = "FOUR_LEVEL",
+        [ semicolon, colon ]
+    };
+    key <AB01> { [ z, Z ] };
+};
+
+partial alphanumeric_keys
+xkb_symbols "other" {
+    key <AD01> { [ a, A ] };
+};
+`
+	for _, c := range []struct {
+		name, want string
+		ok         bool
+	}{
+		{"", "AB01", true},      // the default, past the multi-line key's "};"
+		{"basic", "AB01", true}, // by name
+		{"other", "AD01", true},
+		{"missing", "", false},
+	} {
+		body, ok := xkbSection(data, c.name)
+		if ok != c.ok {
+			t.Errorf("xkbSection(%q): ok = %v", c.name, ok)
+			continue
+		}
+		km := keymap{}
+		for _, m := range xkbStmtRE.FindAllStringSubmatch(body, -1) {
+			km[m[2]] = nil
+		}
+		if _, has := km[c.want]; ok && !has {
+			t.Errorf("xkbSection(%q) = %q, missing <%s>", c.name, body, c.want)
+		}
+	}
+	if body, _ := xkbSection(data, "other"); len(body) > 40 {
+		t.Errorf("xkbSection(other) ran past its section: %q", body)
+	}
+}
@@ -0,0 +1,112 @@
+package shortcuts
+
+import "strings"
+
+/*───────────────── XKB options ──────────────────
+
+org.gnome.desktop.input-sources xkb-options can
+move modifiers to other physical keys (labels must
+follow) and make XKB itself consume chords for
+layout switching or Compose before GNOME ever
+sees them.
+*/
+
+func xkbOptions() []string {
+	return gvTextStrings(gsettingsGet("org.gnome.desktop.input-sources", "xkb-options"))
+}
+
+// applyXkbOptions rewrites modifier labels so they name the key the
+// user physically presses.
+func applyXkbOptions(lbl map[string]string, opts []string) {
+	alias := func(tok, key string) {
+		if !strings.Contains(lbl[tok], key) {
+			lbl[tok] += " (" + key + ")"
+		}
+	}
+	for _, o := range opts {
+		switch o {
+		case "altwin:swap_alt_win", "altwin:swap_lalt_lwin":
+			lbl["<Alt>"], lbl["<Super>"] = lbl["<Super>"], lbl["<Alt>"]
+		case "ctrl:swap_lwin_lctl", "ctrl:swap_rwin_rctl":
+			lbl["<Control>"], lbl["<Super>"] = lbl["<Super>"], lbl["<Control>"]
+		case "caps:super":
+			alias("<Super>", "Caps")
+		case "altwin:ctrl_win": // the Win keys send Control
+			alias("<Control>", "Win")
+		case "ctrl:nocaps", "ctrl:swapcaps":
+			alias("<Control>", "Caps")
+		case "caps:hyper":
+			alias("<Hyper>", "Caps")
+		}
+	}
+	// <Primary> etc. print like <Control>
+	for _, t := range []string{"<Primary>", "<Ctrl>"} {
+		lbl[t] = lbl["<Control>"]
+	}
+}
+
+// xkbGroupToggles are the grp: options whose chord switches layout
+// inside XKB, shadowing any GNOME binding on the same chord.
+var xkbGroupToggles = map[string]string{
+	"grp:alt_shift_toggle":  "<Alt><Shift>",
+	"grp:ctrl_shift_toggle": "<Control><Shift>",
+	"grp:ctrl_alt_toggle":   "<Control><Alt>",
+	"grp:alt_space_toggle":  "<Alt>space",
+	"grp:win_space_toggle":  "<Super>space",
+	"grp:ctrl_space_toggle": "<Control>space",
+	"grp:alt_caps_toggle":   "<Alt>Caps_Lock",
+	"grp:shift_caps_toggle": "<Shift>Caps_Lock",
+	"grp:caps_toggle":       "Caps_Lock",
+	"grp:menu_toggle":       "Menu",
+	"grp:lalt_toggle":       "Alt_L",
+	"grp:ralt_toggle":       "Alt_R",
+	"grp:lctrl_toggle":      "Control_L",
+	"grp:rctrl_toggle":      "Control_R",
+	"grp:lshift_toggle":     "Shift_L",
+	"grp:rshift_toggle":     "Shift_R",
+	"grp:lwin_toggle":       "Super_L",
+	"grp:rwin_toggle":       "Super_R",
+	"grp:sclk_toggle":       "Scroll_Lock",
+}
+
+// xkbComposeKeys are the compose: options; the key they name stops
+// doing anything else.
+var xkbComposeKeys = map[string]string{
+	"compose:ralt":  "Alt_R",
+	"compose:lwin":  "Super_L",
+	"compose:rwin":  "Super_R",
+	"compose:menu":  "Menu",
+	"compose:lctrl": "Control_L",
+	"compose:rctrl": "Control_R",
+	"compose:caps":  "Caps_Lock",
+	"compose:102":   "less",
+	"compose:paus":  "Pause",
+	"compose:prsc":  "Print",
+	"compose:sclk":  "Scroll_Lock",
+	"compose:ins":   "Insert",
+}
+
+// xkbOptionKey is the action and key option o makes XKB consume, or
+// spec "" for an option that takes no key.
+func xkbOptionKey(o string) (action, spec string) {
+	if spec := xkbGroupToggles[o]; spec != "" {
+		return "Switch Layout", spec
+	}
+	return "Compose Key", xkbComposeKeys[o]
+}
+
+// xkbKeyRows are the chords XKB consumes itself: layout toggles and
+// the Compose key.  Their keySpecs are every option set, so that
+// unbindCmd drops only the one that lost.
+func xkbKeyRows(lbl map[string]string) []row {
+	var out []row
+	opts := xkbOptions()
+	for _, o := range opts {
+		action, spec := xkbOptionKey(o)
+		if acc, ok := fmtAccel(spec, lbl); ok && spec != "" {
+			out = append(out, row{accel: acc, app: "Keyboard (XKB)", action: action,
+				rank: -2, spec: spec, schema: "org.gnome.desktop.input-sources", key: "xkb-options", keySpecs: opts})
+		}
+	}
+	return out
+}
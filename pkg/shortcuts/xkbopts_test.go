@@ -0,0 +1,86 @@
+package shortcuts
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestApplyXkbOptions(t *testing.T) {
+	for _, c := range []struct {
+		opt                 string
+		ctrl, alt, super, h string
+	}{
+		{"", "Ctrl", "Alt", "Win", ""},
+		{"altwin:swap_alt_win", "Ctrl", "Win", "Alt", ""},
+		{"altwin:swap_lalt_lwin", "Ctrl", "Win", "Alt", ""},
+		{"ctrl:swap_lwin_lctl", "Win", "Alt", "Ctrl", ""},
+		{"ctrl:swap_rwin_rctl", "Win", "Alt", "Ctrl", ""},
+		{"caps:super", "Ctrl", "Alt", "Win (Caps)", ""},
+		{"altwin:ctrl_win", "Ctrl (Win)", "Alt", "Win", ""},
+		{"ctrl:nocaps", "Ctrl (Caps)", "Alt", "Win", ""},
+		{"ctrl:swapcaps", "Ctrl (Caps)", "Alt", "Win", ""},
+		{"caps:hyper", "Ctrl", "Alt", "Win", " (Caps)"},
+		{"grp:alt_shift_toggle", "Ctrl", "Alt", "Win", ""},
+	} {
+		lbl := map[string]string{"<Control>": "Ctrl", "<Alt>": "Alt", "<Super>": "Win"}
+		applyXkbOptions(lbl, []string{c.opt})
+		if lbl["<Control>"] != c.ctrl || lbl["<Alt>"] != c.alt || lbl["<Super>"] != c.super || lbl["<Hyper>"] != c.h {
+			t.Errorf("%q: Control %q, Alt %q, Super %q, Hyper %q", c.opt, lbl["<Control>"], lbl["<Alt>"], lbl["<Super>"], lbl["<Hyper>"])
+		}
+		if lbl["<Primary>"] != lbl["<Control>"] || lbl["<Ctrl>"] != lbl["<Control>"] {
+			t.Errorf("%q: <Primary> %q and <Ctrl> %q should follow <Control>", c.opt, lbl["<Primary>"], lbl["<Ctrl>"])
+		}
+	}
+}
+
+// TestXkbOptionConflict has compose:menu and grp:menu_toggle both take
+// Menu: unbinding the loser keeps every other option.
+func TestXkbOptionConflict(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "dump.txt")
+	dump := "org.gnome.desktop.input-sources xkb-options ['caps:super', 'compose:menu', 'grp:menu_toggle']\n"
+	if err := os.WriteFile(file, []byte(dump), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	savedDump := dumpOpt
+	t.Cleanup(func() { dumpOpt = savedDump })
+	d, err := readDump(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dumpOpt = d
+
+	lbl := labelsFor(kbPC, "text")
+	res, err := activeResolver()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chosen := map[string]row{}
+	for _, r := range xkbKeyRows(lbl) {
+		claimChord(chosen, res, r)
+	}
+	cs := findConflicts(sortedValues(chosen), lbl)
+	if len(cs) != 1 || len(cs[0].Suggest) != 1 {
+		t.Fatalf("conflicts = %+v, want one with one suggestion", cs)
+	}
+	keep := "'caps:super', 'grp:menu_toggle'"
+	if cs[0].Losers[0].Action == "Switch Layout" {
+		keep = "'caps:super', 'compose:menu'"
+	}
+	want := `gsettings set org.gnome.desktop.input-sources xkb-options "[` + keep + `]"`
+	if got := cs[0].Suggest[0]; got != want {
+		t.Errorf("suggestion = %s\nwant %s", got, want)
+	}
+	if strings.Contains(cs[0].Suggest[0], "@as []") {
+		t.Error("suggestion clears every option")
+	}
+}
+
+func sortedValues(m map[string]row) []row {
+	var out []row
+	for _, k := range sortedKeys(m) {
+		out = append(out, m[k])
+	}
+	return out
+}